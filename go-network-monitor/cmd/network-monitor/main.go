@@ -1,26 +1,74 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"os"
 	
 	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/config"
 	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/monitor"
 )
 
 func main() {
-	// Load configuration (supports both root and non-root users now)
-	cfg := config.DefaultConfig()
-	cfg.LoadFromEnv()
-	cfg.ParseFlags()
+	// Load configuration (supports both root and non-root users now);
+	// precedence is config file < environment < flags
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Failed to load configuration: %v", err)
+		os.Exit(monitor.ExitConfigError)
+	}
+	
+	// Validation only: show what would be used and report problems via the exit code
+	if cfg.ValidateOnly {
+		if err := cfg.WriteYAML(os.Stdout); err != nil {
+			log.Fatalf("Failed to print configuration: %v", err)
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+			os.Exit(monitor.ExitConfigError)
+		}
+		for _, warning := range monitor.InterfaceTypeWarnings(cfg) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		fmt.Fprintln(os.Stderr, "Configuration OK")
+		os.Exit(0)
+	}
+	
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration:\n%v", err)
+		os.Exit(monitor.ExitConfigError)
+	}
 	
 	// Create and run monitor
 	mon, err := monitor.New(cfg)
 	if err != nil {
-		log.Fatalf("Failed to create monitor: %v", err)
+		log.Printf("Failed to create monitor: %v", err)
+		os.Exit(monitor.ExitConfigError)
+	}
+	
+	// Dry run: show what would be monitored without taking the lock
+	if cfg.DryRun {
+		mon.DryRun()
+		mon.Close()
+		os.Exit(0)
+	}
+	
+	// One-shot check: report readiness via the exit code
+	if cfg.Once {
+		ready := mon.RunOnce()
+		mon.Close()
+		if !ready {
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
-	defer mon.Close()
 	
-	if err := mon.Run(); err != nil {
-		log.Fatalf("Monitor failed: %v", err)
+	// Deferred calls don't run across os.Exit, so close explicitly
+	err = mon.Run()
+	mon.Close()
+	if err != nil && !errors.Is(err, monitor.ErrInterrupted) {
+		log.Printf("Monitor failed: %v", err)
 	}
+	os.Exit(monitor.ExitCode(err))
 }
\ No newline at end of file
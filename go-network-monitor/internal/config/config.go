@@ -3,10 +3,19 @@ package config
 import (
 	"flag"
 	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/network"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/readiness"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/version"
 )
 
 // Config holds all configuration options for the network monitor
@@ -15,31 +24,339 @@ type Config struct {
 	TotalTimeout     time.Duration
 	RunAfterSuccess  time.Duration
 	SleepInterval    time.Duration
+	SleepJitter      int            // Random +/- percentage applied to each sleep interval, decorrelating a fleet (0 = none)
 	PingTimeout      time.Duration
 	DNSTimeout       time.Duration
+	SystemdTimeout   time.Duration  // Per-query D-Bus/systemctl timeout
+	NMTimeout        time.Duration  // Per-command systemctl/nmcli timeout for NetworkManager connectivity
+	ShutdownTimeout  time.Duration  // Grace period for in-flight checks after SIGTERM before forcing exit
+	
+	// Consecutive results required before a check changes state
+	FailureThreshold  int
+	RecoveryThreshold int                        // Passing results before a failed check is up again (0 = FailureThreshold)
+	CheckThresholds   map[string]CheckThreshold  // Per-check overrides keyed by check name
+	
+	// A check whose result changes more than FlapThreshold times within
+	// FlapWindow is reported as flapping (0 = disabled)
+	FlapThreshold     int
+	FlapWindow        time.Duration
+	
+	// Checks that are logged but don't block readiness (e.g. "nm_connectivity")
+	AdvisoryChecks   []string
+	ReadinessQuorum  string  // How many required checks must pass: "all" or a number
+	ReadinessExpr    string  // Boolean expression over check names replacing the default rule (empty = none)
 	
 	// Operating mode
 	BlockingMode     bool
+	Watch            bool  // Re-run link checks on netlink events in addition to the timer
+	EventDriven      bool  // Re-run all checks on netlink events and only poll at EventBackstop
+	EventBackstop    time.Duration  // Polling interval in event-driven mode
+	Once             bool  // Run a single check cycle, print the results and exit
+	ValidateOnly     bool  // Print the effective configuration, validate it and exit
+	DryRun           bool  // Print what would be monitored and exit without running checks
+	Output           string  // Result format for -once: "text" or "json"
 	
 	// Interface monitoring
 	InterfaceTypes      []string
-	RequiredInterfaces  []string  // Specific interfaces that must be up (empty = any interface sufficient)
+	RequiredInterfaces  []string  // Interfaces, glob patterns or MAC addresses that must be up (empty = any interface sufficient)
+	ExcludedInterfaces  []string  // Interfaces or glob patterns never monitored (e.g. "docker*")
+	IgnoreSRIOVVFs      bool      // Never monitor SR-IOV virtual functions, e.g. ones reserved for guests
+	MinInterfacesUp     int       // Monitored interfaces that must be up (0 = any one, or the required interfaces)
+	ExpectedMTU         int             // MTU every monitored interface must have (0 = report only)
+	InterfaceMTUs       map[string]int  // Per-interface MTU overrides
+	PathMTUProbe        bool            // Ping the gateway with a full-size, unfragmentable packet
+	MinSpeed            int             // Minimum negotiated link speed in Mbps; also rejects half duplex (0 = report only)
+	InterfaceSpeeds     map[string]int  // Per-interface minimum speed overrides
+	BondMinSlaves       int             // Slaves every bond needs up, and aggregated under 802.3ad (0 = any one)
+	BondSlaves          map[string]int  // Per-bond minimum slave overrides
+	LACPPartnerMAC      string             // Switch system MAC every 802.3ad bond must have as LACP partner (empty = any)
+	BondPartnerMACs     map[string]string  // Per-bond expected LACP partner overrides
+	TunnelHandshakeAge  time.Duration   // Oldest acceptable WireGuard handshake for a tunnel to count as up
+	WirelessSSIDs       []string        // SSIDs a wireless interface may be associated with (empty = any)
+	WirelessMinSignal   int             // Weakest acceptable wireless signal in dBm (0 = report only)
+	InterfacePolicies   map[string]InterfacePolicy  // Per-interface readiness rules (config file only)
 	
 	// Network services
 	NetworkServices  []string
+	RequiredServices []string  // Services that must all be active (in addition to the best-effort set)
+	
+	// Gateway connectivity: ping these targets instead of the default gateway when set
+	PingTargets      []string
+	PingPolicy       string  // "all" or "any"
+	PingGateway      bool    // Also ping the default gateway alongside the targets
+	GatewayFamily    string  // Default gateway(s) to test: "ipv4", "ipv6", "either" or "both"
+	GatewayProbe     string  // How the IPv4 gateway is probed: "icmp", "arp", "any" or "all"
+	PingCount        int            // Echo requests sent to each target per check
+	PingMaxLoss      int            // Highest acceptable packet loss percentage per target
+	PingMaxRTT       time.Duration  // Highest acceptable average round-trip time per target (0 = no limit)
+	GatewayMaxRTT    time.Duration  // Highest acceptable average round-trip time to the default gateway (0 = ping_max_rtt)
+	PingThresholds   map[string]PingThreshold  // Per-target overrides keyed by target, or "gateway" (config file only)
 	
 	// DNS resolution
-	ResolverHostname string
+	ResolverHostnames []string
+	DNSQuorum        string         // How many resolver hostnames must resolve: "all", "any" or a number
+	DNSServers       []string       // Query each of these servers directly instead of the system resolver
+	DNSSystemServers bool           // Query each of the system resolver's nameservers directly
+	DNSMaxLatency    time.Duration  // Slowest acceptable resolution of a hostname (0 = no limit)
+	EncryptedDNS     []string       // DoT ("tls://host[:port][#name]") and DoH (https URL) servers that must resolve the hostnames
+	
+	// Routing table used for default route discovery (254 = main, 0 = all tables)
+	RouteTable       int
+	IPv6DefaultRoute bool      // Routing check also requires an IPv6 default route
+	RequiredRoutes   []string  // Routes that must exist, e.g. "10.0.0.0/8 via 10.1.1.1" or "default dev bond0"
+	PolicyTables     []string  // Policy routing tables that must be selected by an ip rule and have a default route
+	
+	// Optional checks
+	CheckDHCP        bool           // Require unexpired DHCP leases on interfaces that use DHCP
+	CheckTimeSync    bool           // Require the system clock to be NTP synchronized
+	CheckNDP         bool           // Require IPv6 neighbor entries and a resolved IPv6 gateway
+	CheckResolved    bool           // Require systemd-resolved to have DNS servers for the default route
+	CheckReverseDNS  bool           // Require PTR records for the host's primary addresses
+	CheckResolvConf  bool           // Require a sane /etc/resolv.conf with reachable nameservers
+	CheckHostname    bool           // Require the hostname to resolve to one of the host's addresses
+	CheckDNSSEC      bool           // Require the resolver to validate DNSSEC
+	DNSSECSignedName string         // Signed name that must resolve with the AD flag
+	DNSSECBogusName  string         // Deliberately mis-signed name that must fail (empty = skip)
+	
+	// TCP endpoints ("host:port") that must all accept a connection
+	TCPChecks        []string
+	TCPTimeout       time.Duration
+	
+	// HTTP(S) endpoints that must answer with an accepted status
+	HTTPChecks       []HTTPCheck
+	HTTPTimeout      time.Duration
+	HTTPStatus       string         // Accepted status code or range, e.g. "200-399"
+	
+	// Captive portal detection; the URL must answer 204 without a redirect
+	CaptivePortal    bool
+	CaptivePortalURL string
+	
+	// HTTP proxy check; the proxy defaults to the http_proxy/https_proxy environment
+	CheckProxy       bool
+	ProxyURL         string
+	ProxyTestURL     string         // Fetched through the proxy; https URLs test CONNECT, http URLs GET
+	HTTPViaProxy     bool           // Send the HTTP checks through ProxyURL
+	
+	// TLS endpoints ("host" or "host:port") whose certificate chain must verify
+	TLSChecks        []string
+	TLSTimeout       time.Duration
+	TLSMinDays       int            // Days the certificates must remain valid for (0 = just not expired)
+	
+	// Hop-by-hop path check; each of the first PathHops hops toward PathTarget must respond
+	PathTarget       string
+	PathHops         int
+	
+	// External check commands; each must exit 0 for the network to be ready
+	ExecChecks       []string
+	ExecCheckTimeout time.Duration
+	
+	// ARP probing
+	ARPProbe         bool           // Actively probe the gateway before reading the neighbor table
+	ARPProbeTimeout  time.Duration
+	
+	// Notifications
+	WebhookURL       string         // POST readiness transitions here when set
+	WebhookTimeout   time.Duration
+	OnReady          string         // Command run when the network becomes ready
+	OnDegraded       string         // Command run when a ready network stops being ready
+	OnTimeout        string         // Command run when the total timeout expires before readiness
+	HookTimeout      time.Duration  // Limit on each hook command's runtime
+	
+	// Metrics
+	MetricsListen    string         // Address to serve Prometheus /metrics on (empty = disabled)
 	
 	// File paths
-	LogFile          string
-	LockFile         string
+	ConfigFile       string         // YAML file loaded before the environment and flags (empty = none)
+	ConfigDir        string         // Drop-in directory whose *.conf fragments override the config file
+	ConfigFragments  []string       // Fragments loaded from ConfigDir, in merge order
+	Profile          string         // Named profile applied on top of the config files (empty = none)
+	LogFile          string         // Log file path, rotated in place
+	LockFile         string         // Lock file preventing concurrent instances
+	StateFile        string         // JSON outcome of the last run for other tooling (empty = disabled)
+	NoLogFile        bool           // Log to stdout only, e.g. under journald or in containers
+	Console          io.Writer      // Replaces stdout for log output; set by programs embedding the monitor
+	
+	profiles         map[string][]*fileConfig  // Profiles defined by the loaded config files
+	badPartnerMACs   []string                  // lacp_partner_mac entries that didn't parse, reported by Validate
+}
+
+// IsAdvisory reports whether a check is logged only and doesn't block readiness
+func (c *Config) IsAdvisory(check string) bool {
+	for _, name := range c.AdvisoryChecks {
+		if name == check {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinChecks lists the built-in checks in report order, each with whether
+// a configuration enables it; checks without a condition always run
+var builtinChecks = []struct {
+	name    string
+	enabled func(c *Config) bool
+}{
+	{"services", nil},
+	{"interfaces", nil},
+	{"gateway", nil},
+	{"dns", nil},
+	{"nm_connectivity", nil},
+	{"arp", nil},
+	{"routing", nil},
+	{"dhcp", func(c *Config) bool { return c.CheckDHCP }},
+	{"timesync", func(c *Config) bool { return c.CheckTimeSync }},
+	{"ndp", func(c *Config) bool { return c.CheckNDP }},
+	{"tcp", func(c *Config) bool { return len(c.TCPChecks) > 0 }},
+	{"http", func(c *Config) bool { return len(c.HTTPChecks) > 0 }},
+	{"captive_portal", func(c *Config) bool { return c.CaptivePortal }},
+	{"proxy", func(c *Config) bool { return c.CheckProxy }},
+	{"tls", func(c *Config) bool { return len(c.TLSChecks) > 0 }},
+	{"path", func(c *Config) bool { return c.PathTarget != "" }},
+	{"resolved", func(c *Config) bool { return c.CheckResolved }},
+	{"encrypted_dns", func(c *Config) bool { return len(c.EncryptedDNS) > 0 }},
+	{"reverse_dns", func(c *Config) bool { return c.CheckReverseDNS }},
+	{"resolv_conf", func(c *Config) bool { return c.CheckResolvConf }},
+	{"hostname", func(c *Config) bool { return c.CheckHostname }},
+	{"dnssec", func(c *Config) bool { return c.CheckDNSSEC }},
+}
+
+// EnabledChecks returns the names of the checks the configuration enables in
+// report order, exec checks last
+func (c *Config) EnabledChecks() []string {
+	var checks []string
+	for _, check := range builtinChecks {
+		if check.enabled == nil || check.enabled(c) {
+			checks = append(checks, check.name)
+		}
+	}
+	return append(checks, c.ExecCheckNames()...)
+}
+
+// RequiredChecks returns the enabled checks that aren't advisory, the ones
+// the readiness quorum counts
+func (c *Config) RequiredChecks() []string {
+	var required []string
+	for _, check := range c.EnabledChecks() {
+		if !c.IsAdvisory(check) {
+			required = append(required, check)
+		}
+	}
+	return required
+}
+
+// ExecCheckNames returns the check name of each exec check in order: "exec:"
+// and the base name of its executable, numbered "#2", "#3" and so on when
+// several checks run the same executable
+func (c *Config) ExecCheckNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, command := range c.ExecChecks {
+		base := "exec:"
+		if args := strings.Fields(command); len(args) > 0 {
+			base += filepath.Base(args[0])
+		}
+		name := base
+		for i := 2; seen[name]; i++ {
+			name = fmt.Sprintf("%s#%d", base, i)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// InterfacePolicy overrides the global readiness rules for a single interface
+type InterfacePolicy struct {
+	CarrierOnly bool   // Only carrier is required; MTU, speed, bond, bridge, tunnel, InfiniBand and wireless state are reported but not enforced
+	MinSlaves   int    // Minimum usable bond slaves, overriding the global and per-bond minimum (0 = no override)
+	RequireLACP *bool  // Require the bond mode's readiness rule, e.g. LACP negotiation (nil = default, true)
+	MTU         int    // Expected MTU, overriding the global and per-interface expected MTU
+	MinSpeed    int    // Minimum negotiated speed in Mbps, overriding the global and per-interface minimum
+}
+
+// LACPRequired reports whether bond negotiation must be complete under this policy
+func (p InterfacePolicy) LACPRequired() bool {
+	return !p.CarrierOnly && (p.RequireLACP == nil || *p.RequireLACP)
+}
+
+// HTTPCheck is a URL fetched by the HTTP check
+type HTTPCheck struct {
+	URL      string
+	Status   string         // Accepted status code or range (empty = http_status)
+	Body     string         // Substring the response body must contain (empty = any body)
+	Timeout  time.Duration  // Request timeout (0 = http_timeout)
+	Insecure bool           // Skip TLS certificate verification
+}
+
+// httpChecks turns a list of URLs into HTTP checks using the global settings
+func httpChecks(urls []string) []HTTPCheck {
+	checks := make([]HTTPCheck, 0, len(urls))
+	for _, url := range urls {
+		checks = append(checks, HTTPCheck{URL: url})
+	}
+	return checks
+}
+
+// PingThreshold overrides the ping settings for a single target
+type PingThreshold struct {
+	Count   int            // Echo requests per check (0 = ping_count)
+	MaxLoss *int           // Highest acceptable packet loss percentage (nil = ping_max_loss)
+	MaxRTT  time.Duration  // Highest acceptable average round-trip time (0 = ping_max_rtt)
+}
+
+// PingSettings returns how many echo requests to send to a ping target
+// ("gateway" for the default gateway) and the loss and latency it must stay within
+func (c *Config) PingSettings(target string) (count, maxLoss int, maxRTT time.Duration) {
+	count, maxLoss, maxRTT = c.PingCount, c.PingMaxLoss, c.PingMaxRTT
+	if target == "gateway" && c.GatewayMaxRTT > 0 {
+		maxRTT = c.GatewayMaxRTT
+	}
+	
+	if t, ok := c.PingThresholds[strings.ToLower(target)]; ok {
+		if t.Count > 0 {
+			count = t.Count
+		}
+		if t.MaxLoss != nil {
+			maxLoss = *t.MaxLoss
+		}
+		if t.MaxRTT > 0 {
+			maxRTT = t.MaxRTT
+		}
+	}
+	return count, maxLoss, maxRTT
+}
+
+// CheckThreshold overrides the consecutive-result thresholds for a single check
+type CheckThreshold struct {
+	Fail    int  // Failing results before the check counts as down (0 = default)
+	Recover int  // Passing results before the check counts as up (0 = default)
+}
+
+// Thresholds returns how many consecutive failing and passing results the
+// named check needs before it changes state
+func (c *Config) Thresholds(check string) (fail, recover int) {
+	fail = c.FailureThreshold
+	recover = c.RecoveryThreshold
+	if recover == 0 {
+		recover = c.FailureThreshold
+	}
+	
+	if t, ok := c.CheckThresholds[check]; ok {
+		if t.Fail > 0 {
+			fail = t.Fail
+		}
+		if t.Recover > 0 {
+			recover = t.Recover
+		}
+	}
+	return fail, recover
 }
 
 // DefaultConfig returns a configuration with default values
 func DefaultConfig() *Config {
 	logFile := "/var/log/network_startup_monitor.log"
 	lockFile := "/var/run/network_monitor.lock"
+	stateFile := "/var/lib/network-monitor/last-run.json"
 	
 	// Set log file location based on user privileges (like bash script)
 	if os.Geteuid() != 0 {
@@ -48,11 +365,13 @@ func DefaultConfig() *Config {
 			if info, err := os.Stat(home); err == nil && info.IsDir() {
 				logFile = home + "/network_startup_monitor.log"
 				lockFile = home + "/network_monitor.lock"
+				stateFile = home + "/network_monitor_last_run.json"
 			}
 		} else {
 			uid := os.Getuid()
 			logFile = fmt.Sprintf("/tmp/network_startup_monitor_%d.log", uid)
 			lockFile = fmt.Sprintf("/tmp/network_monitor_%d.lock", uid)
+			stateFile = fmt.Sprintf("/tmp/network_monitor_last_run_%d.json", uid)
 		}
 	}
 	
@@ -60,11 +379,43 @@ func DefaultConfig() *Config {
 		TotalTimeout:       15 * time.Minute,
 		RunAfterSuccess:    1 * time.Minute,  // Updated to match bash script v0.6.1
 		SleepInterval:      1 * time.Second,
+		SleepJitter:        0,
 		PingTimeout:        1 * time.Second,
 		DNSTimeout:         1 * time.Second,  // Updated to match bash script v0.6.1
+		SystemdTimeout:     5 * time.Second,
+		NMTimeout:          5 * time.Second,
+		ShutdownTimeout:    3 * time.Second,
+		FailureThreshold:   1,
+		RecoveryThreshold:  0,
+		CheckThresholds:    map[string]CheckThreshold{},
+		FlapThreshold:      5,
+		FlapWindow:         1 * time.Minute,
+		AdvisoryChecks:     []string{},
+		ReadinessQuorum:    "all",
+		ReadinessExpr:      "",
 		BlockingMode:       false,
-		InterfaceTypes:     []string{"ethernet", "bond"},
+		Output:             "text",
+		Watch:              false,
+		EventDriven:        false,
+		EventBackstop:      30 * time.Second,
+		InterfaceTypes:     []string{"ethernet", "bond", "vlan"},
 		RequiredInterfaces: []string{},  // Empty = any interface sufficient
+		ExcludedInterfaces: []string{},
+		IgnoreSRIOVVFs:     false,
+		MinInterfacesUp:    0,
+		ExpectedMTU:        0,
+		InterfaceMTUs:      map[string]int{},
+		PathMTUProbe:       false,
+		MinSpeed:           0,
+		TunnelHandshakeAge: 3 * time.Minute,  // WireGuard sessions expire after 180s without a new handshake
+		WirelessSSIDs:      []string{},
+		WirelessMinSignal:  0,
+		InterfaceSpeeds:    map[string]int{},
+		BondMinSlaves:      0,
+		BondSlaves:         map[string]int{},
+		LACPPartnerMAC:     "",
+		BondPartnerMACs:    map[string]string{},
+		InterfacePolicies:  map[string]InterfacePolicy{},
 		NetworkServices: []string{
 			"systemd-networkd.service",
 			"systemd-networkd-wait-online.service",
@@ -75,48 +426,171 @@ func DefaultConfig() *Config {
 			"dhcpcd.service",
 			"wpa_supplicant.service",
 		},
-		ResolverHostname: "google.com",
+		RequiredServices: []string{},
+		PingTargets:      []string{},
+		PingPolicy:       "all",
+		PingGateway:      false,
+		GatewayFamily:    "ipv4",
+		GatewayProbe:     "icmp",
+		PingCount:        1,
+		PingMaxLoss:      0,
+		PingMaxRTT:       0,
+		GatewayMaxRTT:    0,
+		PingThresholds:   map[string]PingThreshold{},
+		ResolverHostnames: []string{"google.com"},
+		DNSQuorum:        "all",
+		DNSServers:       []string{},
+		DNSSystemServers: false,
+		DNSMaxLatency:    0,
+		EncryptedDNS:     []string{},
+		RouteTable:       254,
+		IPv6DefaultRoute: false,
+		RequiredRoutes:   []string{},
+		PolicyTables:     []string{},
+		CheckDHCP:        false,
+		CheckTimeSync:    false,
+		CheckResolved:    false,
+		CheckReverseDNS:  false,
+		CheckResolvConf:  false,
+		CheckHostname:    false,
+		CheckDNSSEC:      false,
+		DNSSECSignedName: "ietf.org",
+		DNSSECBogusName:  "dnssec-failed.org",
+		CheckNDP:         false,
+		TCPChecks:        []string{},
+		TCPTimeout:       3 * time.Second,
+		HTTPChecks:       []HTTPCheck{},
+		HTTPTimeout:      5 * time.Second,
+		HTTPStatus:       "200-399",
+		CaptivePortal:    false,
+		CaptivePortalURL: "http://connectivitycheck.gstatic.com/generate_204",
+		CheckProxy:       false,
+		ProxyURL:         "",
+		ProxyTestURL:     "https://connectivitycheck.gstatic.com/generate_204",
+		HTTPViaProxy:     false,
+		TLSChecks:        []string{},
+		TLSTimeout:       5 * time.Second,
+		TLSMinDays:       0,
+		PathTarget:       "",
+		PathHops:         3,
+		ExecChecks:       []string{},
+		ExecCheckTimeout: 10 * time.Second,
+		ARPProbe:         false,
+		ARPProbeTimeout:  500 * time.Millisecond,
+		WebhookURL:       "",
+		WebhookTimeout:   5 * time.Second,
+		OnReady:          "",
+		OnDegraded:       "",
+		OnTimeout:        "",
+		HookTimeout:      30 * time.Second,
+		MetricsListen:    "",
 		LogFile:         logFile,
 		LockFile:        lockFile,
+		StateFile:       stateFile,
+		NoLogFile:       false,
 	}
 }
 
 // LoadFromEnv loads configuration from environment variables
 func (c *Config) LoadFromEnv() {
 	if val := os.Getenv("TOTAL_TIMEOUT"); val != "" {
-		if timeout, err := strconv.Atoi(val); err == nil {
-			c.TotalTimeout = time.Duration(timeout) * time.Second
+		if timeout, err := ParseDuration(val); err == nil {
+			c.TotalTimeout = timeout
 		}
 	}
 	
 	if val := os.Getenv("RUN_AFTER_SUCCESS"); val != "" {
-		if timeout, err := strconv.Atoi(val); err == nil {
-			c.RunAfterSuccess = time.Duration(timeout) * time.Second
+		if timeout, err := ParseDuration(val); err == nil {
+			c.RunAfterSuccess = timeout
 		}
 	}
 	
 	if val := os.Getenv("SLEEP_INTERVAL"); val != "" {
-		// Try parsing as duration first (e.g., "1.5s", "500ms")
-		if duration, err := time.ParseDuration(val); err == nil {
-			c.SleepInterval = duration
-		} else if interval, err := strconv.ParseFloat(val, 64); err == nil {
-			// Fall back to parsing as float seconds for backward compatibility
-			c.SleepInterval = time.Duration(interval * float64(time.Second))
+		if interval, err := ParseDuration(val); err == nil {
+			c.SleepInterval = interval
+		}
+	}
+	
+	if val := os.Getenv("SLEEP_JITTER"); val != "" {
+		if jitter, err := ParsePercent(val); err == nil {
+			c.SleepJitter = jitter
 		}
 	}
 	
 	if val := os.Getenv("PING_TIMEOUT"); val != "" {
-		if timeout, err := strconv.Atoi(val); err == nil {
-			c.PingTimeout = time.Duration(timeout) * time.Second
+		if timeout, err := ParseDuration(val); err == nil {
+			c.PingTimeout = timeout
 		}
 	}
 	
 	if val := os.Getenv("DNS_TIMEOUT"); val != "" {
-		if timeout, err := strconv.Atoi(val); err == nil {
-			c.DNSTimeout = time.Duration(timeout) * time.Second
+		if timeout, err := ParseDuration(val); err == nil {
+			c.DNSTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("SYSTEMD_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.SystemdTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("NM_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.NMTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("SHUTDOWN_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.ShutdownTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("FAILURE_THRESHOLD"); val != "" {
+		if threshold, err := strconv.Atoi(val); err == nil && threshold > 0 {
+			c.FailureThreshold = threshold
+		}
+	}
+	
+	if val := os.Getenv("RECOVERY_THRESHOLD"); val != "" {
+		if threshold, err := strconv.Atoi(val); err == nil && threshold > 0 {
+			c.RecoveryThreshold = threshold
+		}
+	}
+	
+	if val := os.Getenv("CHECK_THRESHOLDS"); val != "" {
+		if thresholds, err := ParseCheckThresholds(val); err == nil {
+			c.CheckThresholds = thresholds
+		}
+	}
+	
+	if val := os.Getenv("FLAP_THRESHOLD"); val != "" {
+		if threshold, err := strconv.Atoi(val); err == nil && threshold >= 0 {
+			c.FlapThreshold = threshold
+		}
+	}
+	
+	if val := os.Getenv("FLAP_WINDOW"); val != "" {
+		if window, err := ParseDuration(val); err == nil {
+			c.FlapWindow = window
+		}
+	}
+	
+	if val := os.Getenv("ADVISORY_CHECKS"); val != "" {
+		c.AdvisoryChecks = checkNames(strings.Fields(val))
+	}
+	
+	if val := os.Getenv("READINESS_QUORUM"); val != "" {
+		if _, err := ParseQuorum(val, 1); err == nil {
+			c.ReadinessQuorum = val
 		}
 	}
 	
+	if val := os.Getenv("READINESS_EXPR"); val != "" {
+		c.ReadinessExpr = val
+	}
+	
 	if val := os.Getenv("INTERFACE_TYPES"); val != "" {
 		c.InterfaceTypes = strings.Fields(val)
 	}
@@ -125,104 +599,1425 @@ func (c *Config) LoadFromEnv() {
 		c.RequiredInterfaces = strings.Fields(val)
 	}
 	
+	if val := os.Getenv("EXCLUDED_INTERFACES"); val != "" {
+		c.ExcludedInterfaces = strings.Fields(val)
+	}
+	
+	if val := os.Getenv("IGNORE_SRIOV_VFS"); val != "" {
+		if ignore, err := strconv.ParseBool(val); err == nil {
+			c.IgnoreSRIOVVFs = ignore
+		}
+	}
+	
+	if val := os.Getenv("MIN_INTERFACES_UP"); val != "" {
+		if count, err := strconv.Atoi(val); err == nil && count > 0 {
+			c.MinInterfacesUp = count
+		}
+	}
+	
+	if val := os.Getenv("EXPECTED_MTU"); val != "" {
+		c.parseExpectedMTU(val)
+	}
+	
+	if val := os.Getenv("PATH_MTU_PROBE"); val != "" {
+		if probe, err := strconv.ParseBool(val); err == nil {
+			c.PathMTUProbe = probe
+		}
+	}
+	
+	if val := os.Getenv("MIN_SPEED"); val != "" {
+		c.parseMinSpeed(val)
+	}
+	
+	if val := os.Getenv("BOND_MIN_SLAVES"); val != "" {
+		c.parseBondMinSlaves(val)
+	}
+	
+	if val := os.Getenv("LACP_PARTNER_MAC"); val != "" {
+		c.parseLACPPartnerMAC(val)
+	}
+	
+	if val := os.Getenv("TUNNEL_HANDSHAKE_MAX_AGE"); val != "" {
+		if age, err := ParseDuration(val); err == nil && age > 0 {
+			c.TunnelHandshakeAge = age
+		}
+	}
+	
+	if val := os.Getenv("WIRELESS_SSIDS"); val != "" {
+		c.WirelessSSIDs = strings.Fields(val)
+	}
+	
+	if val := os.Getenv("WIRELESS_MIN_SIGNAL"); val != "" {
+		if signal, err := strconv.Atoi(val); err == nil && signal < 0 {
+			c.WirelessMinSignal = signal
+		}
+	}
+	
 	if val := os.Getenv("NETWORK_SERVICES"); val != "" {
 		c.NetworkServices = strings.Fields(val)
 	}
 	
+	if val := os.Getenv("PING_TARGETS"); val != "" {
+		c.PingTargets = strings.Fields(val)
+	}
+	
+	if val := os.Getenv("PING_GATEWAY"); val != "" {
+		if ping, err := strconv.ParseBool(val); err == nil {
+			c.PingGateway = ping
+		}
+	}
+	
+	if val := os.Getenv("PING_POLICY"); val == "any" || val == "all" {
+		c.PingPolicy = val
+	}
+	
+	if val := os.Getenv("PING_COUNT"); val != "" {
+		if count, err := strconv.Atoi(val); err == nil {
+			c.PingCount = count
+		}
+	}
+	
+	if val := os.Getenv("PING_MAX_LOSS"); val != "" {
+		if loss, err := strconv.Atoi(strings.TrimSuffix(val, "%")); err == nil {
+			c.PingMaxLoss = loss
+		}
+	}
+	
+	if val := os.Getenv("PING_MAX_RTT"); val != "" {
+		if rtt, err := ParseDuration(val); err == nil {
+			c.PingMaxRTT = rtt
+		}
+	}
+	
+	if val := os.Getenv("GATEWAY_MAX_RTT"); val != "" {
+		if rtt, err := ParseDuration(val); err == nil {
+			c.GatewayMaxRTT = rtt
+		}
+	}
+	
+	if val := os.Getenv("GATEWAY_FAMILY"); IsGatewayFamily(val) {
+		c.GatewayFamily = val
+	}
+	
+	if val := os.Getenv("GATEWAY_PROBE"); IsGatewayProbe(val) {
+		c.GatewayProbe = val
+	}
+	
+	if val := os.Getenv("ROUTE_TABLE"); val != "" {
+		if table, err := ParseRouteTable(val); err == nil {
+			c.RouteTable = table
+		}
+	}
+	
+	if val := os.Getenv("REQUIRE_IPV6_DEFAULT_ROUTE"); val != "" {
+		if require, err := strconv.ParseBool(val); err == nil {
+			c.IPv6DefaultRoute = require
+		}
+	}
+	
+	if val := os.Getenv("REQUIRED_ROUTES"); val != "" {
+		c.RequiredRoutes = splitCommands(val)
+	}
+	
+	if val := os.Getenv("POLICY_TABLES"); val != "" {
+		c.PolicyTables = strings.Fields(val)
+	}
+	
+	if val := os.Getenv("REQUIRED_SERVICES"); val != "" {
+		c.RequiredServices = strings.Fields(val)
+	}
+	
 	if val := os.Getenv("RESOLVER_HOSTNAME"); val != "" {
-		c.ResolverHostname = val
+		c.ResolverHostnames = strings.Fields(val)
 	}
-}
-
-// ParseFlags parses command line flags
-func (c *Config) ParseFlags() {
-	// Operating mode
-	blocking := flag.Bool("blocking", false, "Exit immediately when network is ready (default: continuous monitoring)")
 	
-	// Interface configuration
-	requiredInterfaces := flag.String("required-interfaces", "", "Space-separated interfaces that must be up (default: any interface sufficient)")
-	interfaceTypes := flag.String("interface-types", "", "Space-separated interface types to monitor (default: \"ethernet bond\")")
+	if val := os.Getenv("DNS_QUORUM"); val != "" {
+		if _, err := ParseQuorum(val, 1); err == nil {
+			c.DNSQuorum = val
+		}
+	}
 	
-	// Timeouts
-	totalTimeout := flag.Int("total-timeout", 0, "Maximum runtime in seconds (default: 900)")
-	runAfterSuccess := flag.Int("run-after-success", 0, "Time to run after network ready in monitoring mode (default: 60)")
-	sleepInterval := flag.String("sleep-interval", "", "Check frequency (e.g., '1s', '1.5s', '500ms') (default: 1s)")
-	pingTimeout := flag.Int("ping-timeout", 0, "Gateway ping timeout in seconds (default: 1)")
-	dnsTimeout := flag.Int("dns-timeout", 0, "DNS resolution timeout in seconds (default: 1)")
+	if val := os.Getenv("DNS_SERVERS"); val != "" {
+		c.DNSServers = strings.Fields(val)
+	}
 	
-	// Network configuration
-	networkServices := flag.String("network-services", "", "Space-separated network services to monitor")
-	resolverHostname := flag.String("resolver-hostname", "", "Hostname for DNS resolution test (default: google.com)")
+	if val := os.Getenv("DNS_ENCRYPTED_SERVERS"); val != "" {
+		c.EncryptedDNS = strings.Fields(val)
+	}
 	
-	// Help
-	help := flag.Bool("help", false, "Show this help message")
-	helpShort := flag.Bool("h", false, "Show this help message")
+	if val := os.Getenv("DNS_SYSTEM_SERVERS"); val != "" {
+		if each, err := strconv.ParseBool(val); err == nil {
+			c.DNSSystemServers = each
+		}
+	}
 	
-	flag.Parse()
+	if val := os.Getenv("DNS_MAX_LATENCY"); val != "" {
+		if latency, err := ParseDuration(val); err == nil {
+			c.DNSMaxLatency = latency
+		}
+	}
 	
-	// Show help if requested
-	if *help || *helpShort {
-		fmt.Println("Usage: network-monitor [OPTIONS]")
-		fmt.Println("")
-		fmt.Println("Network startup monitor service for Linux systems")
-		fmt.Println("")
-		fmt.Println("OPTIONS:")
-		flag.PrintDefaults()
-		fmt.Println("")
-		fmt.Println("Examples:")
-		fmt.Println("  network-monitor                                       # Monitor any interface, continuous mode")
-		fmt.Println("  network-monitor -blocking                            # Exit when network ready")
-		fmt.Println("  network-monitor -required-interfaces \"eth0 eth1\"     # Require specific interfaces")
-		fmt.Println("  network-monitor -total-timeout 300 -sleep-interval 1.5s # Custom timeouts")
-		fmt.Println("  network-monitor -interface-types \"ethernet bond vlan\" # Monitor additional interface types")
-		os.Exit(0)
+	if val := os.Getenv("WEBHOOK_URL"); val != "" {
+		c.WebhookURL = val
 	}
 	
-	// Apply flag values
-	c.BlockingMode = *blocking
-	if c.BlockingMode {
-		c.RunAfterSuccess = 0
+	if val := os.Getenv("ON_READY"); val != "" {
+		c.OnReady = val
 	}
 	
-	if *requiredInterfaces != "" {
-		c.RequiredInterfaces = strings.Fields(*requiredInterfaces)
+	if val := os.Getenv("ON_DEGRADED"); val != "" {
+		c.OnDegraded = val
 	}
 	
-	if *interfaceTypes != "" {
-		c.InterfaceTypes = strings.Fields(*interfaceTypes)
+	if val := os.Getenv("ON_TIMEOUT"); val != "" {
+		c.OnTimeout = val
 	}
 	
-	if *totalTimeout > 0 {
-		c.TotalTimeout = time.Duration(*totalTimeout) * time.Second
+	if val := os.Getenv("HOOK_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.HookTimeout = timeout
+		}
 	}
 	
-	if *runAfterSuccess > 0 {
-		c.RunAfterSuccess = time.Duration(*runAfterSuccess) * time.Second
+	if val := os.Getenv("METRICS_LISTEN"); val != "" {
+		c.MetricsListen = val
 	}
 	
-	if *sleepInterval != "" {
-		// Try parsing as duration first (e.g., "1.5s", "500ms")
-		if duration, err := time.ParseDuration(*sleepInterval); err == nil {
-			c.SleepInterval = duration
-		} else if interval, err := strconv.ParseFloat(*sleepInterval, 64); err == nil {
-			// Fall back to parsing as float seconds for backward compatibility
-			c.SleepInterval = time.Duration(interval * float64(time.Second))
+	if val := os.Getenv("WATCH"); val != "" {
+		if watch, err := strconv.ParseBool(val); err == nil {
+			c.Watch = watch
+		}
+	}
+	
+	if val := os.Getenv("EVENT_DRIVEN"); val != "" {
+		if eventDriven, err := strconv.ParseBool(val); err == nil {
+			c.EventDriven = eventDriven
 		}
 	}
 	
-	if *pingTimeout > 0 {
-		c.PingTimeout = time.Duration(*pingTimeout) * time.Second
+	if val := os.Getenv("EVENT_BACKSTOP"); val != "" {
+		if interval, err := ParseDuration(val); err == nil {
+			c.EventBackstop = interval
+		}
 	}
 	
-	if *dnsTimeout > 0 {
-		c.DNSTimeout = time.Duration(*dnsTimeout) * time.Second
+	if val := os.Getenv("CHECK_DHCP"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckDHCP = check
+		}
 	}
 	
-	if *networkServices != "" {
-		c.NetworkServices = strings.Fields(*networkServices)
+	if val := os.Getenv("CHECK_TIMESYNC"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckTimeSync = check
+		}
 	}
 	
-	if *resolverHostname != "" {
-		c.ResolverHostname = *resolverHostname
+	if val := os.Getenv("CHECK_RESOLVED"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckResolved = check
+		}
+	}
+	
+	if val := os.Getenv("CHECK_REVERSE_DNS"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckReverseDNS = check
+		}
+	}
+	
+	if val := os.Getenv("CHECK_RESOLV_CONF"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckResolvConf = check
+		}
+	}
+	
+	if val := os.Getenv("CHECK_HOSTNAME"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckHostname = check
+		}
+	}
+	
+	if val := os.Getenv("CHECK_DNSSEC"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckDNSSEC = check
+		}
+	}
+	
+	if val := os.Getenv("DNSSEC_SIGNED_NAME"); val != "" {
+		c.DNSSECSignedName = val
+	}
+	
+	if val := os.Getenv("DNSSEC_BOGUS_NAME"); val == "none" {
+		c.DNSSECBogusName = ""
+	} else if val != "" {
+		c.DNSSECBogusName = val
+	}
+	
+	if val := os.Getenv("CHECK_NDP"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckNDP = check
+		}
+	}
+	
+	if val := os.Getenv("TCP_CHECKS"); val != "" {
+		c.TCPChecks = strings.Fields(val)
+	}
+	
+	if val := os.Getenv("TCP_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.TCPTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("HTTP_CHECKS"); val != "" {
+		c.HTTPChecks = httpChecks(strings.Fields(val))
+	}
+	
+	if val := os.Getenv("HTTP_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.HTTPTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("HTTP_STATUS"); val != "" {
+		c.HTTPStatus = val
+	}
+	
+	if val := os.Getenv("CHECK_CAPTIVE_PORTAL"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CaptivePortal = check
+		}
+	}
+	
+	if val := os.Getenv("CAPTIVE_PORTAL_URL"); val != "" {
+		c.CaptivePortalURL = val
+	}
+	
+	if val := os.Getenv("CHECK_PROXY"); val != "" {
+		if check, err := strconv.ParseBool(val); err == nil {
+			c.CheckProxy = check
+		}
+	}
+	
+	if val := os.Getenv("PROXY_URL"); val != "" {
+		c.ProxyURL = val
+	}
+	
+	if val := os.Getenv("PROXY_TEST_URL"); val != "" {
+		c.ProxyTestURL = val
+	}
+	
+	if val := os.Getenv("HTTP_CHECKS_VIA_PROXY"); val != "" {
+		if via, err := strconv.ParseBool(val); err == nil {
+			c.HTTPViaProxy = via
+		}
+	}
+	
+	if val := os.Getenv("TLS_CHECKS"); val != "" {
+		c.TLSChecks = strings.Fields(val)
 	}
-}
\ No newline at end of file
+	
+	if val := os.Getenv("TLS_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil && timeout > 0 {
+			c.TLSTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("TLS_MIN_DAYS"); val != "" {
+		if days, err := strconv.Atoi(val); err == nil && days >= 0 {
+			c.TLSMinDays = days
+		}
+	}
+	
+	if val := os.Getenv("PATH_TARGET"); val != "" {
+		c.PathTarget = val
+	}
+	
+	if val := os.Getenv("PATH_HOPS"); val != "" {
+		if hops, err := strconv.Atoi(val); err == nil && hops > 0 {
+			c.PathHops = hops
+		}
+	}
+	
+	if val := os.Getenv("EXEC_CHECKS"); val != "" {
+		c.ExecChecks = splitCommands(val)
+	}
+	
+	if val := os.Getenv("EXEC_CHECK_TIMEOUT"); val != "" {
+		if timeout, err := ParseDuration(val); err == nil {
+			c.ExecCheckTimeout = timeout
+		}
+	}
+	
+	if val := os.Getenv("ARP_PROBE"); val != "" {
+		if probe, err := strconv.ParseBool(val); err == nil {
+			c.ARPProbe = probe
+		}
+	}
+	
+	if val := os.Getenv("LOG_FILE"); val != "" {
+		c.LogFile = val
+	}
+	
+	if val := os.Getenv("LOCK_FILE"); val != "" {
+		c.LockFile = val
+	}
+	
+	if val := os.Getenv("STATE_FILE"); val == "none" {
+		c.StateFile = ""
+	} else if val != "" {
+		c.StateFile = val
+	}
+	
+	if val := os.Getenv("NO_LOG_FILE"); val != "" {
+		if noLogFile, err := strconv.ParseBool(val); err == nil {
+			c.NoLogFile = noLogFile
+		}
+	}
+}
+
+// ParseFlags parses command line flags. An unknown or malformed flag is
+// returned as an error rather than exiting with the flag package's status 2,
+// which would be mistaken for a timeout.
+func (c *Config) ParseFlags() error {
+	// A private flag set lets the flags be re-applied when reloading
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	
+	// Operating mode
+	blocking := fs.Bool("blocking", false, "Exit immediately when network is ready (default: continuous monitoring)")
+	fs.String("config", "", "YAML config file, loaded before environment variables and flags")
+	fs.String("profile", "", "Named profile from the config file to apply, e.g. 'datacenter' (also PROFILE)")
+	fs.String("config-dir", "", "Drop-in directory of *.conf YAML fragments merged in lexical order after -config (default: conf.d next to the config file)")
+	validateConfig := fs.Bool("validate-config", false, "Print the effective configuration and exit non-zero if it is invalid")
+	once := fs.Bool("once", false, "Run the checks once, print the results and exit (0 = ready, 1 = not ready)")
+	output := fs.String("output", "", "Result format for -once: 'text' or 'json' (default: text)")
+	dryRun := fs.Bool("dry-run", false, "Print the interfaces, services, gateway and resolvers that would be monitored and exit")
+	watch := fs.Bool("watch", false, "Re-run link checks immediately on netlink link/address/route/neighbor events")
+	eventDriven := fs.Bool("event-driven", false, "Re-run all checks on netlink events instead of polling every -sleep-interval")
+	eventBackstop := fs.String("event-backstop", "", "Polling interval in -event-driven mode, for changes without netlink events (default: 30s)")
+	
+	// Interface configuration
+	requiredInterfaces := fs.String("required-interfaces", "", "Space-separated interfaces, glob patterns or MAC addresses (e.g. 'en* bond[0-9] 3c:fd:fe:12:34:56') that must be up (default: any interface sufficient)")
+	minInterfacesUp := fs.Int("min-interfaces-up", 0, "Minimum number of monitored interfaces that must be up (default: any one)")
+	excludedInterfaces := fs.String("excluded-interfaces", "", "Space-separated interfaces or glob patterns to ignore (e.g. 'docker* veth*')")
+	ignoreSRIOVVFs := fs.Bool("ignore-sriov-vfs", false, "Never monitor SR-IOV virtual functions, e.g. VFs reserved for guests")
+	interfaceTypes := fs.String("interface-types", "", "Space-separated interface types to monitor (default: \"ethernet bond vlan\")")
+	expectedMTU := fs.String("expected-mtu", "", "Expected interface MTU, globally (\"9000\") and/or per interface (\"eth0=9000 bond0=9000\")")
+	pathMTUProbe := fs.Bool("path-mtu-probe", false, "Ping the gateway with an unfragmentable packet of the interface's expected MTU")
+	tunnelHandshakeAge := fs.String("tunnel-handshake-max-age", "", "Oldest acceptable WireGuard peer handshake for a tunnel interface to count as up (default: 3m)")
+	wirelessSSIDs := fs.String("wireless-ssids", "", "Space-separated SSIDs a wireless interface must be associated with (default: any)")
+	wirelessMinSignal := fs.Int("wireless-min-signal", 0, "Weakest acceptable wireless signal in dBm, e.g. -70 (default: report only)")
+	bondMinSlaves := fs.String("bond-min-slaves", "", "Slaves each bond needs up (and aggregated for 802.3ad), globally (\"2\") and/or per bond (\"bond0=2\") (default: any one)")
+	lacpPartnerMAC := fs.String("lacp-partner-mac", "", "Switch system MAC expected as LACP partner of 802.3ad bonds, globally (\"00:1c:73:aa:bb:cc\") and/or per bond (\"bond0=00:1c:73:aa:bb:cc\")")
+	minSpeed := fs.String("min-speed", "", "Minimum negotiated link speed in Mbps, globally (\"10000\") and/or per interface (\"ens1f0=25000\"); half duplex also fails (default: report only)")
+	
+	// Timeouts
+	totalTimeout := fs.String("total-timeout", "", "Maximum runtime (e.g., '900', '15m') (default: 15m)")
+	runAfterSuccess := fs.String("run-after-success", "", "Time to run after network ready in monitoring mode (e.g., '60', '1m') (default: 1m)")
+	sleepInterval := fs.String("sleep-interval", "", "Check frequency (e.g., '1s', '1.5s', '500ms') (default: 1s)")
+	sleepJitter := fs.String("sleep-jitter", "", "Randomize each check interval by up to +/- this percentage, e.g. 20 or 20% (default: 0)")
+	pingTimeout := fs.String("ping-timeout", "", "Gateway ping timeout (e.g., '1', '500ms') (default: 1s)")
+	dnsTimeout := fs.String("dns-timeout", "", "DNS resolution timeout (e.g., '1', '500ms') (default: 1s)")
+	systemdTimeout := fs.String("systemd-timeout", "", "Systemd service query timeout (e.g., '5', '2.5s') (default: 5s)")
+	nmTimeout := fs.String("nm-timeout", "", "NetworkManager connectivity command timeout (e.g., '5', '2.5s') (default: 5s)")
+	shutdownTimeout := fs.String("shutdown-timeout", "", "Grace period for in-flight checks after SIGTERM (e.g., '3s') (default: 3s)")
+	advisoryChecks := fs.String("advisory-checks", "", "Space-separated checks that are logged but don't block readiness (e.g. 'nm_connectivity dns')")
+	readinessQuorum := fs.String("readiness-quorum", "", "How many required checks must pass for readiness: 'all' or a number, e.g. 6 (default: all)")
+	readinessExpr := fs.String("readiness-expr", "", "Readiness condition over check names, e.g. 'interfaces && gateway && (dns || nm_connectivity)'")
+	failureThreshold := fs.Int("failure-threshold", 0, "Consecutive results required before a check changes state (default: 1)")
+	recoveryThreshold := fs.Int("recovery-threshold", 0, "Consecutive passing results before a failed check is up again (default: -failure-threshold)")
+	checkThresholds := fs.String("check-thresholds", "", "Per-check thresholds as check=fail[/recover], e.g. 'gateway=3/2 dns=5'")
+	flapThreshold := fs.Int("flap-threshold", -1, "Result changes within -flap-window before a check is reported as flapping, 0 to disable (default: 5)")
+	flapWindow := fs.String("flap-window", "", "Window for -flap-threshold (e.g., '1m') (default: 1m)")
+	
+	// Network configuration
+	networkServices := fs.String("network-services", "", "Space-separated network services to monitor")
+	requiredServices := fs.String("required-services", "", "Space-separated network services that must be active")
+	var pingTargets stringList
+	fs.Var(&pingTargets, "ping-targets", "Space-separated IPs/hostnames to ping instead of the default gateway (repeatable)")
+	fs.Var(&pingTargets, "gateway-target", "Alias for -ping-targets")
+	pingGateway := fs.Bool("ping-gateway", false, "Also ping the default gateway in addition to the ping targets")
+	pingPolicy := fs.String("ping-policy", "", "Whether 'all' or 'any' ping targets must be reachable (default: all)")
+	pingCount := fs.Int("ping-count", 0, "Echo requests sent to each ping target per check (default: 1)")
+	pingMaxLoss := fs.Int("ping-max-loss", -1, "Highest acceptable packet loss percentage per ping target (default: 0)")
+	pingMaxRTT := fs.String("ping-max-rtt", "", "Highest acceptable average round-trip time per ping target, e.g. '50ms' (default: no limit)")
+	gatewayMaxRTT := fs.String("gateway-max-rtt", "", "Highest acceptable average round-trip time to the default gateway, e.g. '5ms' (default: -ping-max-rtt)")
+	gatewayProbe := fs.String("gateway-probe", "", "How the IPv4 gateway is probed: 'icmp', 'arp', 'any' (either answers) or 'all' (both answer) (default: icmp)")
+	gatewayFamily := fs.String("gateway-family", "", "Default gateway(s) that must be reachable: 'ipv4', 'ipv6', 'either' or 'both' (default: ipv4)")
+	routeTable := fs.String("route-table", "", "Routing table for default route lookup: 'main', 'all' or a table ID (default: main)")
+	requireIPv6DefaultRoute := fs.Bool("require-ipv6-default-route", false, "Routing check also requires an IPv6 default route")
+	var requiredRoutes commandList
+	fs.Var(&requiredRoutes, "required-route", "Route that must exist, e.g. '10.0.0.0/8 via 10.1.1.1' or 'default dev bond0' (repeatable)")
+	policyTables := fs.String("policy-tables", "", "Space-separated policy routing table IDs that must be selected by an ip rule and have a default route, e.g. '100 200'")
+	resolverHostname := fs.String("resolver-hostname", "", "Space-separated hostnames for DNS resolution test (default: google.com)")
+	dnsQuorum := fs.String("dns-quorum", "", "How many resolver hostnames must resolve: 'all', 'any' or a number (default: all)")
+	dnsServers := fs.String("dns-servers", "", "Space-separated DNS servers to query directly, e.g. '10.0.0.53 1.1.1.1' (default: system resolver)")
+	var dnsEncryptedServers stringList
+	fs.Var(&dnsEncryptedServers, "dns-encrypted-server", "Space-separated DNS-over-TLS ('tls://1.1.1.1#cloudflare-dns.com') or DNS-over-HTTPS ('https://dns.google/dns-query') servers that must resolve the resolver hostnames (repeatable)")
+	dnsMaxLatency := fs.String("dns-max-latency", "", "Slowest acceptable resolution of each resolver hostname, e.g. '500ms' (default: no limit)")
+	dnsSystemServers := fs.Bool("dns-system-servers", false, "Query each nameserver of the system resolver (or systemd-resolved's upstreams) directly, reporting them individually")
+	webhookURL := fs.String("webhook-url", "", "URL to POST a JSON payload to on network ready/lost transitions")
+	onReady := fs.String("on-ready", "", "Command to run when the network becomes ready, e.g. '/usr/local/bin/warmup'")
+	onDegraded := fs.String("on-degraded", "", "Command to run when a ready network stops being ready")
+	onTimeout := fs.String("on-timeout", "", "Command to run when the total timeout expires before the network is ready")
+	hookTimeout := fs.String("hook-timeout", "", "Timeout for each -on-* hook command (e.g., '30s') (default: 30s)")
+	metricsListen := fs.String("metrics-listen", "", "Address to serve Prometheus metrics on (e.g. ':9100')")
+	checkDHCP := fs.Bool("check-dhcp", false, "Require unexpired DHCP leases on monitored interfaces that use DHCP")
+	checkTimeSync := fs.Bool("check-timesync", false, "Require the system clock to be NTP synchronized")
+	checkResolved := fs.Bool("check-resolved", false, "Require systemd-resolved to have received DNS servers for the default route")
+	checkResolvConf := fs.Bool("check-resolv-conf", false, "Require /etc/resolv.conf to exist, list nameservers that answer and, with systemd-resolved, link to its files")
+	checkHostname := fs.Bool("check-hostname", false, "Require the hostname to resolve, as hostname -f does, to one of this host's addresses")
+	checkDNSSEC := fs.Bool("check-dnssec", false, "Require the resolver to validate DNSSEC: a signed name must come back authenticated and a bogus one must fail")
+	dnssecSignedName := fs.String("dnssec-signed-name", "", "DNSSEC-signed name the resolver must return authenticated (default: ietf.org)")
+	dnssecBogusName := fs.String("dnssec-bogus-name", "", "Deliberately mis-signed name the resolver must refuse, or 'none' (default: dnssec-failed.org)")
+	checkReverseDNS := fs.Bool("check-reverse-dns", false, "Require PTR records for the host's primary IPv4 and IPv6 addresses")
+	checkNDP := fs.Bool("check-ndp", false, "Require IPv6 neighbor (NDP) entries and a resolved IPv6 gateway")
+	var tcpChecks stringList
+	fs.Var(&tcpChecks, "tcp-check", "Space-separated host:port endpoints that must accept a TCP connection, e.g. 'proxy:3128 dc1:389' (repeatable)")
+	tcpTimeout := fs.String("tcp-timeout", "", "Connect timeout for each -tcp-check endpoint (e.g., '3s') (default: 3s)")
+	var httpURLs stringList
+	fs.Var(&httpURLs, "http-check", "Space-separated URLs that must answer with an accepted status, e.g. 'https://repo.example.com/health' (repeatable)")
+	httpTimeout := fs.String("http-timeout", "", "Timeout for each -http-check request (e.g., '5s') (default: 5s)")
+	httpStatus := fs.String("http-status", "", "Accepted HTTP status code or range, e.g. '200' or '200-299' (default: 200-399)")
+	captivePortal := fs.Bool("check-captive-portal", false, "Detect captive portals by fetching -captive-portal-url")
+	checkProxy := fs.Bool("check-proxy", false, "Check that the HTTP proxy accepts requests by fetching -proxy-test-url through it")
+	proxyURL := fs.String("proxy-url", "", "HTTP proxy to check, e.g. 'http://proxy.example.com:3128' (default: http_proxy/https_proxy environment)")
+	proxyTestURL := fs.String("proxy-test-url", "", "URL fetched through the proxy; https tests CONNECT, http tests GET (default: https://connectivitycheck.gstatic.com/generate_204)")
+	httpViaProxy := fs.Bool("http-via-proxy", false, "Send the -http-check requests through -proxy-url")
+	var tlsChecks stringList
+	fs.Var(&tlsChecks, "tls-check", "Space-separated host or host:port endpoints whose TLS certificate must verify, e.g. 'repo.example.com ldap.example.com:636' (repeatable)")
+	tlsTimeout := fs.String("tls-timeout", "", "Connect and handshake timeout for each -tls-check endpoint (e.g., '5s') (default: 5s)")
+	tlsMinDays := fs.Int("tls-min-days", -1, "Days the -tls-check certificates must remain valid for (default: 0, just not expired)")
+	pathTarget := fs.String("path-target", "", "IP or hostname to trace toward; each of the first -path-hops hops must respond")
+	pathHops := fs.Int("path-hops", 0, "Number of hops toward -path-target that must respond (default: 3)")
+	captivePortalURL := fs.String("captive-portal-url", "", "URL that answers 204 when there is no captive portal (default: http://connectivitycheck.gstatic.com/generate_204)")
+	var execChecks commandList
+	fs.Var(&execChecks, "exec-check", "Command that must exit 0 for the network to be ready, e.g. '/usr/local/bin/check-mount /data' (repeatable)")
+	execCheckTimeout := fs.String("exec-check-timeout", "", "Timeout for each -exec-check command (e.g., '10s') (default: 10s)")
+	arpProbe := fs.Bool("arp-probe", false, "Actively probe the gateway to populate the ARP table before checking it")
+	
+	// Files
+	logFile := fs.String("log-file", "", "Log file path (default: /var/log/network_startup_monitor.log as root)")
+	lockFile := fs.String("lock-file", "", "Lock file path (default: /var/run/network_monitor.lock as root)")
+	stateFile := fs.String("state-file", "", "Last-run state file path, or 'none' to disable (default: /var/lib/network-monitor/last-run.json as root)")
+	noLogFile := fs.Bool("no-log-file", false, "Log to stdout only, e.g. under journald or in containers")
+	
+	// Help
+	showVersion := fs.Bool("version", false, "Print version, git commit and build date and exit")
+	help := fs.Bool("help", false, "Show this help message")
+	helpShort := fs.Bool("h", false, "Show this help message")
+	
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return fmt.Errorf("invalid command line: %w", err)
+	}
+	
+	if *showVersion {
+		fmt.Printf("network-monitor %s\n", version.Get())
+		os.Exit(0)
+	}
+	
+	// Show help if requested
+	if *help || *helpShort {
+		fmt.Println("Usage: network-monitor [OPTIONS]")
+		fmt.Println("")
+		fmt.Println("Network startup monitor service for Linux systems")
+		fmt.Println("")
+		fmt.Println("OPTIONS:")
+		fs.PrintDefaults()
+		fmt.Println("")
+		fmt.Println("Examples:")
+		fmt.Println("  network-monitor                                       # Monitor any interface, continuous mode")
+		fmt.Println("  network-monitor -blocking                            # Exit when network ready")
+		fmt.Println("  network-monitor -once                                # Check once, exit 0 if ready")
+		fmt.Println("  network-monitor -once -output json                   # Check once, print JSON results")
+		fmt.Println("  network-monitor -dry-run                             # Show what would be monitored")
+		fmt.Println("  network-monitor -required-interfaces \"eth0 eth1\"     # Require specific interfaces")
+		fmt.Println("  network-monitor -total-timeout 5m -sleep-interval 1.5s # Custom timeouts")
+		fmt.Println("  network-monitor -interface-types \"ethernet bond wireless\" # Monitor additional interface types")
+		os.Exit(0)
+	}
+	
+	// Apply flag values
+	if *blocking {
+		c.BlockingMode = true
+	}
+	if c.BlockingMode {
+		c.RunAfterSuccess = 0
+	}
+	
+	if *watch {
+		c.Watch = true
+	}
+	
+	if *eventDriven {
+		c.EventDriven = true
+	}
+	
+	if *eventBackstop != "" {
+		if interval, err := ParseDuration(*eventBackstop); err == nil && interval > 0 {
+			c.EventBackstop = interval
+		}
+	}
+	
+	if *once {
+		c.Once = true
+	}
+	
+	if *validateConfig {
+		c.ValidateOnly = true
+	}
+	
+	if *dryRun {
+		c.DryRun = true
+	}
+	
+	if *output != "" {
+		c.Output = *output
+	}
+	
+	if *requiredInterfaces != "" {
+		c.RequiredInterfaces = strings.Fields(*requiredInterfaces)
+	}
+	
+	if *excludedInterfaces != "" {
+		c.ExcludedInterfaces = strings.Fields(*excludedInterfaces)
+	}
+	
+	if *ignoreSRIOVVFs {
+		c.IgnoreSRIOVVFs = true
+	}
+	
+	if *minInterfacesUp > 0 {
+		c.MinInterfacesUp = *minInterfacesUp
+	}
+	
+	if *interfaceTypes != "" {
+		c.InterfaceTypes = strings.Fields(*interfaceTypes)
+	}
+	
+	if *expectedMTU != "" {
+		c.parseExpectedMTU(*expectedMTU)
+	}
+	
+	if *pathMTUProbe {
+		c.PathMTUProbe = true
+	}
+	
+	if *minSpeed != "" {
+		c.parseMinSpeed(*minSpeed)
+	}
+	
+	if *bondMinSlaves != "" {
+		c.parseBondMinSlaves(*bondMinSlaves)
+	}
+	
+	if *lacpPartnerMAC != "" {
+		c.parseLACPPartnerMAC(*lacpPartnerMAC)
+	}
+	
+	if *tunnelHandshakeAge != "" {
+		if age, err := ParseDuration(*tunnelHandshakeAge); err == nil && age > 0 {
+			c.TunnelHandshakeAge = age
+		}
+	}
+	
+	if *wirelessSSIDs != "" {
+		c.WirelessSSIDs = strings.Fields(*wirelessSSIDs)
+	}
+	
+	if *wirelessMinSignal < 0 {
+		c.WirelessMinSignal = *wirelessMinSignal
+	}
+	
+	if *totalTimeout != "" {
+		if timeout, err := ParseDuration(*totalTimeout); err == nil && timeout > 0 {
+			c.TotalTimeout = timeout
+		}
+	}
+	
+	if *runAfterSuccess != "" {
+		if timeout, err := ParseDuration(*runAfterSuccess); err == nil && timeout > 0 {
+			c.RunAfterSuccess = timeout
+		}
+	}
+	
+	if *sleepInterval != "" {
+		if interval, err := ParseDuration(*sleepInterval); err == nil {
+			c.SleepInterval = interval
+		}
+	}
+	
+	if *sleepJitter != "" {
+		if jitter, err := ParsePercent(*sleepJitter); err == nil {
+			c.SleepJitter = jitter
+		}
+	}
+	
+	if *pingTimeout != "" {
+		if timeout, err := ParseDuration(*pingTimeout); err == nil && timeout > 0 {
+			c.PingTimeout = timeout
+		}
+	}
+	
+	if *dnsTimeout != "" {
+		if timeout, err := ParseDuration(*dnsTimeout); err == nil && timeout > 0 {
+			c.DNSTimeout = timeout
+		}
+	}
+	
+	if *systemdTimeout != "" {
+		if timeout, err := ParseDuration(*systemdTimeout); err == nil && timeout > 0 {
+			c.SystemdTimeout = timeout
+		}
+	}
+	
+	if *nmTimeout != "" {
+		if timeout, err := ParseDuration(*nmTimeout); err == nil && timeout > 0 {
+			c.NMTimeout = timeout
+		}
+	}
+	
+	if *shutdownTimeout != "" {
+		if timeout, err := ParseDuration(*shutdownTimeout); err == nil && timeout > 0 {
+			c.ShutdownTimeout = timeout
+		}
+	}
+	
+	if *advisoryChecks != "" {
+		c.AdvisoryChecks = checkNames(strings.Fields(*advisoryChecks))
+	}
+	
+	if *readinessQuorum != "" {
+		if _, err := ParseQuorum(*readinessQuorum, 1); err == nil {
+			c.ReadinessQuorum = *readinessQuorum
+		}
+	}
+	
+	if *readinessExpr != "" {
+		c.ReadinessExpr = *readinessExpr
+	}
+	
+	if *failureThreshold > 0 {
+		c.FailureThreshold = *failureThreshold
+	}
+	
+	if *recoveryThreshold > 0 {
+		c.RecoveryThreshold = *recoveryThreshold
+	}
+	
+	if *flapThreshold >= 0 {
+		c.FlapThreshold = *flapThreshold
+	}
+	
+	if *flapWindow != "" {
+		if window, err := ParseDuration(*flapWindow); err == nil && window > 0 {
+			c.FlapWindow = window
+		}
+	}
+	
+	if *checkThresholds != "" {
+		if thresholds, err := ParseCheckThresholds(*checkThresholds); err == nil {
+			c.CheckThresholds = thresholds
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %v, ignoring -check-thresholds\n", err)
+		}
+	}
+	
+	if *networkServices != "" {
+		c.NetworkServices = strings.Fields(*networkServices)
+	}
+	
+	if len(pingTargets) > 0 {
+		c.PingTargets = pingTargets
+	}
+	
+	if *pingGateway {
+		c.PingGateway = true
+	}
+	
+	if *pingPolicy == "any" || *pingPolicy == "all" {
+		c.PingPolicy = *pingPolicy
+	}
+	
+	if *pingCount > 0 {
+		c.PingCount = *pingCount
+	}
+	
+	if *pingMaxLoss >= 0 {
+		c.PingMaxLoss = *pingMaxLoss
+	}
+	
+	if *pingMaxRTT != "" {
+		if rtt, err := ParseDuration(*pingMaxRTT); err == nil {
+			c.PingMaxRTT = rtt
+		}
+	}
+	
+	if *gatewayMaxRTT != "" {
+		if rtt, err := ParseDuration(*gatewayMaxRTT); err == nil {
+			c.GatewayMaxRTT = rtt
+		}
+	}
+	
+	if IsGatewayFamily(*gatewayFamily) {
+		c.GatewayFamily = *gatewayFamily
+	}
+	
+	if IsGatewayProbe(*gatewayProbe) {
+		c.GatewayProbe = *gatewayProbe
+	}
+	
+	if *routeTable != "" {
+		if table, err := ParseRouteTable(*routeTable); err == nil {
+			c.RouteTable = table
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %v, using main table\n", err)
+		}
+	}
+	
+	if *requireIPv6DefaultRoute {
+		c.IPv6DefaultRoute = true
+	}
+	
+	if len(requiredRoutes) > 0 {
+		c.RequiredRoutes = requiredRoutes
+	}
+	
+	if *policyTables != "" {
+		c.PolicyTables = strings.Fields(*policyTables)
+	}
+	
+	if *requiredServices != "" {
+		c.RequiredServices = strings.Fields(*requiredServices)
+	}
+	
+	if *resolverHostname != "" {
+		c.ResolverHostnames = strings.Fields(*resolverHostname)
+	}
+	
+	if *dnsQuorum != "" {
+		if _, err := ParseQuorum(*dnsQuorum, 1); err == nil {
+			c.DNSQuorum = *dnsQuorum
+		}
+	}
+	
+	if *dnsServers != "" {
+		c.DNSServers = strings.Fields(*dnsServers)
+	}
+	
+	if *dnsSystemServers {
+		c.DNSSystemServers = true
+	}
+	
+	if *dnsMaxLatency != "" {
+		if latency, err := ParseDuration(*dnsMaxLatency); err == nil {
+			c.DNSMaxLatency = latency
+		}
+	}
+	
+	if len(dnsEncryptedServers) > 0 {
+		c.EncryptedDNS = dnsEncryptedServers
+	}
+	
+	if *webhookURL != "" {
+		c.WebhookURL = *webhookURL
+	}
+	
+	if *onReady != "" {
+		c.OnReady = *onReady
+	}
+	
+	if *onDegraded != "" {
+		c.OnDegraded = *onDegraded
+	}
+	
+	if *onTimeout != "" {
+		c.OnTimeout = *onTimeout
+	}
+	
+	if *hookTimeout != "" {
+		if timeout, err := ParseDuration(*hookTimeout); err == nil && timeout > 0 {
+			c.HookTimeout = timeout
+		}
+	}
+	
+	if *metricsListen != "" {
+		c.MetricsListen = *metricsListen
+	}
+	
+	if *checkDHCP {
+		c.CheckDHCP = true
+	}
+	
+	if *checkTimeSync {
+		c.CheckTimeSync = true
+	}
+	
+	if *checkResolved {
+		c.CheckResolved = true
+	}
+	
+	if *checkReverseDNS {
+		c.CheckReverseDNS = true
+	}
+	
+	if *checkResolvConf {
+		c.CheckResolvConf = true
+	}
+	
+	if *checkHostname {
+		c.CheckHostname = true
+	}
+	
+	if *checkDNSSEC {
+		c.CheckDNSSEC = true
+	}
+	
+	if *dnssecSignedName != "" {
+		c.DNSSECSignedName = *dnssecSignedName
+	}
+	
+	if *dnssecBogusName == "none" {
+		c.DNSSECBogusName = ""
+	} else if *dnssecBogusName != "" {
+		c.DNSSECBogusName = *dnssecBogusName
+	}
+	
+	if *checkNDP {
+		c.CheckNDP = true
+	}
+	
+	if len(tcpChecks) > 0 {
+		c.TCPChecks = tcpChecks
+	}
+	
+	if *tcpTimeout != "" {
+		if timeout, err := ParseDuration(*tcpTimeout); err == nil && timeout > 0 {
+			c.TCPTimeout = timeout
+		}
+	}
+	
+	if len(httpURLs) > 0 {
+		c.HTTPChecks = httpChecks(httpURLs)
+	}
+	
+	if *httpTimeout != "" {
+		if timeout, err := ParseDuration(*httpTimeout); err == nil && timeout > 0 {
+			c.HTTPTimeout = timeout
+		}
+	}
+	
+	if *httpStatus != "" {
+		c.HTTPStatus = *httpStatus
+	}
+	
+	if *captivePortal {
+		c.CaptivePortal = true
+	}
+	
+	if *captivePortalURL != "" {
+		c.CaptivePortalURL = *captivePortalURL
+	}
+	
+	if *checkProxy {
+		c.CheckProxy = true
+	}
+	
+	if *proxyURL != "" {
+		c.ProxyURL = *proxyURL
+	}
+	
+	if *proxyTestURL != "" {
+		c.ProxyTestURL = *proxyTestURL
+	}
+	
+	if *httpViaProxy {
+		c.HTTPViaProxy = true
+	}
+	
+	if len(tlsChecks) > 0 {
+		c.TLSChecks = tlsChecks
+	}
+	
+	if *tlsTimeout != "" {
+		if timeout, err := ParseDuration(*tlsTimeout); err == nil && timeout > 0 {
+			c.TLSTimeout = timeout
+		}
+	}
+	
+	if *tlsMinDays >= 0 {
+		c.TLSMinDays = *tlsMinDays
+	}
+	
+	if *pathTarget != "" {
+		c.PathTarget = *pathTarget
+	}
+	
+	if *pathHops > 0 {
+		c.PathHops = *pathHops
+	}
+	
+	if len(execChecks) > 0 {
+		c.ExecChecks = execChecks
+	}
+	
+	if *execCheckTimeout != "" {
+		if timeout, err := ParseDuration(*execCheckTimeout); err == nil && timeout > 0 {
+			c.ExecCheckTimeout = timeout
+		}
+	}
+	
+	if *arpProbe {
+		c.ARPProbe = true
+	}
+	
+	if *logFile != "" {
+		c.LogFile = *logFile
+	}
+	
+	if *lockFile != "" {
+		c.LockFile = *lockFile
+	}
+	
+	if *stateFile == "none" {
+		c.StateFile = ""
+	} else if *stateFile != "" {
+		c.StateFile = *stateFile
+	}
+	
+	if *noLogFile {
+		c.NoLogFile = true
+	}
+	
+	return nil
+}
+
+// WorstCaseCycleTime returns the longest a single check cycle can take if
+// every bounded check runs into its timeout; checks run concurrently, so this
+// is the slowest check rather than the sum
+func (c *Config) WorstCaseCycleTime() time.Duration {
+	// Ping targets are pinged one after another, after resolving any hostname
+	pingTime := c.pingTime("gateway")
+	if len(c.PingTargets) > 0 {
+		if !c.PingGateway {
+			pingTime = 0
+		}
+		for _, target := range c.PingTargets {
+			pingTime += c.pingTime(target)
+			if net.ParseIP(target) == nil {
+				pingTime += c.DNSTimeout
+			}
+		}
+	}
+	
+	// Services are queried in parallel; NetworkManager runs two commands in sequence
+	worstCase := c.SystemdTimeout
+	for _, d := range []time.Duration{pingTime, c.DNSTimeout, 2 * c.NMTimeout} {
+		if d > worstCase {
+			worstCase = d
+		}
+	}
+	if c.ARPProbe && c.ARPProbeTimeout > worstCase {
+		worstCase = c.ARPProbeTimeout
+	}
+	if len(c.TCPChecks) > 0 && c.TCPTimeout > worstCase {
+		worstCase = c.TCPTimeout
+	}
+	if len(c.TLSChecks) > 0 && c.TLSTimeout > worstCase {
+		worstCase = c.TLSTimeout
+	}
+	// Path hops are probed one after another
+	if pathTime := time.Duration(c.PathHops) * c.PingTimeout; c.PathTarget != "" && pathTime > worstCase {
+		worstCase = pathTime
+	}
+	for _, check := range c.HTTPChecks {
+		timeout := check.Timeout
+		if timeout == 0 {
+			timeout = c.HTTPTimeout
+		}
+		if timeout > worstCase {
+			worstCase = timeout
+		}
+	}
+	if len(c.ExecChecks) > 0 && c.ExecCheckTimeout > worstCase {
+		worstCase = c.ExecCheckTimeout
+	}
+	return worstCase
+}
+
+// pingTime returns how long pinging a target takes when no echo request is
+// answered: every request waits out its timeout, spaced by the ping interval
+func (c *Config) pingTime(target string) time.Duration {
+	count, _, _ := c.PingSettings(target)
+	return time.Duration(count)*c.PingTimeout + time.Duration(count-1)*network.PingInterval
+}
+
+// stringList is a repeatable flag holding space-separated values
+type stringList []string
+
+func (sl *stringList) String() string {
+	return strings.Join(*sl, " ")
+}
+
+func (sl *stringList) Set(val string) error {
+	*sl = append(*sl, strings.Fields(val)...)
+	return nil
+}
+
+// commandList is a repeatable flag where each value is a whole command line
+type commandList []string
+
+func (cl *commandList) String() string {
+	return strings.Join(*cl, "; ")
+}
+
+func (cl *commandList) Set(val string) error {
+	*cl = append(*cl, splitCommands(val)...)
+	return nil
+}
+
+// splitCommands splits a semicolon-separated list of command lines
+func splitCommands(val string) []string {
+	var commands []string
+	for _, command := range strings.Split(val, ";") {
+		if command = strings.TrimSpace(command); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// checkNames returns check names in the form checks are looked up by, so
+// "DNS" and "dns" name the same check
+func checkNames(names []string) []string {
+	canonical := make([]string, len(names))
+	for i, name := range names {
+		canonical[i] = readiness.CanonicalName(name)
+	}
+	return canonical
+}
+
+// ParseDuration parses a duration string ("1.5s", "500ms", "30m"), falling
+// back to a bare number of seconds ("5", "1.5") for backward compatibility
+func ParseDuration(val string) (time.Duration, error) {
+	val = strings.TrimSpace(val)
+	
+	if duration, err := time.ParseDuration(val); err == nil {
+		if duration < 0 {
+			return 0, fmt.Errorf("negative duration %q", val)
+		}
+		return duration, nil
+	}
+	
+	seconds, err := strconv.ParseFloat(val, 64)
+	if err != nil || math.IsNaN(seconds) || seconds < 0 {
+		return 0, fmt.Errorf("invalid duration %q", val)
+	}
+	// Infinity and anything past ~292 years doesn't fit in a time.Duration
+	if seconds >= math.MaxInt64/float64(time.Second) {
+		return 0, fmt.Errorf("duration %q out of range", val)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// ParseCheckThresholds parses space-separated "check=fail" or
+// "check=fail/recover" entries, e.g. "gateway=3/2 dns=5"; check names are
+// case-insensitive
+func ParseCheckThresholds(val string) (map[string]CheckThreshold, error) {
+	thresholds := make(map[string]CheckThreshold)
+	for _, field := range strings.Fields(val) {
+		name, counts, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid check threshold %q (expected check=fail[/recover])", field)
+		}
+		
+		failStr, recoverStr, hasRecover := strings.Cut(counts, "/")
+		fail, err := strconv.Atoi(failStr)
+		if err != nil || fail < 1 {
+			return nil, fmt.Errorf("invalid failure threshold in %q", field)
+		}
+		threshold := CheckThreshold{Fail: fail}
+		if hasRecover {
+			recover, err := strconv.Atoi(recoverStr)
+			if err != nil || recover < 1 {
+				return nil, fmt.Errorf("invalid recovery threshold in %q", field)
+			}
+			threshold.Recover = recover
+		}
+		thresholds[readiness.CanonicalName(name)] = threshold
+	}
+	return thresholds, nil
+}
+
+// ParsePercent parses a whole percentage written with or without a trailing
+// "%", e.g. "20" or "20%"
+func ParsePercent(val string) (int, error) {
+	percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(val), "%"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", val)
+	}
+	return percent, nil
+}
+
+// ParseQuorum converts "all", "any" or a count into the number of passing
+// results required out of total; counts above total are capped
+func ParseQuorum(val string, total int) (int, error) {
+	switch strings.ToLower(val) {
+	case "all", "":
+		return total, nil
+	case "any":
+		return 1, nil
+	}
+	
+	count, err := strconv.Atoi(val)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid quorum %q (use 'all', 'any' or a positive number)", val)
+	}
+	if count > total {
+		count = total
+	}
+	return count, nil
+}
+
+// ParseResolverHostname splits a resolver hostname entry into the hostname and
+// the record type to request, e.g. "_ldap._tcp.corp/SRV"; an entry without a
+// type resolves any address
+func ParseResolverHostname(entry string) (hostname, recordType string, err error) {
+	hostname, recordType, found := strings.Cut(entry, "/")
+	if hostname == "" {
+		return "", "", fmt.Errorf("missing hostname")
+	}
+	if !found {
+		return entry, "", nil
+	}
+	
+	recordType = strings.ToUpper(recordType)
+	for _, valid := range validRecordTypes {
+		if recordType == valid {
+			return hostname, recordType, nil
+		}
+	}
+	return "", "", fmt.Errorf("unsupported record type %q (valid: %s)", recordType, strings.Join(validRecordTypes, ", "))
+}
+
+// ParseEncryptedDNSServer splits an encrypted DNS server into its transport
+// ("tls" or "https"), the address to query and the name its certificate must
+// be valid for. DNS-over-TLS servers are "tls://host[:port][#name]", port 853
+// by default, in the address#name form systemd-resolved uses; DNS-over-HTTPS
+// servers are the https URL of their endpoint.
+func ParseEncryptedDNSServer(server string) (transport, address, serverName string, err error) {
+	if rest, ok := strings.CutPrefix(server, "tls://"); ok {
+		address, serverName, _ = strings.Cut(rest, "#")
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(strings.Trim(address, "[]"), "853")
+		}
+		host, _, err := SplitEndpoint(address)
+		if err != nil {
+			return "", "", "", fmt.Errorf("%q is not tls://host[:port][#name]", server)
+		}
+		if serverName == "" {
+			serverName = host
+		}
+		return "tls", address, serverName, nil
+	}
+	
+	u, err := url.Parse(server)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return "", "", "", fmt.Errorf("%q is neither a tls:// server nor an https:// URL", server)
+	}
+	return "https", server, u.Hostname(), nil
+}
+
+// ParseStatusRange converts an HTTP status code ("200") or range ("200-399")
+// into its lowest and highest accepted codes
+func ParseStatusRange(val string) (low, high int, err error) {
+	lowStr, highStr, isRange := strings.Cut(val, "-")
+	if !isRange {
+		highStr = lowStr
+	}
+	
+	low, err = strconv.Atoi(strings.TrimSpace(lowStr))
+	if err == nil {
+		high, err = strconv.Atoi(strings.TrimSpace(highStr))
+	}
+	if err != nil || low < 100 || high > 599 || low > high {
+		return 0, 0, fmt.Errorf("invalid status %q (use a code such as 200 or a range such as 200-399)", val)
+	}
+	return low, high, nil
+}
+
+// IsGatewayFamily reports whether val names a supported gateway family
+func IsGatewayFamily(val string) bool {
+	switch val {
+	case "ipv4", "ipv6", "either", "both":
+		return true
+	}
+	return false
+}
+
+// IsGatewayProbe reports whether val names a supported gateway probe method
+func IsGatewayProbe(val string) bool {
+	switch val {
+	case "icmp", "arp", "any", "all":
+		return true
+	}
+	return false
+}
+
+// ParseRouteTable converts "main", "all" or a numeric table ID into a table number
+func ParseRouteTable(val string) (int, error) {
+	switch strings.ToLower(val) {
+	case "main":
+		return 254, nil
+	case "all":
+		return 0, nil
+	}
+	
+	table, err := strconv.Atoi(val)
+	if err != nil || table <= 0 {
+		return 0, fmt.Errorf("invalid route table %q", val)
+	}
+	return table, nil
+}
+
+// RouteTableName returns a printable name for a routing table number
+func RouteTableName(table int) string {
+	switch table {
+	case 254:
+		return "main table"
+	case 0:
+		return "all tables"
+	}
+	return fmt.Sprintf("table %d", table)
+}
+
+// ParseRequiredRoute parses a required route in ip-route syntax:
+// "<destination> [via <gateway>] [dev <interface>]", where the destination is
+// "default", a prefix or an address. The gateway may be left out of "via", as
+// in "default via dev bond0", in which case any gateway matches. A nil
+// destination is a default route of either family.
+func ParseRequiredRoute(spec string) (destination *net.IPNet, gateway net.IP, device string, err error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, nil, "", fmt.Errorf("empty route")
+	}
+	
+	if fields[0] != "default" {
+		if _, destination, err = net.ParseCIDR(fields[0]); err != nil {
+			ip := net.ParseIP(fields[0])
+			if ip == nil {
+				return nil, nil, "", fmt.Errorf("invalid destination %q in %q", fields[0], spec)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			destination = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+	}
+	
+	for i := 1; i < len(fields); i++ {
+		switch fields[i] {
+		case "via":
+			if i+1 < len(fields) && fields[i+1] != "dev" {
+				i++
+				if gateway = net.ParseIP(fields[i]); gateway == nil {
+					return nil, nil, "", fmt.Errorf("invalid gateway %q in %q", fields[i], spec)
+				}
+			}
+		case "dev":
+			if i+1 == len(fields) {
+				return nil, nil, "", fmt.Errorf("missing interface after dev in %q", spec)
+			}
+			i++
+			device = fields[i]
+		default:
+			return nil, nil, "", fmt.Errorf("unexpected %q in %q", fields[i], spec)
+		}
+	}
+	return destination, gateway, device, nil
+}
+
+// parseExpectedMTU parses a space-separated list of MTU values where a bare
+// number applies to all interfaces and "name=mtu" applies to one interface
+func (c *Config) parseExpectedMTU(val string) {
+	for _, field := range strings.Fields(val) {
+		if name, mtuStr, ok := strings.Cut(field, "="); ok {
+			if mtu, err := strconv.Atoi(mtuStr); err == nil && mtu > 0 {
+				c.InterfaceMTUs[name] = mtu
+			}
+		} else if mtu, err := strconv.Atoi(field); err == nil && mtu > 0 {
+			c.ExpectedMTU = mtu
+		}
+	}
+}
+
+// parseMinSpeed parses a space-separated list of speeds in Mbps where a bare
+// number applies to all interfaces and "name=speed" applies to one interface
+func (c *Config) parseMinSpeed(val string) {
+	for _, field := range strings.Fields(val) {
+		if name, speedStr, ok := strings.Cut(field, "="); ok {
+			if speed, err := strconv.Atoi(speedStr); err == nil && speed > 0 {
+				c.InterfaceSpeeds[name] = speed
+			}
+		} else if speed, err := strconv.Atoi(field); err == nil && speed >= 0 {
+			c.MinSpeed = speed
+		}
+	}
+}
+
+// SplitEndpoint splits a host:port endpoint, rejecting an empty host or port
+// and numeric ports outside 1-65535; named ports such as "ldap" are accepted
+func SplitEndpoint(endpoint string) (host, port string, err error) {
+	host, port, err = net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	if host == "" || port == "" {
+		return "", "", fmt.Errorf("%q needs both a host and a port", endpoint)
+	}
+	if n, err := strconv.Atoi(port); err == nil && (n < 1 || n > 65535) {
+		return "", "", fmt.Errorf("%q has port %d outside 1-65535", endpoint, n)
+	}
+	return host, port, nil
+}
+
+// TLSEndpoint returns a TLS check endpoint as host:port, defaulting to port 443
+func TLSEndpoint(endpoint string) string {
+	if _, _, err := net.SplitHostPort(endpoint); err == nil {
+		return endpoint
+	}
+	return net.JoinHostPort(strings.Trim(endpoint, "[]"), "443")
+}
+
+// parseBondMinSlaves parses a space-separated list of slave counts where a
+// bare number applies to all bonds and "name=count" applies to one bond
+func (c *Config) parseBondMinSlaves(val string) {
+	for _, field := range strings.Fields(val) {
+		if name, countStr, ok := strings.Cut(field, "="); ok {
+			if count, err := strconv.Atoi(countStr); err == nil && count > 0 {
+				c.BondSlaves[name] = count
+			}
+		} else if count, err := strconv.Atoi(field); err == nil && count >= 0 {
+			c.BondMinSlaves = count
+		}
+	}
+}
+
+// parseLACPPartnerMAC parses a space-separated list of MAC addresses where a
+// bare address applies to all bonds and "name=mac" applies to one bond
+func (c *Config) parseLACPPartnerMAC(val string) {
+	for _, field := range strings.Fields(val) {
+		if name, macStr, ok := strings.Cut(field, "="); ok {
+			if mac, err := net.ParseMAC(macStr); err == nil && name != "" {
+				c.BondPartnerMACs[name] = mac.String()
+			} else {
+				c.badPartnerMACs = append(c.badPartnerMACs, field)
+			}
+		} else if mac, err := net.ParseMAC(field); err == nil {
+			c.LACPPartnerMAC = mac.String()
+		} else {
+			c.badPartnerMACs = append(c.badPartnerMACs, field)
+		}
+	}
+}
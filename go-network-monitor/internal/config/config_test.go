@@ -0,0 +1,252 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    time.Duration
+		wantErr bool
+	}{
+		// Go duration strings
+		{val: "500ms", want: 500 * time.Millisecond},
+		{val: "1.5s", want: 1500 * time.Millisecond},
+		{val: "30m", want: 30 * time.Minute},
+		{val: "1h15m", want: 75 * time.Minute},
+		{val: " 2s ", want: 2 * time.Second},
+		{val: "0s", want: 0},
+		
+		// Bare numbers are seconds, for backward compatibility
+		{val: "5", want: 5 * time.Second},
+		{val: "0", want: 0},
+		{val: "1.5", want: 1500 * time.Millisecond},
+		{val: "0.25", want: 250 * time.Millisecond},
+		{val: "900", want: 15 * time.Minute},
+		
+		// Negative, malformed and out of range values
+		{val: "-1s", wantErr: true},
+		{val: "-5", wantErr: true},
+		{val: "", wantErr: true},
+		{val: "abc", wantErr: true},
+		{val: "5 s", wantErr: true},
+		{val: "10x", wantErr: true},
+		{val: "NaN", wantErr: true},
+		{val: "nan", wantErr: true},
+		{val: "Inf", wantErr: true},
+		{val: "+Inf", wantErr: true},
+		{val: "infinity", wantErr: true},
+		{val: "-Inf", wantErr: true},
+		{val: "1e300", wantErr: true},
+		{val: "1e400", wantErr: true},
+		{val: "9223372037", wantErr: true},
+		{val: "9999999999h", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.val)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %s, want an error", tt.val, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", tt.val, err)
+		} else if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, want %s", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestReadinessQuorumValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		quorum   string
+		advisory []string
+		exec     []string
+		wantErr  bool
+	}{
+		{name: "all", quorum: "all"},
+		{name: "every default check", quorum: "7"},
+		{name: "more than the default checks", quorum: "8", wantErr: true},
+		{name: "exec checks count", quorum: "9", exec: []string{"/usr/local/bin/check-mount /srv", "/usr/local/bin/check-mount /var"}},
+		{name: "advisory checks don't count", quorum: "7", advisory: []string{"arp"}, wantErr: true},
+		{name: "advisory exec check", quorum: "7", advisory: []string{"exec:check-mount#2"}, exec: []string{"check-mount /srv", "check-mount /var"}},
+		{name: "every check advisory", quorum: "all", advisory: []string{"interfaces", "gateway", "services", "dns", "nm_connectivity", "arp", "routing"}, wantErr: true},
+		{name: "only an exec check required", quorum: "any", advisory: []string{"interfaces", "gateway", "services", "dns", "nm_connectivity", "arp", "routing"}, exec: []string{"check-mount /srv"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultConfig()
+			c.ReadinessQuorum = tt.quorum
+			c.AdvisoryChecks = tt.advisory
+			c.ExecChecks = tt.exec
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    int
+		wantErr bool
+	}{
+		{val: "20", want: 20},
+		{val: "20%", want: 20},
+		{val: " 5% ", want: 5},
+		{val: "0", want: 0},
+		{val: "-10%", want: -10}, // Range is left to Validate
+		{val: "", wantErr: true},
+		{val: "%", wantErr: true},
+		{val: "20%%", wantErr: true},
+		{val: "12.5%", wantErr: true},
+		{val: "twenty", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		got, err := ParsePercent(tt.val)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePercent(%q) error = %v, want error %t", tt.val, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParsePercent(%q) = %d, want %d", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestSplitEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		host     string
+		port     string
+		wantErr  bool
+	}{
+		{endpoint: "proxy:3128", host: "proxy", port: "3128"},
+		{endpoint: "dc1.corp:ldap", host: "dc1.corp", port: "ldap"},
+		{endpoint: "[2001:db8::1]:443", host: "2001:db8::1", port: "443"},
+		{endpoint: "proxy:", wantErr: true},
+		{endpoint: ":3128", wantErr: true},
+		{endpoint: "proxy", wantErr: true},
+		{endpoint: "proxy:0", wantErr: true},
+		{endpoint: "proxy:65536", wantErr: true},
+		{endpoint: "2001:db8::1:443", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		host, port, err := SplitEndpoint(tt.endpoint)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SplitEndpoint(%q) error = %v, want error %t", tt.endpoint, err, tt.wantErr)
+			continue
+		}
+		if host != tt.host || port != tt.port {
+			t.Errorf("SplitEndpoint(%q) = %q, %q; want %q, %q", tt.endpoint, host, port, tt.host, tt.port)
+		}
+	}
+}
+
+func TestParseResolverHostname(t *testing.T) {
+	tests := []struct {
+		entry      string
+		hostname   string
+		recordType string
+		wantErr    bool
+	}{
+		{entry: "example.com", hostname: "example.com"},
+		{entry: "_ldap._tcp.corp/srv", hostname: "_ldap._tcp.corp", recordType: "SRV"},
+		{entry: "example.com/AAAA", hostname: "example.com", recordType: "AAAA"},
+		{entry: "example.com/SPF", wantErr: true},
+		{entry: "/A", wantErr: true},
+		{entry: "", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		hostname, recordType, err := ParseResolverHostname(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseResolverHostname(%q) error = %v, want error %t", tt.entry, err, tt.wantErr)
+			continue
+		}
+		if hostname != tt.hostname || recordType != tt.recordType {
+			t.Errorf("ParseResolverHostname(%q) = %q, %q; want %q, %q", tt.entry, hostname, recordType, tt.hostname, tt.recordType)
+		}
+	}
+}
+
+func TestReadinessCheckNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		advisory []string
+		dhcp     bool
+		wantErr  bool
+	}{
+		{name: "enabled checks", expr: "interfaces && (dns || exec:check-mount)"},
+		{name: "disabled check", expr: "interfaces && dhcp", wantErr: true},
+		{name: "enabled optional check", expr: "interfaces && dhcp", dhcp: true},
+		{name: "mistyped exec check", expr: "interfaces && exec:chek-mount", wantErr: true},
+		{name: "unknown check", expr: "interfaces && carrier", wantErr: true},
+		{name: "advisory exec check", advisory: []string{"exec:check-mount"}},
+		{name: "mistyped advisory exec check", advisory: []string{"exec:chek-mount"}, wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultConfig()
+			c.ExecChecks = []string{"/usr/local/bin/check-mount /srv"}
+			c.ReadinessExpr = tt.expr
+			c.AdvisoryChecks = tt.advisory
+			c.CheckDHCP = tt.dhcp
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProxyURLValidation(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "http://proxy.example.com:3128"},
+		{url: "https://proxy.example.com:3128"},
+		{url: "socks5://proxy.example.com:1080"},
+		{url: "socks5h://proxy.example.com:1080"},
+		{url: "ftp://proxy.example.com:21", wantErr: true},
+		{url: "proxy.example.com:3128", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			c := DefaultConfig()
+			c.CheckProxy = true
+			c.ProxyURL = tt.url
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	c := DefaultConfig()
+	next := DefaultConfig()
+	next.SleepInterval = 2 * c.SleepInterval
+	next.CheckDHCP = true
+	next.ReadinessExpr = "interfaces && dhcp"
+	
+	c.ApplyReloadable(next)
+	if got := c.RestartRequired(next); len(got) != 1 || got[0] != "check_dhcp" {
+		t.Errorf("RestartRequired() = %v, want [check_dhcp]", got)
+	}
+	if err := next.ValidateReadiness(DefaultConfig().EnabledChecks()); err == nil {
+		t.Error("ValidateReadiness() against checks without dhcp = nil, want an error")
+	}
+}
@@ -0,0 +1,806 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig mirrors Config as it appears in a YAML config file; keys are the
+// lower-cased environment variable names and absent keys leave the value unchanged
+type fileConfig struct {
+	TotalTimeout       *string   `yaml:"total_timeout"`
+	RunAfterSuccess    *string   `yaml:"run_after_success"`
+	SleepInterval      *string   `yaml:"sleep_interval"`
+	SleepJitter        *int      `yaml:"sleep_jitter"`
+	PingTimeout        *string   `yaml:"ping_timeout"`
+	DNSTimeout         *string   `yaml:"dns_timeout"`
+	SystemdTimeout     *string   `yaml:"systemd_timeout"`
+	NMTimeout          *string   `yaml:"nm_timeout"`
+	ShutdownTimeout    *string   `yaml:"shutdown_timeout"`
+	FailureThreshold   *int      `yaml:"failure_threshold"`
+	RecoveryThreshold  *int      `yaml:"recovery_threshold"`
+	CheckThresholds    *string   `yaml:"check_thresholds"`
+	FlapThreshold      *int      `yaml:"flap_threshold"`
+	FlapWindow         *string   `yaml:"flap_window"`
+	AdvisoryChecks     []string  `yaml:"advisory_checks"`
+	ReadinessQuorum    *string   `yaml:"readiness_quorum"`
+	ReadinessExpr      *string   `yaml:"readiness_expr"`
+	Blocking           *bool     `yaml:"blocking"`
+	Watch              *bool     `yaml:"watch"`
+	EventDriven        *bool     `yaml:"event_driven"`
+	EventBackstop      *string   `yaml:"event_backstop"`
+	InterfaceTypes     []string  `yaml:"interface_types"`
+	RequiredInterfaces []string  `yaml:"required_interfaces"`
+	ExcludedInterfaces []string  `yaml:"excluded_interfaces"`
+	IgnoreSRIOVVFs     *bool     `yaml:"ignore_sriov_vfs"`
+	MinInterfacesUp    *int      `yaml:"min_interfaces_up"`
+	ExpectedMTU        *string   `yaml:"expected_mtu"`
+	PathMTUProbe       *bool     `yaml:"path_mtu_probe"`
+	MinSpeed           *string   `yaml:"min_speed"`
+	BondMinSlaves      *string   `yaml:"bond_min_slaves"`
+	LACPPartnerMAC     *string   `yaml:"lacp_partner_mac"`
+	TunnelHandshakeAge *string   `yaml:"tunnel_handshake_max_age"`
+	WirelessSSIDs      []string  `yaml:"wireless_ssids"`
+	WirelessMinSignal  *int      `yaml:"wireless_min_signal"`
+	NetworkServices    []string  `yaml:"network_services"`
+	RequiredServices   []string  `yaml:"required_services"`
+	PingTargets        []string  `yaml:"ping_targets"`
+	PingPolicy         *string   `yaml:"ping_policy"`
+	PingGateway        *bool     `yaml:"ping_gateway"`
+	GatewayFamily      *string   `yaml:"gateway_family"`
+	GatewayProbe       *string   `yaml:"gateway_probe"`
+	PingCount          *int      `yaml:"ping_count"`
+	PingMaxLoss        *int      `yaml:"ping_max_loss"`
+	PingMaxRTT         *string   `yaml:"ping_max_rtt"`
+	GatewayMaxRTT      *string   `yaml:"gateway_max_rtt"`
+	ResolverHostname   fieldList `yaml:"resolver_hostname"`
+	DNSQuorum          *string   `yaml:"dns_quorum"`
+	DNSServers         []string  `yaml:"dns_servers"`
+	DNSSystemServers   *bool     `yaml:"dns_system_servers"`
+	DNSMaxLatency      *string   `yaml:"dns_max_latency"`
+	EncryptedDNS       fieldList `yaml:"dns_encrypted_servers"`
+	RouteTable         *string   `yaml:"route_table"`
+	IPv6DefaultRoute   *bool     `yaml:"require_ipv6_default_route"`
+	RequiredRoutes     []string  `yaml:"required_routes"`
+	PolicyTables       fieldList `yaml:"policy_tables"`
+	CheckDHCP          *bool     `yaml:"check_dhcp"`
+	CheckTimeSync      *bool     `yaml:"check_timesync"`
+	CheckResolved      *bool     `yaml:"check_resolved"`
+	CheckReverseDNS    *bool     `yaml:"check_reverse_dns"`
+	CheckResolvConf    *bool     `yaml:"check_resolv_conf"`
+	CheckHostname      *bool     `yaml:"check_hostname"`
+	CheckDNSSEC        *bool     `yaml:"check_dnssec"`
+	DNSSECSignedName   *string   `yaml:"dnssec_signed_name"`
+	DNSSECBogusName    *string   `yaml:"dnssec_bogus_name"`
+	CheckNDP           *bool     `yaml:"check_ndp"`
+	TCPChecks          fieldList `yaml:"tcp_checks"`
+	TCPTimeout         *string   `yaml:"tcp_timeout"`
+	HTTPChecks         []*fileHTTPCheck  `yaml:"http_checks"`
+	HTTPTimeout        *string   `yaml:"http_timeout"`
+	HTTPStatus         *string   `yaml:"http_status"`
+	CaptivePortal      *bool     `yaml:"check_captive_portal"`
+	CaptivePortalURL   *string   `yaml:"captive_portal_url"`
+	CheckProxy         *bool     `yaml:"check_proxy"`
+	ProxyURL           *string   `yaml:"proxy_url"`
+	ProxyTestURL       *string   `yaml:"proxy_test_url"`
+	HTTPViaProxy       *bool     `yaml:"http_via_proxy"`
+	TLSChecks          fieldList `yaml:"tls_checks"`
+	TLSTimeout         *string   `yaml:"tls_timeout"`
+	TLSMinDays         *int      `yaml:"tls_min_days"`
+	PathTarget         *string   `yaml:"path_target"`
+	PathHops           *int      `yaml:"path_hops"`
+	ExecChecks         []string  `yaml:"exec_checks"`
+	ExecCheckTimeout   *string   `yaml:"exec_check_timeout"`
+	ARPProbe           *bool     `yaml:"arp_probe"`
+	ARPProbeTimeout    *string   `yaml:"arp_probe_timeout"`
+	WebhookURL         *string   `yaml:"webhook_url"`
+	WebhookTimeout     *string   `yaml:"webhook_timeout"`
+	OnReady            *string   `yaml:"on_ready"`
+	OnDegraded         *string   `yaml:"on_degraded"`
+	OnTimeout          *string   `yaml:"on_timeout"`
+	HookTimeout        *string   `yaml:"hook_timeout"`
+	MetricsListen      *string   `yaml:"metrics_listen"`
+	LogFile            *string   `yaml:"log_file"`
+	LockFile           *string   `yaml:"lock_file"`
+	StateFile          *string   `yaml:"state_file"`
+	NoLogFile          *bool     `yaml:"no_log_file"`
+	
+	// Per-interface readiness rules keyed by interface name
+	Interfaces         map[string]*fileInterfacePolicy  `yaml:"interfaces,omitempty"`
+	
+	// Per-target ping settings keyed by ping target, or "gateway"
+	PingThresholds     map[string]*filePingThreshold  `yaml:"ping_thresholds,omitempty"`
+	
+	// Named overrides selected with --profile or PROFILE; top level only
+	Profiles           map[string]*fileConfig  `yaml:"profiles,omitempty"`
+}
+
+// fileInterfacePolicy mirrors InterfacePolicy; absent keys keep any value set by an earlier file
+type fileInterfacePolicy struct {
+	CarrierOnly *bool  `yaml:"carrier_only"`
+	MinSlaves   *int   `yaml:"min_slaves"`
+	RequireLACP *bool  `yaml:"require_lacp,omitempty"`
+	MTU         *int   `yaml:"mtu"`
+	MinSpeed    *int   `yaml:"min_speed"`
+}
+
+// filePingThreshold mirrors PingThreshold; absent keys keep any value set by an earlier file
+type filePingThreshold struct {
+	Count   *int     `yaml:"count,omitempty"`
+	MaxLoss *int     `yaml:"max_loss,omitempty"`
+	MaxRTT  *string  `yaml:"max_rtt,omitempty"`
+}
+
+// fileHTTPCheck mirrors HTTPCheck; a plain URL string is accepted for checks
+// that use the global settings
+type fileHTTPCheck struct {
+	URL      string   `yaml:"url"`
+	Status   *string  `yaml:"status,omitempty"`
+	Body     string   `yaml:"body,omitempty"`
+	Timeout  *string  `yaml:"timeout,omitempty"`
+	Insecure bool     `yaml:"insecure,omitempty"`
+}
+
+func (fh *fileHTTPCheck) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		fh.URL = value.Value
+		return nil
+	}
+	type plain fileHTTPCheck
+	return value.Decode((*plain)(fh))
+}
+
+// fieldList is a list that may also be written as a single space-separated
+// string, matching the environment variable format
+type fieldList []string
+
+func (fl *fieldList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*fl = strings.Fields(value.Value)
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*fl = list
+	return nil
+}
+
+// FindConfigPath returns the value of -config/--config from the command line
+// so the file can be loaded before the environment and the remaining flags
+func FindConfigPath(args []string) string {
+	return findFlagValue(args, "config")
+}
+
+// FindConfigDir returns the drop-in directory from -config-dir/--config-dir,
+// defaulting to conf.d next to the config file when that exists
+func FindConfigDir(args []string, configPath string) string {
+	if dir := findFlagValue(args, "config-dir"); dir != "" {
+		return dir
+	}
+	if configPath != "" {
+		dir := filepath.Join(filepath.Dir(configPath), "conf.d")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// findFlagValue returns the value of a string flag given as "-name value",
+// "--name value" or "-name=value"
+func findFlagValue(args []string, flagName string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue // Not a flag
+		}
+		
+		if name == flagName && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(name, flagName+"=") {
+			return strings.TrimPrefix(name, flagName+"=")
+		}
+	}
+	return ""
+}
+
+// LoadFromFile loads configuration from a YAML file; unlike environment
+// variables, unknown keys and invalid values are reported as errors
+func (c *Config) LoadFromFile(path string) error {
+	if err := c.loadFile(path); err != nil {
+		return err
+	}
+	
+	c.ConfigFile = path
+	return nil
+}
+
+// LoadFromDir merges every *.conf fragment in a drop-in directory, in lexical
+// order; later fragments override earlier ones key by key, lists included. The
+// directory must exist, so a mistyped -config-dir isn't silently ignored
+func (c *Config) LoadFromDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read config directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("config directory %s is not a directory", dir)
+	}
+	
+	fragments, err := filepath.Glob(filepath.Join(dir, "*.conf"))
+	if err != nil {
+		return fmt.Errorf("failed to list config directory %s: %w", dir, err)
+	}
+	
+	for _, fragment := range fragments {
+		if err := c.loadFile(fragment); err != nil {
+			return err
+		}
+	}
+	
+	c.ConfigDir = dir
+	c.ConfigFragments = fragments
+	return nil
+}
+
+// loadFile applies a single YAML file onto the configuration
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	
+	var fc fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	
+	if err := fc.apply(c); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	
+	// Remember profiles so the selected one can be applied once every file is loaded
+	for name, profile := range fc.Profiles {
+		if profile == nil {
+			continue
+		}
+		if profile.Profiles != nil {
+			return fmt.Errorf("invalid config file %s: profile %s: profiles can't be nested", path, name)
+		}
+		if c.profiles == nil {
+			c.profiles = make(map[string][]*fileConfig)
+		}
+		c.profiles[name] = append(c.profiles[name], profile)
+	}
+	
+	return nil
+}
+
+// FindProfile returns the profile selected with -profile/--profile, or the PROFILE environment variable
+func FindProfile(args []string) string {
+	if profile := findFlagValue(args, "profile"); profile != "" {
+		return profile
+	}
+	return os.Getenv("PROFILE")
+}
+
+// ApplyProfile applies a named profile from the loaded config files on top of
+// the top-level settings; a profile defined in several files is merged in load order
+func (c *Config) ApplyProfile(name string) error {
+	profiles, ok := c.profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	
+	for _, profile := range profiles {
+		if err := profile.apply(c); err != nil {
+			return fmt.Errorf("invalid profile %s: %w", name, err)
+		}
+	}
+	
+	c.Profile = name
+	return nil
+}
+
+// ProfileNames returns the names of the profiles defined in the loaded config files
+func (c *Config) ProfileNames() []string {
+	var names []string
+	for name := range c.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apply copies every value present in the file onto the configuration
+func (fc *fileConfig) apply(c *Config) error {
+	durations := []struct {
+		name   string
+		value  *string
+		target *time.Duration
+	}{
+		{"total_timeout", fc.TotalTimeout, &c.TotalTimeout},
+		{"run_after_success", fc.RunAfterSuccess, &c.RunAfterSuccess},
+		{"sleep_interval", fc.SleepInterval, &c.SleepInterval},
+		{"ping_timeout", fc.PingTimeout, &c.PingTimeout},
+		{"dns_timeout", fc.DNSTimeout, &c.DNSTimeout},
+		{"systemd_timeout", fc.SystemdTimeout, &c.SystemdTimeout},
+		{"nm_timeout", fc.NMTimeout, &c.NMTimeout},
+		{"shutdown_timeout", fc.ShutdownTimeout, &c.ShutdownTimeout},
+		{"event_backstop", fc.EventBackstop, &c.EventBackstop},
+		{"tcp_timeout", fc.TCPTimeout, &c.TCPTimeout},
+		{"http_timeout", fc.HTTPTimeout, &c.HTTPTimeout},
+		{"tls_timeout", fc.TLSTimeout, &c.TLSTimeout},
+		{"exec_check_timeout", fc.ExecCheckTimeout, &c.ExecCheckTimeout},
+		{"arp_probe_timeout", fc.ARPProbeTimeout, &c.ARPProbeTimeout},
+		{"webhook_timeout", fc.WebhookTimeout, &c.WebhookTimeout},
+		{"hook_timeout", fc.HookTimeout, &c.HookTimeout},
+		{"ping_max_rtt", fc.PingMaxRTT, &c.PingMaxRTT},
+		{"gateway_max_rtt", fc.GatewayMaxRTT, &c.GatewayMaxRTT},
+		{"dns_max_latency", fc.DNSMaxLatency, &c.DNSMaxLatency},
+		{"flap_window", fc.FlapWindow, &c.FlapWindow},
+		{"tunnel_handshake_max_age", fc.TunnelHandshakeAge, &c.TunnelHandshakeAge},
+	}
+	for _, d := range durations {
+		if d.value == nil {
+			continue
+		}
+		duration, err := ParseDuration(*d.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", d.name, err)
+		}
+		*d.target = duration
+	}
+	
+	if fc.SleepJitter != nil {
+		c.SleepJitter = *fc.SleepJitter
+	}
+	
+	if fc.FailureThreshold != nil {
+		if *fc.FailureThreshold <= 0 {
+			return fmt.Errorf("failure_threshold: must be positive")
+		}
+		c.FailureThreshold = *fc.FailureThreshold
+	}
+	
+	if fc.RecoveryThreshold != nil {
+		// 0 means the same as failure_threshold, as --validate-config prints it
+		if *fc.RecoveryThreshold < 0 {
+			return fmt.Errorf("recovery_threshold: must not be negative")
+		}
+		c.RecoveryThreshold = *fc.RecoveryThreshold
+	}
+	
+	if fc.FlapThreshold != nil {
+		if *fc.FlapThreshold < 0 {
+			return fmt.Errorf("flap_threshold: must not be negative")
+		}
+		c.FlapThreshold = *fc.FlapThreshold
+	}
+	
+	if fc.CheckThresholds != nil {
+		thresholds, err := ParseCheckThresholds(*fc.CheckThresholds)
+		if err != nil {
+			return fmt.Errorf("check_thresholds: %w", err)
+		}
+		c.CheckThresholds = thresholds
+	}
+	
+	if fc.AdvisoryChecks != nil {
+		c.AdvisoryChecks = checkNames(fc.AdvisoryChecks)
+	}
+	
+	if fc.ReadinessQuorum != nil {
+		if _, err := ParseQuorum(*fc.ReadinessQuorum, 1); err != nil {
+			return fmt.Errorf("readiness_quorum: %w", err)
+		}
+		c.ReadinessQuorum = *fc.ReadinessQuorum
+	}
+	
+	if fc.ReadinessExpr != nil {
+		c.ReadinessExpr = *fc.ReadinessExpr
+	}
+	
+	if fc.Blocking != nil {
+		c.BlockingMode = *fc.Blocking
+	}
+	
+	if fc.Watch != nil {
+		c.Watch = *fc.Watch
+	}
+	
+	if fc.EventDriven != nil {
+		c.EventDriven = *fc.EventDriven
+	}
+	
+	if fc.InterfaceTypes != nil {
+		c.InterfaceTypes = fc.InterfaceTypes
+	}
+	
+	if fc.RequiredInterfaces != nil {
+		c.RequiredInterfaces = fc.RequiredInterfaces
+	}
+	
+	if fc.ExcludedInterfaces != nil {
+		c.ExcludedInterfaces = fc.ExcludedInterfaces
+	}
+	
+	if fc.IgnoreSRIOVVFs != nil {
+		c.IgnoreSRIOVVFs = *fc.IgnoreSRIOVVFs
+	}
+	
+	if fc.MinInterfacesUp != nil {
+		c.MinInterfacesUp = *fc.MinInterfacesUp
+	}
+	
+	if fc.ExpectedMTU != nil {
+		c.parseExpectedMTU(*fc.ExpectedMTU)
+	}
+	
+	if fc.PathMTUProbe != nil {
+		c.PathMTUProbe = *fc.PathMTUProbe
+	}
+	
+	if fc.MinSpeed != nil {
+		c.parseMinSpeed(*fc.MinSpeed)
+	}
+	
+	if fc.BondMinSlaves != nil {
+		c.parseBondMinSlaves(*fc.BondMinSlaves)
+	}
+	
+	if fc.LACPPartnerMAC != nil {
+		c.parseLACPPartnerMAC(*fc.LACPPartnerMAC)
+	}
+	
+	if fc.WirelessSSIDs != nil {
+		c.WirelessSSIDs = fc.WirelessSSIDs
+	}
+	
+	if fc.WirelessMinSignal != nil {
+		c.WirelessMinSignal = *fc.WirelessMinSignal
+	}
+	
+	if fc.NetworkServices != nil {
+		c.NetworkServices = fc.NetworkServices
+	}
+	
+	if fc.RequiredServices != nil {
+		c.RequiredServices = fc.RequiredServices
+	}
+	
+	if fc.PingTargets != nil {
+		c.PingTargets = fc.PingTargets
+	}
+	
+	if fc.PingPolicy != nil {
+		if *fc.PingPolicy != "any" && *fc.PingPolicy != "all" {
+			return fmt.Errorf("ping_policy: must be 'all' or 'any'")
+		}
+		c.PingPolicy = *fc.PingPolicy
+	}
+	
+	if fc.PingGateway != nil {
+		c.PingGateway = *fc.PingGateway
+	}
+	
+	if fc.PingCount != nil {
+		if *fc.PingCount <= 0 {
+			return fmt.Errorf("ping_count: must be positive")
+		}
+		c.PingCount = *fc.PingCount
+	}
+	
+	if fc.PingMaxLoss != nil {
+		c.PingMaxLoss = *fc.PingMaxLoss
+	}
+	
+	if fc.GatewayFamily != nil {
+		if !IsGatewayFamily(*fc.GatewayFamily) {
+			return fmt.Errorf("gateway_family: must be 'ipv4', 'ipv6', 'either' or 'both'")
+		}
+		c.GatewayFamily = *fc.GatewayFamily
+	}
+	
+	if fc.GatewayProbe != nil {
+		if !IsGatewayProbe(*fc.GatewayProbe) {
+			return fmt.Errorf("gateway_probe: must be 'icmp', 'arp', 'any' or 'all'")
+		}
+		c.GatewayProbe = *fc.GatewayProbe
+	}
+	
+	if fc.ResolverHostname != nil {
+		c.ResolverHostnames = fc.ResolverHostname
+	}
+	
+	if fc.DNSQuorum != nil {
+		if _, err := ParseQuorum(*fc.DNSQuorum, 1); err != nil {
+			return fmt.Errorf("dns_quorum: %w", err)
+		}
+		c.DNSQuorum = *fc.DNSQuorum
+	}
+	
+	if fc.DNSServers != nil {
+		c.DNSServers = fc.DNSServers
+	}
+	
+	if fc.EncryptedDNS != nil {
+		c.EncryptedDNS = fc.EncryptedDNS
+	}
+	
+	if fc.DNSSystemServers != nil {
+		c.DNSSystemServers = *fc.DNSSystemServers
+	}
+	
+	if fc.RouteTable != nil {
+		table, err := ParseRouteTable(*fc.RouteTable)
+		if err != nil {
+			return fmt.Errorf("route_table: %w", err)
+		}
+		c.RouteTable = table
+	}
+	
+	if fc.IPv6DefaultRoute != nil {
+		c.IPv6DefaultRoute = *fc.IPv6DefaultRoute
+	}
+	
+	if fc.RequiredRoutes != nil {
+		c.RequiredRoutes = fc.RequiredRoutes
+	}
+	
+	if fc.PolicyTables != nil {
+		c.PolicyTables = fc.PolicyTables
+	}
+	
+	if fc.CheckDHCP != nil {
+		c.CheckDHCP = *fc.CheckDHCP
+	}
+	
+	if fc.CheckTimeSync != nil {
+		c.CheckTimeSync = *fc.CheckTimeSync
+	}
+	
+	if fc.CheckResolved != nil {
+		c.CheckResolved = *fc.CheckResolved
+	}
+	
+	if fc.CheckReverseDNS != nil {
+		c.CheckReverseDNS = *fc.CheckReverseDNS
+	}
+	
+	if fc.CheckResolvConf != nil {
+		c.CheckResolvConf = *fc.CheckResolvConf
+	}
+	
+	if fc.CheckHostname != nil {
+		c.CheckHostname = *fc.CheckHostname
+	}
+	
+	if fc.CheckDNSSEC != nil {
+		c.CheckDNSSEC = *fc.CheckDNSSEC
+	}
+	
+	if fc.DNSSECSignedName != nil {
+		c.DNSSECSignedName = *fc.DNSSECSignedName
+	}
+	
+	if fc.DNSSECBogusName != nil && *fc.DNSSECBogusName == "none" {
+		c.DNSSECBogusName = ""
+	} else if fc.DNSSECBogusName != nil {
+		c.DNSSECBogusName = *fc.DNSSECBogusName
+	}
+	
+	if fc.CheckNDP != nil {
+		c.CheckNDP = *fc.CheckNDP
+	}
+	
+	if fc.TCPChecks != nil {
+		c.TCPChecks = fc.TCPChecks
+	}
+	
+	if fc.HTTPChecks != nil {
+		checks := make([]HTTPCheck, 0, len(fc.HTTPChecks))
+		for i, fh := range fc.HTTPChecks {
+			if fh == nil || fh.URL == "" {
+				return fmt.Errorf("http_checks[%d]: url is required", i)
+			}
+			check := HTTPCheck{URL: fh.URL, Body: fh.Body, Insecure: fh.Insecure}
+			if fh.Status != nil {
+				check.Status = *fh.Status
+			}
+			if fh.Timeout != nil {
+				timeout, err := ParseDuration(*fh.Timeout)
+				if err != nil {
+					return fmt.Errorf("http_checks[%d].timeout: %w", i, err)
+				}
+				check.Timeout = timeout
+			}
+			checks = append(checks, check)
+		}
+		c.HTTPChecks = checks
+	}
+	
+	if fc.HTTPStatus != nil {
+		c.HTTPStatus = *fc.HTTPStatus
+	}
+	
+	if fc.CaptivePortal != nil {
+		c.CaptivePortal = *fc.CaptivePortal
+	}
+	
+	if fc.CaptivePortalURL != nil {
+		c.CaptivePortalURL = *fc.CaptivePortalURL
+	}
+	
+	if fc.CheckProxy != nil {
+		c.CheckProxy = *fc.CheckProxy
+	}
+	
+	if fc.ProxyURL != nil {
+		c.ProxyURL = *fc.ProxyURL
+	}
+	
+	if fc.ProxyTestURL != nil {
+		c.ProxyTestURL = *fc.ProxyTestURL
+	}
+	
+	if fc.HTTPViaProxy != nil {
+		c.HTTPViaProxy = *fc.HTTPViaProxy
+	}
+	
+	if fc.TLSChecks != nil {
+		c.TLSChecks = fc.TLSChecks
+	}
+	
+	if fc.TLSMinDays != nil {
+		c.TLSMinDays = *fc.TLSMinDays
+	}
+	
+	if fc.PathTarget != nil {
+		c.PathTarget = *fc.PathTarget
+	}
+	
+	if fc.PathHops != nil {
+		c.PathHops = *fc.PathHops
+	}
+	
+	if fc.ExecChecks != nil {
+		c.ExecChecks = fc.ExecChecks
+	}
+	
+	if fc.ARPProbe != nil {
+		c.ARPProbe = *fc.ARPProbe
+	}
+	
+	if fc.WebhookURL != nil {
+		c.WebhookURL = *fc.WebhookURL
+	}
+	
+	if fc.OnReady != nil {
+		c.OnReady = *fc.OnReady
+	}
+	
+	if fc.OnDegraded != nil {
+		c.OnDegraded = *fc.OnDegraded
+	}
+	
+	if fc.OnTimeout != nil {
+		c.OnTimeout = *fc.OnTimeout
+	}
+	
+	if fc.MetricsListen != nil {
+		c.MetricsListen = *fc.MetricsListen
+	}
+	
+	if fc.LogFile != nil {
+		c.LogFile = *fc.LogFile
+	}
+	
+	if fc.LockFile != nil {
+		c.LockFile = *fc.LockFile
+	}
+	
+	if fc.StateFile != nil {
+		c.StateFile = *fc.StateFile
+	}
+	
+	if fc.NoLogFile != nil {
+		c.NoLogFile = *fc.NoLogFile
+	}
+	
+	for name, fp := range fc.Interfaces {
+		if fp == nil {
+			continue
+		}
+		
+		policy := c.InterfacePolicies[name]
+		if fp.CarrierOnly != nil {
+			policy.CarrierOnly = *fp.CarrierOnly
+		}
+		if fp.MinSlaves != nil {
+			if *fp.MinSlaves < 0 {
+				return fmt.Errorf("interfaces.%s.min_slaves: must not be negative", name)
+			}
+			policy.MinSlaves = *fp.MinSlaves
+		}
+		if fp.RequireLACP != nil {
+			requireLACP := *fp.RequireLACP
+			policy.RequireLACP = &requireLACP
+		}
+		if fp.MTU != nil {
+			if *fp.MTU <= 0 {
+				return fmt.Errorf("interfaces.%s.mtu: must be positive", name)
+			}
+			policy.MTU = *fp.MTU
+		}
+		if fp.MinSpeed != nil {
+			if *fp.MinSpeed < 0 {
+				return fmt.Errorf("interfaces.%s.min_speed: must not be negative", name)
+			}
+			policy.MinSpeed = *fp.MinSpeed
+		}
+		
+		// Copy on write so the default map is never shared between configs
+		policies := make(map[string]InterfacePolicy, len(c.InterfacePolicies)+1)
+		for n, p := range c.InterfacePolicies {
+			policies[n] = p
+		}
+		policies[name] = policy
+		c.InterfacePolicies = policies
+	}
+	
+	for target, ft := range fc.PingThresholds {
+		if ft == nil {
+			continue
+		}
+		
+		// Hostnames are case-insensitive
+		target = strings.ToLower(target)
+		threshold := c.PingThresholds[target]
+		if ft.Count != nil {
+			if *ft.Count <= 0 {
+				return fmt.Errorf("ping_thresholds.%s.count: must be positive", target)
+			}
+			threshold.Count = *ft.Count
+		}
+		if ft.MaxLoss != nil {
+			maxLoss := *ft.MaxLoss
+			threshold.MaxLoss = &maxLoss
+		}
+		if ft.MaxRTT != nil {
+			rtt, err := ParseDuration(*ft.MaxRTT)
+			if err != nil {
+				return fmt.Errorf("ping_thresholds.%s.max_rtt: %w", target, err)
+			}
+			threshold.MaxRTT = rtt
+		}
+		
+		// Copy on write so the default map is never shared between configs
+		thresholds := make(map[string]PingThreshold, len(c.PingThresholds)+1)
+		for t, pt := range c.PingThresholds {
+			thresholds[t] = pt
+		}
+		thresholds[target] = threshold
+		c.PingThresholds = thresholds
+	}
+	
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDNSSECBogusNameNone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("dnssec_bogus_name: none\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	c := DefaultConfig()
+	if err := c.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if c.DNSSECBogusName != "" {
+		t.Fatalf("DNSSECBogusName = %q, want the bogus name test disabled", c.DNSSECBogusName)
+	}
+	
+	// The printed configuration loads back with the test still disabled
+	var out bytes.Buffer
+	if err := c.WriteYAML(&out); err != nil {
+		t.Fatalf("WriteYAML: %v", err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := DefaultConfig()
+	if err := reloaded.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile of WriteYAML output: %v", err)
+	}
+	if reloaded.DNSSECBogusName != "" {
+		t.Errorf("DNSSECBogusName after a round trip = %q, want it disabled", reloaded.DNSSECBogusName)
+	}
+}
+
+func TestLoadFromDirMissing(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "10-site.conf")
+	if err := os.WriteFile(file, []byte("dnssec_bogus_name: none\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	for _, path := range []string{filepath.Join(dir, "conf.d"), file} {
+		if err := DefaultConfig().LoadFromDir(path); err == nil {
+			t.Errorf("LoadFromDir(%s) succeeded, want an error", path)
+		}
+	}
+	
+	c := DefaultConfig()
+	if err := c.LoadFromDir(dir); err != nil {
+		t.Fatalf("LoadFromDir(%s): %v", dir, err)
+	}
+	if len(c.ConfigFragments) != 1 || c.DNSSECBogusName != "" {
+		t.Errorf("fragments %v, DNSSECBogusName %q; want 10-site.conf applied", c.ConfigFragments, c.DNSSECBogusName)
+	}
+}
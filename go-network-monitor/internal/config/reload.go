@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Load builds the configuration from defaults, the config file, its drop-in
+// fragments, the selected profile, the environment and the command line flags,
+// in increasing order of precedence
+func Load() (*Config, error) {
+	c := DefaultConfig()
+	path := FindConfigPath(os.Args[1:])
+	if path != "" {
+		if err := c.LoadFromFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if dir := FindConfigDir(os.Args[1:], path); dir != "" {
+		if err := c.LoadFromDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	if profile := FindProfile(os.Args[1:]); profile != "" {
+		if err := c.ApplyProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	c.LoadFromEnv()
+	if err := c.ParseFlags(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ApplyReloadable copies the options that are safe to change while running
+// from next and returns a description of each change; everything else
+// (timeouts baked into checkers, paths, listeners, enabled checks) needs a restart
+func (c *Config) ApplyReloadable(next *Config) []string {
+	var changes []string
+	
+	if !reflect.DeepEqual(c.ResolverHostnames, next.ResolverHostnames) || c.DNSQuorum != next.DNSQuorum {
+		changes = append(changes, fmt.Sprintf("resolver hostnames [%s] (%s) -> [%s] (%s)",
+			strings.Join(c.ResolverHostnames, " "), c.DNSQuorum, strings.Join(next.ResolverHostnames, " "), next.DNSQuorum))
+		c.ResolverHostnames = next.ResolverHostnames
+		c.DNSQuorum = next.DNSQuorum
+	}
+	
+	if !reflect.DeepEqual(c.DNSServers, next.DNSServers) {
+		changes = append(changes, fmt.Sprintf("DNS servers [%s] -> [%s]",
+			strings.Join(c.DNSServers, " "), strings.Join(next.DNSServers, " ")))
+		c.DNSServers = next.DNSServers
+	}
+	
+	if c.DNSSystemServers != next.DNSSystemServers {
+		changes = append(changes, fmt.Sprintf("DNS system servers %t -> %t", c.DNSSystemServers, next.DNSSystemServers))
+		c.DNSSystemServers = next.DNSSystemServers
+	}
+	
+	if c.DNSMaxLatency != next.DNSMaxLatency {
+		changes = append(changes, fmt.Sprintf("DNS max latency %s -> %s", c.DNSMaxLatency, next.DNSMaxLatency))
+		c.DNSMaxLatency = next.DNSMaxLatency
+	}
+	
+	if !reflect.DeepEqual(c.RequiredInterfaces, next.RequiredInterfaces) {
+		changes = append(changes, fmt.Sprintf("required interfaces [%s] -> [%s]",
+			strings.Join(c.RequiredInterfaces, " "), strings.Join(next.RequiredInterfaces, " ")))
+		c.RequiredInterfaces = next.RequiredInterfaces
+	}
+	
+	if c.MinInterfacesUp != next.MinInterfacesUp {
+		changes = append(changes, fmt.Sprintf("minimum interfaces up %d -> %d", c.MinInterfacesUp, next.MinInterfacesUp))
+		c.MinInterfacesUp = next.MinInterfacesUp
+	}
+	
+	if !reflect.DeepEqual(c.RequiredServices, next.RequiredServices) {
+		changes = append(changes, fmt.Sprintf("required services [%s] -> [%s]",
+			strings.Join(c.RequiredServices, " "), strings.Join(next.RequiredServices, " ")))
+		c.RequiredServices = next.RequiredServices
+	}
+	
+	if c.SleepInterval != next.SleepInterval && next.SleepInterval > 0 {
+		changes = append(changes, fmt.Sprintf("sleep interval %s -> %s", c.SleepInterval, next.SleepInterval))
+		c.SleepInterval = next.SleepInterval
+	}
+	
+	if c.SleepJitter != next.SleepJitter {
+		changes = append(changes, fmt.Sprintf("sleep jitter %d%% -> %d%%", c.SleepJitter, next.SleepJitter))
+		c.SleepJitter = next.SleepJitter
+	}
+	
+	if c.RunAfterSuccess != next.RunAfterSuccess && !c.BlockingMode {
+		changes = append(changes, fmt.Sprintf("run after success %s -> %s", c.RunAfterSuccess, next.RunAfterSuccess))
+		c.RunAfterSuccess = next.RunAfterSuccess
+	}
+	
+	if !reflect.DeepEqual(c.AdvisoryChecks, next.AdvisoryChecks) {
+		changes = append(changes, fmt.Sprintf("advisory checks [%s] -> [%s]",
+			strings.Join(c.AdvisoryChecks, " "), strings.Join(next.AdvisoryChecks, " ")))
+		c.AdvisoryChecks = next.AdvisoryChecks
+	}
+	
+	if c.ReadinessQuorum != next.ReadinessQuorum {
+		changes = append(changes, fmt.Sprintf("readiness quorum %s -> %s", c.ReadinessQuorum, next.ReadinessQuorum))
+		c.ReadinessQuorum = next.ReadinessQuorum
+	}
+	
+	if c.ReadinessExpr != next.ReadinessExpr {
+		changes = append(changes, fmt.Sprintf("readiness expression %q -> %q", c.ReadinessExpr, next.ReadinessExpr))
+		c.ReadinessExpr = next.ReadinessExpr
+	}
+	
+	if c.FailureThreshold != next.FailureThreshold {
+		changes = append(changes, fmt.Sprintf("failure threshold %d -> %d", c.FailureThreshold, next.FailureThreshold))
+		c.FailureThreshold = next.FailureThreshold
+	}
+	
+	if c.RecoveryThreshold != next.RecoveryThreshold {
+		changes = append(changes, fmt.Sprintf("recovery threshold %d -> %d", c.RecoveryThreshold, next.RecoveryThreshold))
+		c.RecoveryThreshold = next.RecoveryThreshold
+	}
+	
+	if c.FlapThreshold != next.FlapThreshold || c.FlapWindow != next.FlapWindow {
+		changes = append(changes, fmt.Sprintf("flap detection %d in %s -> %d in %s",
+			c.FlapThreshold, c.FlapWindow, next.FlapThreshold, next.FlapWindow))
+		c.FlapThreshold = next.FlapThreshold
+		c.FlapWindow = next.FlapWindow
+	}
+	
+	if !reflect.DeepEqual(c.CheckThresholds, next.CheckThresholds) {
+		changes = append(changes, "per-check thresholds")
+		c.CheckThresholds = next.CheckThresholds
+	}
+	
+	if !reflect.DeepEqual(c.PingTargets, next.PingTargets) || c.PingPolicy != next.PingPolicy || c.PingGateway != next.PingGateway {
+		changes = append(changes, fmt.Sprintf("ping targets [%s] (%s) -> [%s] (%s)",
+			strings.Join(c.PingTargets, " "), c.PingPolicy, strings.Join(next.PingTargets, " "), next.PingPolicy))
+		c.PingTargets = next.PingTargets
+		c.PingPolicy = next.PingPolicy
+		c.PingGateway = next.PingGateway
+	}
+	
+	if c.PingCount != next.PingCount || c.PingMaxLoss != next.PingMaxLoss || c.PingMaxRTT != next.PingMaxRTT ||
+		c.GatewayMaxRTT != next.GatewayMaxRTT || !reflect.DeepEqual(c.PingThresholds, next.PingThresholds) {
+		changes = append(changes, "ping thresholds")
+		c.PingCount = next.PingCount
+		c.PingMaxLoss = next.PingMaxLoss
+		c.PingMaxRTT = next.PingMaxRTT
+		c.GatewayMaxRTT = next.GatewayMaxRTT
+		c.PingThresholds = next.PingThresholds
+	}
+	
+	if c.GatewayFamily != next.GatewayFamily {
+		changes = append(changes, fmt.Sprintf("gateway family %s -> %s", c.GatewayFamily, next.GatewayFamily))
+		c.GatewayFamily = next.GatewayFamily
+	}
+	
+	if c.GatewayProbe != next.GatewayProbe {
+		changes = append(changes, fmt.Sprintf("gateway probe %s -> %s", c.GatewayProbe, next.GatewayProbe))
+		c.GatewayProbe = next.GatewayProbe
+	}
+	
+	if !reflect.DeepEqual(c.RequiredRoutes, next.RequiredRoutes) {
+		changes = append(changes, fmt.Sprintf("required routes [%s] -> [%s]",
+			strings.Join(c.RequiredRoutes, "; "), strings.Join(next.RequiredRoutes, "; ")))
+		c.RequiredRoutes = next.RequiredRoutes
+	}
+	
+	if !reflect.DeepEqual(c.PolicyTables, next.PolicyTables) {
+		changes = append(changes, fmt.Sprintf("policy tables [%s] -> [%s]",
+			strings.Join(c.PolicyTables, " "), strings.Join(next.PolicyTables, " ")))
+		c.PolicyTables = next.PolicyTables
+	}
+	
+	if c.ExpectedMTU != next.ExpectedMTU || !reflect.DeepEqual(c.InterfaceMTUs, next.InterfaceMTUs) {
+		changes = append(changes, "expected MTU")
+		c.ExpectedMTU = next.ExpectedMTU
+		c.InterfaceMTUs = next.InterfaceMTUs
+	}
+	
+	if !reflect.DeepEqual(c.InterfacePolicies, next.InterfacePolicies) {
+		changes = append(changes, "interface policies")
+		c.InterfacePolicies = next.InterfacePolicies
+	}
+	
+	if c.MinSpeed != next.MinSpeed || !reflect.DeepEqual(c.InterfaceSpeeds, next.InterfaceSpeeds) {
+		changes = append(changes, "minimum speed")
+		c.MinSpeed = next.MinSpeed
+		c.InterfaceSpeeds = next.InterfaceSpeeds
+	}
+	
+	if c.BondMinSlaves != next.BondMinSlaves || !reflect.DeepEqual(c.BondSlaves, next.BondSlaves) {
+		changes = append(changes, "bond minimum slaves")
+		c.BondMinSlaves = next.BondMinSlaves
+		c.BondSlaves = next.BondSlaves
+	}
+	
+	if c.LACPPartnerMAC != next.LACPPartnerMAC || !reflect.DeepEqual(c.BondPartnerMACs, next.BondPartnerMACs) {
+		changes = append(changes, "LACP partner")
+		c.LACPPartnerMAC = next.LACPPartnerMAC
+		c.BondPartnerMACs = next.BondPartnerMACs
+	}
+	
+	if c.TunnelHandshakeAge != next.TunnelHandshakeAge {
+		changes = append(changes, fmt.Sprintf("tunnel handshake max age %s -> %s", c.TunnelHandshakeAge, next.TunnelHandshakeAge))
+		c.TunnelHandshakeAge = next.TunnelHandshakeAge
+	}
+	
+	if !reflect.DeepEqual(c.WirelessSSIDs, next.WirelessSSIDs) || c.WirelessMinSignal != next.WirelessMinSignal {
+		changes = append(changes, "wireless requirements")
+		c.WirelessSSIDs = next.WirelessSSIDs
+		c.WirelessMinSignal = next.WirelessMinSignal
+	}
+	
+	return changes
+}
+
+// RestartRequired returns the options that differ between c and next but only
+// take effect after a restart, named by their config file keys. Call it after
+// ApplyReloadable, which has already copied everything else.
+func (c *Config) RestartRequired(next *Config) []string {
+	keys := make(map[string]string)
+	ft := reflect.TypeOf(fileConfig{})
+	for i := 0; i < ft.NumField(); i++ {
+		keys[ft.Field(i).Name] = ft.Field(i).Tag.Get("yaml")
+	}
+	
+	var names []string
+	cur, nv := reflect.ValueOf(c).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < cur.NumField(); i++ {
+		field := cur.Type().Field(i)
+		if !field.IsExported() || reflect.DeepEqual(cur.Field(i).Interface(), nv.Field(i).Interface()) {
+			continue
+		}
+		name := keys[field.Name]
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
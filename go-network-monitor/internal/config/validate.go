@@ -0,0 +1,655 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/readiness"
+	"gopkg.in/yaml.v3"
+)
+
+// validInterfaceTypes lists the interface types the interface monitor understands
+var validInterfaceTypes = []string{"ethernet", "bond", "bridge", "wireless", "tunnel", "infiniband", "vlan", "macvlan", "ipvlan", "veth", "dummy", "other"}
+
+// validChecks lists the built-in check names; external checks are named "exec:<command>"
+var validChecks = func() []string {
+	var names []string
+	for _, check := range builtinChecks {
+		names = append(names, check.name)
+	}
+	return names
+}()
+
+// validRecordTypes lists the DNS record types a resolver hostname can request
+var validRecordTypes = []string{"A", "AAAA", "SRV", "MX"}
+
+// maxPathHops bounds the path check, which probes its hops one after another
+const maxPathHops = 30
+
+// validUnitSuffixes lists the systemd unit types accepted as network services
+var validUnitSuffixes = []string{
+	".service", ".socket", ".target", ".device", ".mount", ".automount",
+	".swap", ".path", ".timer", ".slice", ".scope",
+}
+
+// Validate rejects option combinations that can't work; every problem found
+// is reported, joined into a single error
+func (c *Config) Validate() error {
+	var errs []error
+	
+	positive := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"total_timeout", c.TotalTimeout},
+		{"sleep_interval", c.SleepInterval},
+		{"ping_timeout", c.PingTimeout},
+		{"dns_timeout", c.DNSTimeout},
+		{"systemd_timeout", c.SystemdTimeout},
+		{"nm_timeout", c.NMTimeout},
+		{"shutdown_timeout", c.ShutdownTimeout},
+		{"event_backstop", c.EventBackstop},
+		{"tcp_timeout", c.TCPTimeout},
+		{"http_timeout", c.HTTPTimeout},
+		{"tls_timeout", c.TLSTimeout},
+		{"exec_check_timeout", c.ExecCheckTimeout},
+		{"arp_probe_timeout", c.ARPProbeTimeout},
+		{"webhook_timeout", c.WebhookTimeout},
+		{"hook_timeout", c.HookTimeout},
+		{"flap_window", c.FlapWindow},
+		{"tunnel_handshake_max_age", c.TunnelHandshakeAge},
+	}
+	for _, d := range positive {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be positive, got %s", d.name, d.value))
+		}
+	}
+	
+	if c.RunAfterSuccess < 0 {
+		errs = append(errs, fmt.Errorf("run_after_success: must not be negative, got %s", c.RunAfterSuccess))
+	}
+	
+	// Blocking mode exits as soon as the network is ready, so run_after_success
+	// doesn't apply there
+	if !c.BlockingMode && c.RunAfterSuccess > c.TotalTimeout {
+		errs = append(errs, fmt.Errorf("run_after_success: %s exceeds total_timeout %s", c.RunAfterSuccess, c.TotalTimeout))
+	}
+	
+	if c.SleepJitter < 0 || c.SleepJitter >= 100 {
+		errs = append(errs, fmt.Errorf("sleep_jitter: must be a percentage from 0 to 99, got %d", c.SleepJitter))
+	}
+	
+	if c.FailureThreshold < 1 {
+		errs = append(errs, fmt.Errorf("failure_threshold: must be at least 1, got %d", c.FailureThreshold))
+	}
+	
+	if c.RecoveryThreshold < 0 {
+		errs = append(errs, fmt.Errorf("recovery_threshold: must not be negative, got %d", c.RecoveryThreshold))
+	}
+	
+	if c.FlapThreshold < 0 {
+		errs = append(errs, fmt.Errorf("flap_threshold: must not be negative, got %d", c.FlapThreshold))
+	}
+	
+	for check := range c.CheckThresholds {
+		if !containsFold(validChecks, check) && !strings.HasPrefix(check, "exec:") {
+			errs = append(errs, fmt.Errorf("check_thresholds: unknown check %q", check))
+		}
+	}
+	
+	patterns := []struct {
+		name  string
+		value []string
+	}{
+		{"required_interfaces", c.RequiredInterfaces},
+		{"excluded_interfaces", c.ExcludedInterfaces},
+	}
+	for _, p := range patterns {
+		for _, pattern := range p.value {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid pattern %q", p.name, pattern))
+			}
+		}
+	}
+	
+	errs = append(errs, c.validateReadiness(c.EnabledChecks())...)
+	
+	if c.Output != "text" && c.Output != "json" {
+		errs = append(errs, fmt.Errorf("output: must be 'text' or 'json', got %q", c.Output))
+	} else if c.Output == "json" && !c.Once {
+		errs = append(errs, fmt.Errorf("output: json is only supported with -once"))
+	}
+	
+	if len(c.InterfaceTypes) == 0 {
+		errs = append(errs, fmt.Errorf("interface_types: no interface types to monitor"))
+	}
+	for _, t := range c.InterfaceTypes {
+		if !containsFold(validInterfaceTypes, t) {
+			errs = append(errs, fmt.Errorf("interface_types: unknown type %q (valid: %s)", t, strings.Join(validInterfaceTypes, ", ")))
+		}
+	}
+	
+	for _, service := range append(append([]string{}, c.NetworkServices...), c.RequiredServices...) {
+		if !hasUnitSuffix(service) {
+			errs = append(errs, fmt.Errorf("network_services: %q has no known systemd unit suffix (e.g. .service)", service))
+		}
+	}
+	
+	if c.PingPolicy != "all" && c.PingPolicy != "any" {
+		errs = append(errs, fmt.Errorf("ping_policy: must be 'all' or 'any', got %q", c.PingPolicy))
+	}
+	
+	if c.PingCount < 1 {
+		errs = append(errs, fmt.Errorf("ping_count: must be at least 1, got %d", c.PingCount))
+	}
+	
+	if c.PingMaxLoss < 0 || c.PingMaxLoss >= 100 {
+		errs = append(errs, fmt.Errorf("ping_max_loss: must be a percentage from 0 to 99, got %d", c.PingMaxLoss))
+	}
+	
+	if c.PingMaxRTT < 0 {
+		errs = append(errs, fmt.Errorf("ping_max_rtt: must not be negative, got %s", c.PingMaxRTT))
+	}
+	if c.GatewayMaxRTT < 0 {
+		errs = append(errs, fmt.Errorf("gateway_max_rtt: must not be negative, got %s", c.GatewayMaxRTT))
+	}
+	
+	for target, t := range c.PingThresholds {
+		if target != "gateway" && !containsFold(c.PingTargets, target) {
+			errs = append(errs, fmt.Errorf("ping_thresholds: %q is neither a ping target nor 'gateway'", target))
+		}
+		if t.MaxLoss != nil && (*t.MaxLoss < 0 || *t.MaxLoss >= 100) {
+			errs = append(errs, fmt.Errorf("ping_thresholds.%s.max_loss: must be a percentage from 0 to 99, got %d", target, *t.MaxLoss))
+		}
+	}
+	
+	for _, endpoint := range c.TCPChecks {
+		if _, _, err := SplitEndpoint(endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("tcp_checks: %q is not host:port", endpoint))
+		}
+	}
+	
+	for _, endpoint := range c.TLSChecks {
+		if _, _, err := SplitEndpoint(TLSEndpoint(endpoint)); err != nil {
+			errs = append(errs, fmt.Errorf("tls_checks: %q is not host or host:port", endpoint))
+		}
+	}
+	if c.TLSMinDays < 0 {
+		errs = append(errs, fmt.Errorf("tls_min_days: must not be negative, got %d", c.TLSMinDays))
+	}
+	
+	if c.PathHops < 1 || c.PathHops > maxPathHops {
+		errs = append(errs, fmt.Errorf("path_hops: must be between 1 and %d, got %d", maxPathHops, c.PathHops))
+	}
+	
+	if _, _, err := ParseStatusRange(c.HTTPStatus); err != nil {
+		errs = append(errs, fmt.Errorf("http_status: %w", err))
+	}
+	for _, check := range c.HTTPChecks {
+		if !isHTTPURL(check.URL) {
+			errs = append(errs, fmt.Errorf("http_checks: %q is not an http:// or https:// URL", check.URL))
+		}
+		if check.Status != "" {
+			if _, _, err := ParseStatusRange(check.Status); err != nil {
+				errs = append(errs, fmt.Errorf("http_checks: %s: %w", check.URL, err))
+			}
+		}
+		if check.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("http_checks: %s: timeout must not be negative", check.URL))
+		}
+	}
+	
+	if c.CaptivePortal && !isHTTPURL(c.CaptivePortalURL) {
+		errs = append(errs, fmt.Errorf("captive_portal_url: %q is not an http:// or https:// URL", c.CaptivePortalURL))
+	}
+	
+	if c.ProxyURL != "" {
+		if u, err := url.Parse(c.ProxyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" && u.Scheme != "socks5h") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("proxy_url: %q is not an http://, https://, socks5:// or socks5h:// URL", c.ProxyURL))
+		}
+	}
+	if c.CheckProxy && !isHTTPURL(c.ProxyTestURL) {
+		errs = append(errs, fmt.Errorf("proxy_test_url: %q is not an http:// or https:// URL", c.ProxyTestURL))
+	}
+	if c.HTTPViaProxy && c.ProxyURL == "" {
+		errs = append(errs, fmt.Errorf("http_via_proxy: requires proxy_url (the HTTP checks already use the http_proxy/https_proxy environment)"))
+	}
+	
+	if !IsGatewayFamily(c.GatewayFamily) {
+		errs = append(errs, fmt.Errorf("gateway_family: must be 'ipv4', 'ipv6', 'either' or 'both', got %q", c.GatewayFamily))
+	}
+	if !IsGatewayProbe(c.GatewayProbe) {
+		errs = append(errs, fmt.Errorf("gateway_probe: must be 'icmp', 'arp', 'any' or 'all', got %q", c.GatewayProbe))
+	}
+	
+	if c.RouteTable < 0 {
+		errs = append(errs, fmt.Errorf("route_table: invalid table %d", c.RouteTable))
+	}
+	for _, route := range c.RequiredRoutes {
+		if _, _, _, err := ParseRequiredRoute(route); err != nil {
+			errs = append(errs, fmt.Errorf("required_routes: %w", err))
+		}
+	}
+	for _, table := range c.PolicyTables {
+		if id, err := ParseRouteTable(table); err != nil || id == 0 {
+			errs = append(errs, fmt.Errorf("policy_tables: %q is not a routing table ID", table))
+		}
+	}
+	
+	if c.MinInterfacesUp < 0 {
+		errs = append(errs, fmt.Errorf("min_interfaces_up: must not be negative, got %d", c.MinInterfacesUp))
+	}
+	
+	if c.MinSpeed < 0 {
+		errs = append(errs, fmt.Errorf("min_speed: must not be negative, got %d", c.MinSpeed))
+	}
+	
+	if c.BondMinSlaves < 0 {
+		errs = append(errs, fmt.Errorf("bond_min_slaves: must not be negative, got %d", c.BondMinSlaves))
+	}
+	
+	for _, field := range c.badPartnerMACs {
+		errs = append(errs, fmt.Errorf("lacp_partner_mac: invalid entry %q (expected a MAC address or bond=mac)", field))
+	}
+	
+	if c.WirelessMinSignal > 0 {
+		errs = append(errs, fmt.Errorf("wireless_min_signal: must be negative dBm (e.g. -70), got %d", c.WirelessMinSignal))
+	}
+	
+	if len(c.ResolverHostnames) == 0 {
+		errs = append(errs, fmt.Errorf("resolver_hostname: must not be empty"))
+	}
+	
+	for _, hostname := range c.ResolverHostnames {
+		if _, _, err := ParseResolverHostname(hostname); err != nil {
+			errs = append(errs, fmt.Errorf("resolver_hostname: %q: %w", hostname, err))
+		}
+	}
+	
+	if _, err := ParseQuorum(c.DNSQuorum, len(c.ResolverHostnames)); err != nil {
+		errs = append(errs, fmt.Errorf("dns_quorum: %w", err))
+	}
+	
+	for _, server := range c.DNSServers {
+		host := server
+		if h, _, err := net.SplitHostPort(server); err == nil {
+			host = h
+		}
+		if net.ParseIP(host) == nil {
+			errs = append(errs, fmt.Errorf("dns_servers: %q is not an IP address", server))
+		}
+	}
+	if c.DNSMaxLatency < 0 {
+		errs = append(errs, fmt.Errorf("dns_max_latency: must not be negative, got %s", c.DNSMaxLatency))
+	} else if c.DNSMaxLatency > 0 && c.DNSMaxLatency >= c.DNSTimeout {
+		errs = append(errs, fmt.Errorf("dns_max_latency: must be below dns_timeout (%s), got %s", c.DNSTimeout, c.DNSMaxLatency))
+	}
+	if c.CheckDNSSEC && c.DNSSECSignedName == "" {
+		errs = append(errs, fmt.Errorf("check_dnssec: requires dnssec_signed_name"))
+	}
+	if c.DNSSystemServers && len(c.DNSServers) > 0 {
+		errs = append(errs, fmt.Errorf("dns_system_servers: can't be combined with dns_servers"))
+	}
+	for _, server := range c.EncryptedDNS {
+		if _, _, _, err := ParseEncryptedDNSServer(server); err != nil {
+			errs = append(errs, fmt.Errorf("dns_encrypted_servers: %w", err))
+		}
+	}
+	
+	if c.LogFile == "" && !c.NoLogFile {
+		errs = append(errs, fmt.Errorf("log_file: must not be empty (use no_log_file for stdout only)"))
+	}
+	
+	if c.LockFile == "" {
+		errs = append(errs, fmt.Errorf("lock_file: must not be empty"))
+	}
+	
+	return errors.Join(errs...)
+}
+
+// WriteYAML writes the effective configuration in config file format
+func (c *Config) WriteYAML(w io.Writer) error {
+	mtus := []string{}
+	if c.ExpectedMTU > 0 {
+		mtus = append(mtus, strconv.Itoa(c.ExpectedMTU))
+	}
+	for name, mtu := range c.InterfaceMTUs {
+		mtus = append(mtus, fmt.Sprintf("%s=%d", name, mtu))
+	}
+	sort.Strings(mtus)
+	
+	speeds := []string{}
+	if c.MinSpeed > 0 {
+		speeds = append(speeds, strconv.Itoa(c.MinSpeed))
+	}
+	for name, speed := range c.InterfaceSpeeds {
+		speeds = append(speeds, fmt.Sprintf("%s=%d", name, speed))
+	}
+	sort.Strings(speeds)
+	
+	bondSlaves := []string{}
+	if c.BondMinSlaves > 0 {
+		bondSlaves = append(bondSlaves, strconv.Itoa(c.BondMinSlaves))
+	}
+	for name, count := range c.BondSlaves {
+		bondSlaves = append(bondSlaves, fmt.Sprintf("%s=%d", name, count))
+	}
+	sort.Strings(bondSlaves)
+	
+	partners := []string{}
+	if c.LACPPartnerMAC != "" {
+		partners = append(partners, c.LACPPartnerMAC)
+	}
+	for name, mac := range c.BondPartnerMACs {
+		partners = append(partners, fmt.Sprintf("%s=%s", name, mac))
+	}
+	sort.Strings(partners)
+	
+	thresholds := []string{}
+	for name, t := range c.CheckThresholds {
+		if t.Recover > 0 {
+			thresholds = append(thresholds, fmt.Sprintf("%s=%d/%d", name, t.Fail, t.Recover))
+		} else {
+			thresholds = append(thresholds, fmt.Sprintf("%s=%d", name, t.Fail))
+		}
+	}
+	sort.Strings(thresholds)
+	
+	routeTable := strconv.Itoa(c.RouteTable)
+	switch c.RouteTable {
+	case 254:
+		routeTable = "main"
+	case 0:
+		routeTable = "all"
+	}
+	
+	// An empty bogus name disables that test; "none" reads back the same way
+	dnssecBogusName := c.DNSSECBogusName
+	if dnssecBogusName == "" {
+		dnssecBogusName = "none"
+	}
+	
+	fc := fileConfig{
+		TotalTimeout:       durationString(c.TotalTimeout),
+		RunAfterSuccess:    durationString(c.RunAfterSuccess),
+		SleepInterval:      durationString(c.SleepInterval),
+		SleepJitter:        &c.SleepJitter,
+		PingTimeout:        durationString(c.PingTimeout),
+		DNSTimeout:         durationString(c.DNSTimeout),
+		SystemdTimeout:     durationString(c.SystemdTimeout),
+		NMTimeout:          durationString(c.NMTimeout),
+		ShutdownTimeout:    durationString(c.ShutdownTimeout),
+		FailureThreshold:   &c.FailureThreshold,
+		RecoveryThreshold:  &c.RecoveryThreshold,
+		CheckThresholds:    stringPtr(strings.Join(thresholds, " ")),
+		FlapThreshold:      &c.FlapThreshold,
+		FlapWindow:         durationString(c.FlapWindow),
+		AdvisoryChecks:     nonNil(c.AdvisoryChecks),
+		ReadinessQuorum:    &c.ReadinessQuorum,
+		ReadinessExpr:      &c.ReadinessExpr,
+		Blocking:           &c.BlockingMode,
+		Watch:              &c.Watch,
+		EventDriven:        &c.EventDriven,
+		EventBackstop:      durationString(c.EventBackstop),
+		InterfaceTypes:     nonNil(c.InterfaceTypes),
+		RequiredInterfaces: nonNil(c.RequiredInterfaces),
+		ExcludedInterfaces: nonNil(c.ExcludedInterfaces),
+		IgnoreSRIOVVFs:     &c.IgnoreSRIOVVFs,
+		MinInterfacesUp:    &c.MinInterfacesUp,
+		ExpectedMTU:        stringPtr(strings.Join(mtus, " ")),
+		PathMTUProbe:       &c.PathMTUProbe,
+		MinSpeed:           stringPtr(strings.Join(speeds, " ")),
+		BondMinSlaves:      stringPtr(strings.Join(bondSlaves, " ")),
+		LACPPartnerMAC:     stringPtr(strings.Join(partners, " ")),
+		TunnelHandshakeAge: durationString(c.TunnelHandshakeAge),
+		WirelessSSIDs:      nonNil(c.WirelessSSIDs),
+		WirelessMinSignal:  &c.WirelessMinSignal,
+		NetworkServices:    nonNil(c.NetworkServices),
+		RequiredServices:   nonNil(c.RequiredServices),
+		PingTargets:        nonNil(c.PingTargets),
+		PingPolicy:         &c.PingPolicy,
+		PingGateway:        &c.PingGateway,
+		GatewayFamily:      &c.GatewayFamily,
+		GatewayProbe:       &c.GatewayProbe,
+		PingCount:          &c.PingCount,
+		PingMaxLoss:        &c.PingMaxLoss,
+		PingMaxRTT:         durationString(c.PingMaxRTT),
+		GatewayMaxRTT:      durationString(c.GatewayMaxRTT),
+		ResolverHostname:   fieldList(nonNil(c.ResolverHostnames)),
+		DNSQuorum:          &c.DNSQuorum,
+		DNSServers:         nonNil(c.DNSServers),
+		DNSSystemServers:   &c.DNSSystemServers,
+		DNSMaxLatency:      durationString(c.DNSMaxLatency),
+		EncryptedDNS:       fieldList(nonNil(c.EncryptedDNS)),
+		RouteTable:         &routeTable,
+		IPv6DefaultRoute:   &c.IPv6DefaultRoute,
+		RequiredRoutes:     nonNil(c.RequiredRoutes),
+		PolicyTables:       fieldList(nonNil(c.PolicyTables)),
+		CheckDHCP:          &c.CheckDHCP,
+		CheckTimeSync:      &c.CheckTimeSync,
+		CheckResolved:      &c.CheckResolved,
+		CheckReverseDNS:    &c.CheckReverseDNS,
+		CheckResolvConf:    &c.CheckResolvConf,
+		CheckHostname:      &c.CheckHostname,
+		CheckDNSSEC:        &c.CheckDNSSEC,
+		DNSSECSignedName:   &c.DNSSECSignedName,
+		DNSSECBogusName:    &dnssecBogusName,
+		CheckNDP:           &c.CheckNDP,
+		TCPChecks:          fieldList(nonNil(c.TCPChecks)),
+		TCPTimeout:         durationString(c.TCPTimeout),
+		HTTPChecks:         []*fileHTTPCheck{},
+		HTTPTimeout:        durationString(c.HTTPTimeout),
+		HTTPStatus:         &c.HTTPStatus,
+		CaptivePortal:      &c.CaptivePortal,
+		CaptivePortalURL:   &c.CaptivePortalURL,
+		CheckProxy:         &c.CheckProxy,
+		ProxyURL:           &c.ProxyURL,
+		ProxyTestURL:       &c.ProxyTestURL,
+		HTTPViaProxy:       &c.HTTPViaProxy,
+		TLSChecks:          fieldList(nonNil(c.TLSChecks)),
+		TLSTimeout:         durationString(c.TLSTimeout),
+		TLSMinDays:         &c.TLSMinDays,
+		PathTarget:         &c.PathTarget,
+		PathHops:           &c.PathHops,
+		ExecChecks:         nonNil(c.ExecChecks),
+		ExecCheckTimeout:   durationString(c.ExecCheckTimeout),
+		ARPProbe:           &c.ARPProbe,
+		ARPProbeTimeout:    durationString(c.ARPProbeTimeout),
+		WebhookURL:         &c.WebhookURL,
+		WebhookTimeout:     durationString(c.WebhookTimeout),
+		OnReady:            &c.OnReady,
+		OnDegraded:         &c.OnDegraded,
+		OnTimeout:          &c.OnTimeout,
+		HookTimeout:        durationString(c.HookTimeout),
+		MetricsListen:      &c.MetricsListen,
+		LogFile:            &c.LogFile,
+		LockFile:           &c.LockFile,
+		StateFile:          &c.StateFile,
+		NoLogFile:          &c.NoLogFile,
+	}
+	
+	if len(c.InterfacePolicies) > 0 {
+		fc.Interfaces = make(map[string]*fileInterfacePolicy)
+		for name, policy := range c.InterfacePolicies {
+			policy := policy
+			fc.Interfaces[name] = &fileInterfacePolicy{
+				CarrierOnly: &policy.CarrierOnly,
+				MinSlaves:   &policy.MinSlaves,
+				RequireLACP: policy.RequireLACP,
+				MTU:         &policy.MTU,
+				MinSpeed:    &policy.MinSpeed,
+			}
+		}
+	}
+	
+	if len(c.PingThresholds) > 0 {
+		fc.PingThresholds = make(map[string]*filePingThreshold)
+		for target, threshold := range c.PingThresholds {
+			threshold := threshold
+			ft := &filePingThreshold{MaxLoss: threshold.MaxLoss}
+			if threshold.Count > 0 {
+				ft.Count = &threshold.Count
+			}
+			if threshold.MaxRTT > 0 {
+				ft.MaxRTT = durationString(threshold.MaxRTT)
+			}
+			fc.PingThresholds[target] = ft
+		}
+	}
+	
+	for _, check := range c.HTTPChecks {
+		check := check
+		fh := &fileHTTPCheck{URL: check.URL, Body: check.Body, Insecure: check.Insecure}
+		if check.Status != "" {
+			fh.Status = &check.Status
+		}
+		if check.Timeout > 0 {
+			fh.Timeout = durationString(check.Timeout)
+		}
+		fc.HTTPChecks = append(fc.HTTPChecks, fh)
+	}
+	
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&fc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// ValidateReadiness checks the advisory checks, readiness quorum and readiness
+// expression against the checks that will run. A reload passes the checks of
+// the running configuration, since enabling a check takes a restart.
+func (c *Config) ValidateReadiness(enabled []string) error {
+	return errors.Join(c.validateReadiness(enabled)...)
+}
+
+// validateReadiness returns each problem ValidateReadiness finds
+func (c *Config) validateReadiness(enabled []string) []error {
+	var errs []error
+	
+	for _, check := range c.AdvisoryChecks {
+		if check == "exec:" {
+			errs = append(errs, fmt.Errorf("advisory_checks: exec: needs a command name, e.g. exec:check-mount"))
+		} else if !containsFold(validChecks, check) && !strings.HasPrefix(check, "exec:") {
+			errs = append(errs, fmt.Errorf("advisory_checks: unknown check %q (valid: %s, exec:<command>)", check, strings.Join(validChecks, ", ")))
+		} else if strings.HasPrefix(check, "exec:") && !contains(enabled, check) {
+			errs = append(errs, fmt.Errorf("advisory_checks: no exec check is named %q (exec checks: %s)", check, execNames(enabled)))
+		}
+	}
+	
+	if _, err := ParseQuorum(c.ReadinessQuorum, 1); err != nil {
+		errs = append(errs, fmt.Errorf("readiness_quorum: %w", err))
+	} else if c.ReadinessExpr == "" {
+		// With nothing left to pass the network would be ready immediately, and a
+		// count above the checks that can pass would be silently capped to all
+		required := 0
+		for _, check := range enabled {
+			if !c.IsAdvisory(check) {
+				required++
+			}
+		}
+		if required == 0 {
+			errs = append(errs, fmt.Errorf("advisory_checks: every enabled check is advisory, so nothing would gate readiness (use readiness_expr to choose the checks instead)"))
+		} else if count, err := strconv.Atoi(c.ReadinessQuorum); err == nil && count > required {
+			errs = append(errs, fmt.Errorf("readiness_quorum: %d exceeds the %d enabled non-advisory checks", count, required))
+		}
+	}
+	
+	if c.ReadinessExpr != "" {
+		expr, err := readiness.Parse(c.ReadinessExpr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("readiness_expr: %w", err))
+		} else {
+			// Checks that never run count as failing, so naming one could keep
+			// the expression false forever
+			for _, name := range expr.Names() {
+				switch {
+				case contains(enabled, name):
+				case containsFold(validChecks, name):
+					errs = append(errs, fmt.Errorf("readiness_expr: check %q isn't enabled, so it would never pass", name))
+				case strings.HasPrefix(name, "exec:"):
+					errs = append(errs, fmt.Errorf("readiness_expr: no exec check is named %q (exec checks: %s)", name, execNames(enabled)))
+				default:
+					errs = append(errs, fmt.Errorf("readiness_expr: unknown check %q", name))
+				}
+			}
+		}
+	}
+	
+	return errs
+}
+
+// contains reports whether list contains val
+func contains(list []string, val string) bool {
+	for _, item := range list {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
+
+// execNames lists the exec checks among checks for error messages
+func execNames(checks []string) string {
+	var names []string
+	for _, check := range checks {
+		if strings.HasPrefix(check, "exec:") {
+			names = append(names, check)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// containsFold reports whether list contains val, ignoring case
+func containsFold(list []string, val string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, val) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUnitSuffix reports whether name ends in a known systemd unit type
+func hasUnitSuffix(name string) bool {
+	for _, suffix := range validUnitSuffixes {
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func durationString(d time.Duration) *string {
+	return stringPtr(d.String())
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// nonNil keeps empty lists as [] rather than null in the YAML output
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// isHTTPURL reports whether raw is an absolute http:// or https:// URL
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -16,10 +17,25 @@ type Logger struct {
 	logPath      string
 	mu           sync.Mutex
 	messageCount int
+	console      io.Writer  // Where messages are echoed (nil = stdout)
+	
+	// Set on loggers returned by Buffered
+	parent       *Logger
+	buffered     []bufferedLine
+}
+
+// bufferedLine is a message held by a buffered logger
+type bufferedLine struct {
+	line    string  // Timestamped line as it will be written
+	message string
 }
 
-// New creates a new logger instance
+// New creates a new logger instance. An empty logPath logs to stdout only.
 func New(logPath string) (*Logger, error) {
+	if logPath == "" {
+		return &Logger{}, nil
+	}
+	
 	err := os.MkdirAll(filepath.Dir(logPath), 0755)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
@@ -38,23 +54,86 @@ func New(logPath string) (*Logger, error) {
 
 // Log writes a log message with timestamp
 func (l *Logger) Log(message string) {
+	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+	logLine := fmt.Sprintf("%s - %s\n", timestamp, message)
+	
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	
-	l.messageCount++
+	// Buffered loggers hold the line until Flush
+	if l.parent != nil {
+		l.buffered = append(l.buffered, bufferedLine{line: logLine, message: message})
+		return
+	}
 	
-	// Check for log rotation every 10 messages
-	if l.messageCount%10 == 0 {
-		l.rotateIfNeeded()
+	l.write(logLine)
+}
+
+// write appends a formatted line to the log file and echoes it; the caller holds mu
+func (l *Logger) write(logLine string) {
+	if l.file != nil {
+		l.messageCount++
+		
+		// Check for log rotation every 10 messages
+		if l.messageCount%10 == 0 {
+			l.rotateIfNeeded()
+		}
+		
+		l.file.WriteString(logLine)
+		l.file.Sync()
 	}
 	
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	logLine := fmt.Sprintf("%s - %s\n", timestamp, message)
+	// Write to stdout
+	if l.console != nil {
+		fmt.Fprint(l.console, logLine)
+	} else {
+		fmt.Print(logLine)
+	}
+}
+
+// SetConsole echoes messages to w instead of stdout, e.g. to stderr when
+// stdout carries machine-readable output
+func (l *Logger) SetConsole(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.console = w
+}
+
+// Buffered returns a logger that holds its messages until Flush, so checks
+// running concurrently don't interleave their output
+func (l *Logger) Buffered() *Logger {
+	return &Logger{parent: l}
+}
+
+// Messages returns the messages held by a buffered logger, without timestamps
+func (l *Logger) Messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	
-	// Write to both file and stdout
-	l.file.WriteString(logLine)
-	l.file.Sync()
-	fmt.Print(logLine)
+	messages := make([]string, 0, len(l.buffered))
+	for _, b := range l.buffered {
+		messages = append(messages, b.message)
+	}
+	return messages
+}
+
+// Flush writes the held messages to the parent logger, keeping their
+// original timestamps
+func (l *Logger) Flush() {
+	l.mu.Lock()
+	buffered := l.buffered
+	l.buffered = nil
+	l.mu.Unlock()
+	
+	if l.parent == nil || len(buffered) == 0 {
+		return
+	}
+	
+	l.parent.mu.Lock()
+	defer l.parent.mu.Unlock()
+	for _, b := range buffered {
+		l.parent.write(b.line)
+	}
 }
 
 // Logf writes a formatted log message
@@ -63,10 +142,11 @@ func (l *Logger) Logf(format string, args ...interface{}) {
 }
 
 // Banner logs a startup banner with configuration details
-func (l *Logger) Banner(pid int, mode string, totalTimeout, afterSuccess, sleep time.Duration, interfaceTypes []string, resolver string, pingTimeout, dnsTimeout time.Duration) {
+func (l *Logger) Banner(version string, pid int, mode string, totalTimeout, afterSuccess, sleep time.Duration, interfaceTypes []string, resolver string, pingTimeout, dnsTimeout time.Duration) {
 	l.Log("=============================================================")
 	l.Logf("    NETWORK STARTUP MONITOR SERVICE - %s", time.Now().Format(time.RFC3339))
 	l.Log("=============================================================")
+	l.Logf("Version: %s", version)
 	l.Logf("PID: %d", pid)
 	l.Logf("Mode: %s", mode)
 	l.Logf("Timeouts: Total=%s, AfterSuccess=%s, Sleep=%s", totalTimeout, afterSuccess, sleep)
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+	
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/logger"
+)
+
+// Metrics exposes check outcomes and timings in Prometheus format
+type Metrics struct {
+	checkUp        *prometheus.GaugeVec
+	transitions    *prometheus.CounterVec
+	checkDuration  *prometheus.HistogramVec
+	timeToComplete prometheus.Gauge
+	gatewayRTT     *prometheus.GaugeVec
+	dnsLatency     *prometheus.GaugeVec
+	server         *http.Server
+}
+
+// New creates the metrics collectors and an HTTP server for listenAddr
+func New(listenAddr string) *Metrics {
+	registry := prometheus.NewRegistry()
+	
+	m := &Metrics{
+		checkUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "network_monitor_check_up",
+			Help: "Current state of each readiness check (1 = passing, 0 = failing).",
+		}, []string{"check"}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "network_monitor_check_transitions_total",
+			Help: "Number of state transitions per readiness check.",
+		}, []string{"check"}),
+		checkDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "network_monitor_check_duration_seconds",
+			Help:    "Execution time of each readiness check.",
+			Buckets: []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"check"}),
+		timeToComplete: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "network_monitor_time_to_network_complete_seconds",
+			Help: "Time from monitor start until the network was first fully ready.",
+		}),
+		gatewayRTT: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "network_monitor_gateway_rtt_seconds",
+			Help: "Average round-trip time to the default gateway in the last check.",
+		}, []string{"family"}),
+		dnsLatency: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "network_monitor_dns_latency_seconds",
+			Help: "Time taken to resolve each resolver hostname in the last check, per server (\"system\" for the system resolver).",
+		}, []string{"hostname", "server"}),
+	}
+	
+	registry.MustRegister(m.checkUp, m.transitions, m.checkDuration, m.timeToComplete, m.gatewayRTT, m.dnsLatency)
+	
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	m.server = &http.Server{
+		Addr:              listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	
+	return m
+}
+
+// Start serves /metrics in the background
+func (m *Metrics) Start(log *logger.Logger) {
+	go func() {
+		if err := m.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Logf("Metrics: server failed - %v", err)
+		}
+	}()
+	log.Logf("Metrics: serving /metrics on %s", m.server.Addr)
+}
+
+// SetState records the current state of a check
+func (m *Metrics) SetState(check string, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	m.checkUp.WithLabelValues(check).Set(value)
+}
+
+// RecordTransition counts a state transition of a check
+func (m *Metrics) RecordTransition(check string) {
+	m.transitions.WithLabelValues(check).Inc()
+}
+
+// ObserveDuration records how long a check took to run
+func (m *Metrics) ObserveDuration(check string, duration time.Duration) {
+	m.checkDuration.WithLabelValues(check).Observe(duration.Seconds())
+}
+
+// SetTimeToComplete records the time it took for the network to become ready
+func (m *Metrics) SetTimeToComplete(duration time.Duration) {
+	m.timeToComplete.Set(duration.Seconds())
+}
+
+// SetGatewayRTT records the average round-trip time to the default gateway
+// of an address family ("ipv4" or "ipv6")
+func (m *Metrics) SetGatewayRTT(family string, rtt time.Duration) {
+	m.gatewayRTT.WithLabelValues(family).Set(rtt.Seconds())
+}
+
+// SetDNSLatency records how long a resolver hostname took to resolve through
+// a server, or "system" for the system resolver
+func (m *Metrics) SetDNSLatency(hostname, server string, latency time.Duration) {
+	m.dnsLatency.WithLabelValues(hostname, server).Set(latency.Seconds())
+}
+
+// Close shuts down the HTTP server
+func (m *Metrics) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	m.server.Shutdown(ctx)
+}
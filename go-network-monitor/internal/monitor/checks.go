@@ -1,31 +1,74 @@
 package monitor
 
-import ()
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/config"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/network"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/system"
+)
 
 // checkNetworkServices checks the status of network services
-func (m *Monitor) checkNetworkServices(enabledServices []string) bool {
-	if len(enabledServices) == 0 {
-		m.logger.Log("Network services: NONE FOUND")
-		return true // Don't block if no services to check
+func (m *Monitor) checkNetworkServices(ctx context.Context) *CheckResult {
+	result := newResult()
+	enabledServices := m.enabledServices
+	requiredServices := m.config.RequiredServices
+	
+	if len(enabledServices) == 0 && len(requiredServices) == 0 {
+		m.log(ctx).Log("Network services: NONE FOUND")
+		return result.pass() // Don't block if no services to check
 	}
 	
 	if m.systemd == nil {
-		m.logger.Log("Network services: SYSTEMD NOT AVAILABLE")
-		return true // Don't block if systemd unavailable
+		if len(requiredServices) > 0 {
+			m.log(ctx).Log("Required services: SYSTEMD NOT AVAILABLE - cannot verify")
+			return result.failWith(errors.New("systemd not available"))
+		}
+		m.log(ctx).Log("Network services: SYSTEMD NOT AVAILABLE")
+		return result.pass() // Don't block if systemd unavailable
+	}
+	
+	required := make(map[string]bool)
+	for _, service := range requiredServices {
+		required[service] = true
+	}
+	
+	// Query the best-effort set plus any required services not already in it
+	servicesToCheck := append([]string{}, enabledServices...)
+	for _, service := range requiredServices {
+		if !containsString(enabledServices, service) {
+			servicesToCheck = append(servicesToCheck, service)
+		}
 	}
 	
-	serviceStatuses, err := m.systemd.CheckServicesStatus(enabledServices)
+	serviceStatuses, err := m.systemd.CheckServicesStatus(ctx, servicesToCheck)
 	if err != nil {
-		m.logger.Logf("Network services: ERROR - %v", err)
-		return false
+		m.log(ctx).Logf("Network services: ERROR - %v", err)
+		return result.failWith(err)
 	}
 	
 	activeCount := 0
 	failedCount := 0
+	bestEffortCount := 0
 	
 	for _, service := range enabledServices {
+		if required[service] {
+			continue // Evaluated strictly below
+		}
+		bestEffortCount++
+		
 		if status, exists := serviceStatuses[service]; exists {
-			m.logger.Log(status.String())
+			m.log(ctx).Log(status.String())
 			
 			if status.IsReady() {
 				activeCount++
@@ -35,33 +78,74 @@ func (m *Monitor) checkNetworkServices(enabledServices []string) bool {
 		}
 	}
 	
-	allReady := (failedCount == 0 && activeCount > 0)
+	// Required services must all be active, regardless of the lenient rules above
+	requiredReady := true
+	for _, service := range requiredServices {
+		status, exists := serviceStatuses[service]
+		if !exists {
+			m.log(ctx).Logf("Required service %s: UNKNOWN - NOT READY", service)
+			requiredReady = false
+			continue
+		}
+		
+		m.log(ctx).Log(status.String())
+		if !status.IsReady() {
+			m.log(ctx).Logf("Required service %s: NOT ACTIVE - NOT READY", service)
+			requiredReady = false
+		}
+	}
+	
+	if len(requiredServices) > 0 {
+		if requiredReady {
+			m.log(ctx).Logf("Required services: ALL ACTIVE (%d)", len(requiredServices))
+		} else {
+			m.log(ctx).Log("Required services: NOT READY")
+		}
+	}
+	
+	bestEffortReady := bestEffortCount == 0 || (failedCount == 0 && activeCount > 0)
 	
-	if allReady {
-		m.logger.Logf("Network services: ALL READY (%d active)", activeCount)
-	} else {
-		m.logger.Logf("Network services: %d NOT READY, %d ready", failedCount, activeCount)
+	if bestEffortCount > 0 {
+		if bestEffortReady {
+			m.log(ctx).Logf("Network services: ALL READY (%d active)", activeCount)
+		} else {
+			m.log(ctx).Logf("Network services: %d NOT READY, %d ready", failedCount, activeCount)
+		}
 	}
 	
-	return allReady
+	result.detail("active", activeCount)
+	result.detail("not_ready", failedCount)
+	return result.passIf(requiredReady && bestEffortReady)
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
 
 // checkNetworkInterfaces checks network interfaces based on requirements
-func (m *Monitor) checkNetworkInterfaces() bool {
-	interfaces, err := m.ifaceMonitor.GetActiveInterfaces()
+func (m *Monitor) checkNetworkInterfaces(ctx context.Context) *CheckResult {
+	result := newResult()
+	interfaces, err := m.ifaceMonitor.GetActiveInterfaces(m.config.RequiredInterfaces)
 	if err != nil {
-		m.logger.Logf("Failed to get interfaces: %v", err)
-		return false
+		m.log(ctx).Logf("Failed to get interfaces: %v", err)
+		return result.failWith(err)
 	}
 	
 	if len(interfaces) == 0 {
-		m.logger.Log("No network interfaces found")
-		return false
+		m.log(ctx).Log("No network interfaces found")
+		return result.fail()
 	}
 	
 	var interfacesUp, interfacesDown int
 	var requiredInterfacesUp, requiredInterfacesDown int
 	interfaceStates := make(map[string]bool)
+	statuses := make(map[string]*network.InterfaceStatus)
 	
 	// Check all monitored interfaces
 	for _, iface := range interfaces {
@@ -69,11 +153,13 @@ func (m *Monitor) checkNetworkInterfaces() bool {
 		
 		status, err := m.ifaceMonitor.CheckInterfaceStatus(iface)
 		if err != nil {
-			m.logger.Logf("Interface %s: ERROR - %v", iface, err)
+			m.log(ctx).Logf("Interface %s: ERROR - %v", iface, err)
 			interfacesDown++
 			interfaceStates[iface] = false
 			continue
 		}
+		statuses[iface] = status
+		m.trackInterface(ctx, status)
 		
 		carrierStatus := "DOWN"
 		if status.Carrier {
@@ -84,278 +170,2409 @@ func (m *Monitor) checkNetworkInterfaces() bool {
 			interfacesDown++
 		}
 		
-		m.logger.Logf("Interface %s: carrier=%s, operstate=%s", 
-			status.Name, carrierStatus, status.OperState)
+		speed := "unknown"
+		if status.Speed > 0 {
+			speed = fmt.Sprintf("%dMb/s", status.Speed)
+		}
+		
+		m.log(ctx).Logf("Interface %s: carrier=%s, operstate=%s, mtu=%d, speed=%s, duplex=%s", 
+			status.Name, carrierStatus, status.OperState, status.MTU, speed, status.Duplex)
+		
+		m.logSRIOV(ctx, iface)
+		
+		policy := m.config.InterfacePolicies[iface]
+		if policy.CarrierOnly {
+			m.log(ctx).Logf("Interface %s: carrier-only policy - other requirements not enforced", iface)
+		}
+		
+		// Validate MTU when an expected value is configured
+		if expectedMTU := m.expectedMTU(iface); expectedMTU > 0 && status.MTU != expectedMTU && !policy.CarrierOnly {
+			m.log(ctx).Logf("Interface %s: MTU MISMATCH (expected %d, got %d) - marking interface down",
+				iface, expectedMTU, status.MTU)
+			if interfaceUp {
+				interfacesUp--
+				interfacesDown++
+			}
+			interfaceUp = false
+		}
+		
+		// An address that failed DAD is unusable even though the link is up;
+		// tentative addresses become usable once DAD completes
+		if len(status.DADFailed) > 0 && !policy.CarrierOnly {
+			m.log(ctx).Logf("Interface %s: DUPLICATE ADDRESS DETECTED (dadfailed: %s) - marking interface down",
+				iface, strings.Join(status.DADFailed, " "))
+			if interfaceUp {
+				interfacesUp--
+				interfacesDown++
+			}
+			interfaceUp = false
+		} else if len(status.Tentative) > 0 && status.Carrier && !policy.CarrierOnly {
+			m.log(ctx).Logf("Interface %s: DAD IN PROGRESS (tentative: %s) - marking interface down",
+				iface, strings.Join(status.Tentative, " "))
+			if interfaceUp {
+				interfacesUp--
+				interfacesDown++
+			}
+			interfaceUp = false
+		}
+		
+		// Validate negotiated speed once carrier is up; before that the kernel
+		// reports -1 and the carrier check already holds the interface down
+		if minSpeed := m.minSpeed(iface); minSpeed > 0 && status.Carrier && !policy.CarrierOnly {
+			if status.Speed > 0 && status.Speed < minSpeed {
+				m.log(ctx).Logf("Interface %s: DEGRADED LINK SPEED (minimum %dMb/s, got %dMb/s) - marking interface down",
+					iface, minSpeed, status.Speed)
+				if interfaceUp {
+					interfacesUp--
+					interfacesDown++
+				}
+				interfaceUp = false
+			} else if status.Duplex == "half" {
+				m.log(ctx).Logf("Interface %s: HALF DUPLEX LINK - marking interface down", iface)
+				if interfaceUp {
+					interfacesUp--
+					interfacesDown++
+				}
+				interfaceUp = false
+			}
+		}
 		
 		// Check bond status if it's a bond interface
 		if m.ifaceMonitor.IsBondInterface(iface) {
-			m.logger.Logf("Interface %s: BOND INTERFACE DETECTED - checking bond status", iface)
-			bondStatus, err := m.ifaceMonitor.CheckBondStatus(iface)
-			if err != nil {
-				m.logger.Logf("Bond %s: ERROR - %v", iface, err)
-				m.logger.Logf("Interface %s: BOND STATUS FAILED - marking interface down", iface)
+			m.log(ctx).Logf("Interface %s: BOND INTERFACE DETECTED - checking bond status", iface)
+			if m.checkBond(ctx, iface, policy) {
+				m.log(ctx).Logf("Interface %s: BOND STATUS OK", iface)
+			} else if policy.CarrierOnly {
+				m.log(ctx).Logf("Interface %s: BOND STATUS FAILED - not enforced", iface)
+			} else {
+				m.log(ctx).Logf("Interface %s: BOND STATUS FAILED - marking interface down", iface)
 				if interfaceUp {
 					interfacesUp--
 					interfacesDown++
 				}
 				interfaceUp = false
+			}
+		}
+		
+		// Check bridge ports if it's a bridge; a bridge passes no traffic
+		// until one of its ports is forwarding
+		if m.ifaceMonitor.IsBridgeInterface(iface) {
+			bridgeHealthy := false
+			bridgeStatus, err := m.ifaceMonitor.CheckBridgeStatus(iface)
+			if err != nil {
+				m.log(ctx).Logf("Bridge %s: ERROR - %v", iface, err)
 			} else {
-				m.logger.Logf("Bond %s: mode=%s, mii_status=%s, active_slave=%s, slaves=%d/%d",
-					bondStatus.Name, bondStatus.Mode, bondStatus.MIIStatus,
-					bondStatus.ActiveSlave, bondStatus.SlaveCount, bondStatus.TotalSlaves)
-				
-				if bondStatus.LACPComplete {
-					m.logger.Logf("Bond %s: LACP negotiation complete", bondStatus.Name)
-					m.logger.Logf("Bond %s: HEALTHY", bondStatus.Name)
-					m.logger.Logf("Interface %s: BOND STATUS OK", iface)
-				} else {
-					m.logger.Logf("Bond %s: LACP negotiation incomplete", bondStatus.Name)
-					m.logger.Logf("Interface %s: BOND STATUS FAILED - marking interface down", iface)
-					if interfaceUp {
-						interfacesUp--
-						interfacesDown++
+				var ports []string
+				settling := false
+				for _, port := range bridgeStatus.Ports {
+					ports = append(ports, port.Name+"="+port.State)
+					if port.State == "listening" || port.State == "learning" {
+						settling = true
 					}
-					interfaceUp = false
 				}
+				m.log(ctx).Logf("Bridge %s: stp=%s, forwarding=%d/%d, ports=[%s]",
+					bridgeStatus.Name, bridgeStatus.STP, bridgeStatus.ForwardingPorts, len(bridgeStatus.Ports), strings.Join(ports, " "))
+				
+				switch {
+				case bridgeStatus.ForwardingPorts > 0:
+					bridgeHealthy = true
+					m.log(ctx).Logf("Bridge %s: HEALTHY", bridgeStatus.Name)
+				case len(bridgeStatus.Ports) == 0:
+					m.log(ctx).Logf("Bridge %s: NO PORTS", bridgeStatus.Name)
+				case settling:
+					m.log(ctx).Logf("Bridge %s: NO FORWARDING PORTS (STP settling, forward delay %s)", bridgeStatus.Name, bridgeStatus.ForwardDelay)
+				default:
+					m.log(ctx).Logf("Bridge %s: NO FORWARDING PORTS", bridgeStatus.Name)
+				}
+			}
+			
+			if bridgeHealthy {
+				m.log(ctx).Logf("Interface %s: BRIDGE STATUS OK", iface)
+			} else if policy.CarrierOnly {
+				m.log(ctx).Logf("Interface %s: BRIDGE STATUS FAILED - not enforced", iface)
+			} else {
+				m.log(ctx).Logf("Interface %s: BRIDGE STATUS FAILED - marking interface down", iface)
+				if interfaceUp {
+					interfacesUp--
+					interfacesDown++
+				}
+				interfaceUp = false
 			}
 		}
 		
-		interfaceStates[iface] = interfaceUp
+		// Check the tunnel is actually connected if it's a tunnel
+		if status.Type == network.Tunnel {
+			if m.checkTunnel(ctx, iface) {
+				m.log(ctx).Logf("Interface %s: TUNNEL STATUS OK", iface)
+			} else if policy.CarrierOnly {
+				m.log(ctx).Logf("Interface %s: TUNNEL STATUS FAILED - not enforced", iface)
+			} else {
+				m.log(ctx).Logf("Interface %s: TUNNEL STATUS FAILED - marking interface down", iface)
+				if interfaceUp {
+					interfacesUp--
+					interfacesDown++
+				}
+				interfaceUp = false
+			}
+		}
 		
-		// Check if this is a required interface
-		if len(m.config.RequiredInterfaces) > 0 {
-			for _, reqInterface := range m.config.RequiredInterfaces {
-				if iface == reqInterface {
-					if interfaceUp {
-						requiredInterfacesUp++
-					} else {
-						requiredInterfacesDown++
-					}
-					break
+		// Check the HCA port is ACTIVE if it's an InfiniBand interface; carrier
+		// comes up with the physical link, before the subnet manager has
+		// brought the port into service
+		if status.Type == network.InfiniBand {
+			ibHealthy := false
+			ib, err := m.ifaceMonitor.CheckInfiniBandStatus(iface)
+			if err != nil {
+				m.log(ctx).Logf("InfiniBand %s: ERROR - %v", iface, err)
+			} else {
+				m.log(ctx).Logf("InfiniBand %s: device=%s, port=%d, state=%s, phys_state=%s, link_layer=%s, rate=%s",
+					iface, ib.Device, ib.Port, ib.State, ib.PhysState, ib.LinkLayer, ib.Rate)
+				ibHealthy = ib.Active()
+				if !ibHealthy && ib.PhysState == "LinkUp" {
+					m.log(ctx).Logf("InfiniBand %s: PORT NOT ACTIVE (link up, waiting for the subnet manager)", iface)
+				} else if !ibHealthy {
+					m.log(ctx).Logf("InfiniBand %s: PORT NOT ACTIVE", iface)
+				}
+			}
+			
+			if ibHealthy {
+				m.log(ctx).Logf("Interface %s: INFINIBAND STATUS OK", iface)
+			} else if policy.CarrierOnly {
+				m.log(ctx).Logf("Interface %s: INFINIBAND STATUS FAILED - not enforced", iface)
+			} else {
+				m.log(ctx).Logf("Interface %s: INFINIBAND STATUS FAILED - marking interface down", iface)
+				if interfaceUp {
+					interfacesUp--
+					interfacesDown++
+				}
+				interfaceUp = false
+			}
+		}
+		
+		// Check a wireless interface is associated with an expected network,
+		// not just that the driver reports carrier
+		if status.Type == network.Wireless {
+			if m.checkWireless(ctx, iface) {
+				m.log(ctx).Logf("Interface %s: WIRELESS STATUS OK", iface)
+			} else if policy.CarrierOnly {
+				m.log(ctx).Logf("Interface %s: WIRELESS STATUS FAILED - not enforced", iface)
+			} else {
+				m.log(ctx).Logf("Interface %s: WIRELESS STATUS FAILED - marking interface down", iface)
+				if interfaceUp {
+					interfacesUp--
+					interfacesDown++
 				}
+				interfaceUp = false
 			}
 		}
+		
+		interfaceStates[iface] = interfaceUp
+	}
+	
+	// A stacked interface (VLAN over bond over NICs) is only up when the
+	// devices beneath it are, so requiring the VLAN covers the whole stack
+	lowerStates := make(map[string]bool)
+	for _, iface := range interfaces {
+		if !interfaceStates[iface] {
+			continue
+		}
+		stack, err := m.ifaceMonitor.ResolveStack(iface)
+		if err != nil || stack.Parent == nil {
+			continue
+		}
+		m.log(ctx).Logf("Interface %s: stack %s", iface, stack)
+		if down := m.lowerDeviceDown(ctx, stack, interfaceStates, lowerStates); down != "" {
+			m.log(ctx).Logf("Interface %s: LOWER DEVICE %s DOWN - marking interface down", iface, down)
+			interfacesUp--
+			interfacesDown++
+			interfaceStates[iface] = false
+		}
+	}
+	
+	m.trackRemovedInterfaces(ctx, interfaces, statuses)
+	
+	result.detail("up", interfacesUp)
+	result.detail("down", interfacesDown)
+	
+	// A minimum interface count applies in addition to any required interfaces
+	minCountMet := true
+	if m.config.MinInterfacesUp > 0 {
+		minCountMet = interfacesUp >= m.config.MinInterfacesUp
+		if minCountMet {
+			m.log(ctx).Logf("Interfaces: %d/%d UP (minimum %d met)", interfacesUp, len(interfaces), m.config.MinInterfacesUp)
+		} else {
+			m.log(ctx).Logf("Interfaces: %d/%d UP (need at least %d)", interfacesUp, len(interfaces), m.config.MinInterfacesUp)
+		}
 	}
 	
 	// Determine if interfaces are ready
 	if len(m.config.RequiredInterfaces) > 0 {
-		// Specific interfaces required - all must be up
-		totalRequired := len(m.config.RequiredInterfaces)
-		if requiredInterfacesUp == totalRequired && requiredInterfacesDown == 0 {
-			m.logger.Logf("Required interfaces: ALL UP (%d/%d)", requiredInterfacesUp, totalRequired)
-			return true
+		// Specific interfaces required - every pattern must match at least
+		// one interface and every matching interface must be up
+		for _, pattern := range m.config.RequiredInterfaces {
+			matched := 0
+			for _, iface := range interfaces {
+				if status, ok := statuses[iface]; ok && !network.MatchInterfaceStatus(pattern, status) {
+					continue
+				} else if !ok && !network.MatchInterface(pattern, iface) {
+					continue
+				}
+				matched++
+				if interfaceStates[iface] {
+					requiredInterfacesUp++
+				} else {
+					requiredInterfacesDown++
+				}
+			}
+			if matched == 0 {
+				m.log(ctx).Logf("Required interface %s: NOT FOUND", pattern)
+				requiredInterfacesDown++
+			}
+		}
+		
+		totalRequired := requiredInterfacesUp + requiredInterfacesDown
+		if requiredInterfacesDown == 0 {
+			m.log(ctx).Logf("Required interfaces: ALL UP (%d/%d)", requiredInterfacesUp, totalRequired)
+			return result.passIf(minCountMet)
 		} else {
-			m.logger.Logf("Required interfaces: %d DOWN, %d UP (need all %d)", requiredInterfacesDown, requiredInterfacesUp, totalRequired)
-			return false
+			m.log(ctx).Logf("Required interfaces: %d DOWN, %d UP (need all %d)", requiredInterfacesDown, requiredInterfacesUp, totalRequired)
+			return result.fail()
 		}
+	} else if m.config.MinInterfacesUp > 0 {
+		return result.passIf(minCountMet)
 	} else {
 		// Any interface sufficient - at least one must be up
 		if interfacesUp > 0 {
-			m.logger.Logf("Interfaces: %d UP, %d DOWN (any interface sufficient)", interfacesUp, interfacesDown)
-			return true
+			m.log(ctx).Logf("Interfaces: %d UP, %d DOWN (any interface sufficient)", interfacesUp, interfacesDown)
+			return result.pass()
 		} else {
-			m.logger.Logf("Interfaces: ALL DOWN (%d total)", interfacesDown)
-			return false
+			m.log(ctx).Logf("Interfaces: ALL DOWN (%d total)", interfacesDown)
+			return result.fail()
 		}
 	}
 }
 
-// checkGatewayConnectivity tests gateway reachability
-func (m *Monitor) checkGatewayConnectivity() bool {
-	gateway, err := m.connectivity.GetDefaultGateway()
+// lowerDeviceDown walks the parents beneath a stacked interface and returns
+// the first one that isn't up, or "" when all are. Monitored devices count
+// as evaluated by the interface check; others need carrier and, for a bond,
+// its bond rules. The slaves and ports of a bond or bridge are covered by
+// its own rules rather than required individually.
+func (m *Monitor) lowerDeviceDown(ctx context.Context, stack *network.DeviceStack, states, lowerStates map[string]bool) string {
+	for lower := stack.Parent; lower != nil; lower = lower.Parent {
+		up, ok := states[lower.Name]
+		if !ok {
+			up, ok = lowerStates[lower.Name]
+		}
+		if !ok {
+			up = m.checkLowerDevice(ctx, lower.Name)
+			lowerStates[lower.Name] = up
+		}
+		if !up {
+			return lower.Name
+		}
+	}
+	return ""
+}
+
+// checkLowerDevice evaluates a device beneath a stacked interface that isn't
+// monitored itself
+func (m *Monitor) checkLowerDevice(ctx context.Context, iface string) bool {
+	status, err := m.ifaceMonitor.CheckInterfaceStatus(iface)
 	if err != nil {
-		m.logger.Logf("Gateway: ERROR - %v", err)
+		m.log(ctx).Logf("Lower device %s: ERROR - %v", iface, err)
 		return false
 	}
-	
-	err = m.connectivity.CheckGatewayReachability(gateway)
-	if err != nil {
-		m.logger.Logf("Gateway %s: NOT REACHABLE - %v", gateway, err)
+	if !status.Carrier {
+		m.log(ctx).Logf("Lower device %s: carrier=DOWN, operstate=%s", iface, status.OperState)
 		return false
 	}
 	
-	m.logger.Logf("Gateway %s: REACHABLE (%s timeout)", gateway, m.config.PingTimeout)
+	policy := m.config.InterfacePolicies[iface]
+	if m.ifaceMonitor.IsBondInterface(iface) && !m.checkBond(ctx, iface, policy) && !policy.CarrierOnly {
+		m.log(ctx).Logf("Lower device %s: BOND STATUS FAILED", iface)
+		return false
+	}
+	m.log(ctx).Logf("Lower device %s: UP", iface)
 	return true
 }
 
-// checkDNSResolution tests DNS resolution
-func (m *Monitor) checkDNSResolution() bool {
-	err := m.connectivity.CheckDNSResolution(m.config.ResolverHostname)
-	if err != nil {
-		m.logger.Logf("DNS resolution for %s: FAILED (%s timeout) - %v", 
-			m.config.ResolverHostname, m.config.DNSTimeout, err)
-		return false
+// trackInterface logs when a monitored interface first appears after the
+// initial discovery (USB NICs, SR-IOV VFs, hot-added virtio devices) or an
+// interface index shows up under a new name, as happens when udev renames
+// eth0 to its predictable name mid-boot. Both are part of the readiness
+// rules from the cycle they are seen in.
+func (m *Monitor) trackInterface(ctx context.Context, status *network.InterfaceStatus) {
+	mac := status.PermAddr
+	if mac == "" {
+		mac = status.MAC
 	}
 	
-	m.logger.Logf("DNS resolution for %s: SUCCESS (%s timeout)", 
-		m.config.ResolverHostname, m.config.DNSTimeout)
-	return true
+	previous, seen := m.interfaceNames[status.Index]
+	m.interfaceNames[status.Index] = status.Name
+	switch {
+	case !seen && m.interfacesSeen:
+		m.log(ctx).Logf("Interface %s: NEW INTERFACE DETECTED (type %s, ifindex %d, mac %s) - now monitored",
+			status.Name, status.Type, status.Index, mac)
+	case seen && previous != status.Name:
+		m.log(ctx).Logf("Interface %s: RENAMED from %s (ifindex %d, mac %s)", status.Name, previous, status.Index, mac)
+	}
 }
 
-// checkNetworkManagerConnectivity checks NetworkManager connectivity
-func (m *Monitor) checkNetworkManagerConnectivity() bool {
-	connectivity, err := m.connectivity.CheckNetworkManagerConnectivity()
-	if err != nil {
-		m.logger.Logf("NetworkManager connectivity: SERVICE NOT AVAILABLE - %v", err)
-		return true // Don't block if service unavailable
+// trackRemovedInterfaces logs monitored interfaces that have gone away since
+// the previous cycle and marks initial discovery complete
+func (m *Monitor) trackRemovedInterfaces(ctx context.Context, interfaces []string, statuses map[string]*network.InterfaceStatus) {
+	current := make(map[int]bool)
+	for _, status := range statuses {
+		current[status.Index] = true
 	}
-	
-	m.logger.Logf("NetworkManager connectivity: %s", connectivity)
-	return connectivity == "full"
+	for index, name := range m.interfaceNames {
+		if !current[index] && !containsString(interfaces, name) {
+			m.log(ctx).Logf("Interface %s: REMOVED (ifindex %d) - no longer monitored", name, index)
+			delete(m.interfaceNames, index)
+		}
+	}
+	m.interfacesSeen = true
 }
 
-// checkARPTable validates ARP table entries
-func (m *Monitor) checkARPTable() bool {
-	m.logger.Log("--- ARP Table Status ---")
-	
-	interfaces, err := m.ifaceMonitor.GetActiveInterfaces()
+// expectedMTU returns the configured MTU for an interface, or 0 if none is expected
+func (m *Monitor) expectedMTU(iface string) int {
+	if policy, ok := m.config.InterfacePolicies[iface]; ok && policy.MTU > 0 {
+		return policy.MTU
+	}
+	if mtu, ok := m.config.InterfaceMTUs[iface]; ok {
+		return mtu
+	}
+	return m.config.ExpectedMTU
+}
+
+// checkBond reports whether a bond meets its requirements: the readiness
+// rule of its mode unless the interface policy waives it, enough usable slaves, and
+// for 802.3ad the expected switch as partner when one is configured
+func (m *Monitor) checkBond(ctx context.Context, iface string, policy config.InterfacePolicy) bool {
+	bondStatus, err := m.ifaceMonitor.CheckBondStatus(iface)
 	if err != nil {
-		m.logger.Logf("ARP table: ERROR getting interfaces - %v", err)
+		m.log(ctx).Logf("Bond %s: ERROR - %v", iface, err)
 		return false
 	}
 	
-	if len(interfaces) == 0 {
-		m.logger.Log("ARP table: No interfaces to check")
-		return false
+	m.log(ctx).Logf("Bond %s: mode=%s, mii_status=%s, active_slave=%s, slaves=%d/%d",
+		bondStatus.Name, bondStatus.Mode, bondStatus.MIIStatus,
+		bondStatus.ActiveSlave, bondStatus.SlaveCount, bondStatus.TotalSlaves)
+	m.logSlaves(ctx, bondStatus)
+	m.trackFailover(ctx, bondStatus)
+	if bondStatus.Mode == "802.3ad" {
+		m.logLACP(ctx, bondStatus)
+		m.trackChurn(ctx, bondStatus)
 	}
 	
-	gateway, err := m.connectivity.GetDefaultGateway()
-	if err != nil {
-		gateway = nil // Continue without gateway check
+	bondHealthy := true
+	if ready, reason := bondStatus.ModeReady(); ready {
+		m.log(ctx).Logf("Bond %s: %s readiness met - %s", bondStatus.Name, bondStatus.Mode, reason)
+	} else {
+		m.log(ctx).Logf("Bond %s: %s readiness NOT MET - %s", bondStatus.Name, bondStatus.Mode, reason)
+		if policy.LACPRequired() {
+			bondHealthy = false
+		}
 	}
 	
-	arpStatus, err := m.arpMonitor.CheckARPTable(interfaces, gateway)
-	if err != nil {
-		m.logger.Logf("ARP table: ERROR - %v", err)
-		return false
+	if minSlaves := m.minSlaves(iface); minSlaves > 0 {
+		usable := bondStatus.UsableSlaves()
+		if usable < minSlaves {
+			m.log(ctx).Logf("Bond %s: DEGRADED - %d/%d slaves usable, %d required", bondStatus.Name, usable, bondStatus.TotalSlaves, minSlaves)
+			bondHealthy = false
+		} else {
+			m.log(ctx).Logf("Bond %s: %d/%d slaves usable (minimum %d met)", bondStatus.Name, usable, bondStatus.TotalSlaves, minSlaves)
+		}
 	}
 	
-	// Log per-interface ARP counts
-	for _, iface := range interfaces {
-		count := arpStatus.InterfaceEntries[iface]
-		if gateway != nil && arpStatus.GatewayResolved && arpStatus.GatewayMAC != nil {
-			m.logger.Logf("ARP table %s: %d entries (gateway %s -> %s)", 
-				iface, count, gateway, arpStatus.GatewayMAC)
+	if expected := m.lacpPartner(iface); expected != "" && bondStatus.Mode == "802.3ad" {
+		if strings.EqualFold(bondStatus.PartnerSystem, expected) {
+			m.log(ctx).Logf("Bond %s: LACP partner %s matches", bondStatus.Name, bondStatus.PartnerSystem)
 		} else {
-			m.logger.Logf("ARP table %s: %d entries", iface, count)
+			m.log(ctx).Logf("Bond %s: LACP PARTNER MISMATCH (expected %s, got %s)", bondStatus.Name, expected, bondStatus.PartnerSystem)
+			bondHealthy = false
+		}
+	}
+	
+	if bondHealthy {
+		m.log(ctx).Logf("Bond %s: HEALTHY", bondStatus.Name)
+	}
+	return bondHealthy
+}
+
+// logSlaves logs the link of each slave of a bond, and calls out slaves that
+// negotiated different speeds, since a bond that's up can be riding on its
+// slowest link
+func (m *Monitor) logSlaves(ctx context.Context, bond *network.BondStatus) {
+	var speeds []string
+	for _, slave := range bond.Slaves {
+		carrier := "DOWN"
+		if slave.Carrier {
+			carrier = "UP"
 		}
+		speed := "unknown"
+		if slave.Speed > 0 {
+			speed = fmt.Sprintf("%dMb/s", slave.Speed)
+		}
+		speeds = append(speeds, slave.Name+"="+speed)
+		
+		state := slave.State
+		if state == "" {
+			state = "unknown"
+		}
+		m.log(ctx).Logf("Bond %s: slave %s mii_status=%s, carrier=%s, speed=%s, duplex=%s, state=%s, link_failures=%d",
+			bond.Name, slave.Name, slave.MIIStatus, carrier, speed, slave.Duplex, state, slave.LinkFailures)
 	}
 	
-	m.logger.Logf("ARP table total: %d entries", arpStatus.TotalEntries)
+	if bond.MixedSpeeds() {
+		m.log(ctx).Logf("Bond %s: MIXED SLAVE SPEEDS (%s)", bond.Name, strings.Join(speeds, " "))
+	}
+}
+
+// trackFailover logs when a bond's active slave changes between cycles, with
+// the link failure counts of both slaves, so a primary link flapping during
+// boot stands out from a single failover
+func (m *Monitor) trackFailover(ctx context.Context, bond *network.BondStatus) {
+	previous, seen := m.activeSlaves[bond.Name]
+	m.activeSlaves[bond.Name] = bond.ActiveSlave
+	if !seen || previous == bond.ActiveSlave {
+		return
+	}
 	
-	if gateway != nil {
-		if arpStatus.GatewayResolved {
-			m.logger.Logf("ARP table gateway: %s RESOLVED", gateway)
-			return true
-		} else {
-			m.logger.Logf("ARP table gateway: %s NOT RESOLVED", gateway)
-			return false
+	// A bond without an active slave electing one, e.g. when its first slave
+	// gets carrier during boot, hasn't failed over
+	if previous == "" {
+		m.log(ctx).Logf("Bond %s: active slave %s elected", bond.Name, bond.ActiveSlave)
+		return
+	}
+	
+	m.failovers[bond.Name]++
+	failures := func(name string) string {
+		for _, slave := range bond.Slaves {
+			if slave.Name == name {
+				return fmt.Sprint(slave.LinkFailures)
+			}
 		}
-	} else {
-		if arpStatus.TotalEntries > 0 {
-			m.logger.Log("ARP table: POPULATED (no gateway to check)")
-			return true
+		return "n/a"
+	}
+	
+	from, to := previous, bond.ActiveSlave
+	if to == "" {
+		to = "none"
+	}
+	m.log(ctx).Logf("Bond %s: FAILOVER #%d - active slave %s -> %s (link failures: %s=%s, %s=%s)",
+		bond.Name, m.failovers[bond.Name], from, to, from, failures(previous), to, failures(bond.ActiveSlave))
+}
+
+// logLACP logs the 802.3ad actor and partner of a bond and, per slave, its
+// aggregator and port state, naming the flags a slave that isn't carrying
+// traffic is missing
+func (m *Monitor) logLACP(ctx context.Context, bond *network.BondStatus) {
+	m.log(ctx).Logf("Bond %s: LACP aggregator=%d, actor system=%s key=%d, partner system=%s key=%d",
+		bond.Name, bond.Aggregator, bond.ActorSystem, bond.ActorKey, bond.PartnerSystem, bond.PartnerKey)
+	
+	for _, slave := range bond.Slaves {
+		m.log(ctx).Logf("Bond %s: slave %s aggregator=%d, actor=[%s], partner=[%s]",
+			bond.Name, slave.Name, slave.AggregatorID,
+			strings.Join(network.LACPStateFlags(slave.ActorState), ","),
+			strings.Join(network.LACPStateFlags(slave.PartnerState), ","))
+		
+		missing := network.MissingLACPFlags(slave.ActorState)
+		switch {
+		case slave.MIIStatus != "up":
+			m.log(ctx).Logf("Bond %s: slave %s NOT NEGOTIATED - link %s", bond.Name, slave.Name, slave.MIIStatus)
+		case slave.Churned():
+			m.log(ctx).Logf("Bond %s: slave %s NOT NEGOTIATED - LACP CHURN (actor %s, partner %s)",
+				bond.Name, slave.Name, slave.ActorChurn, slave.PartnerChurn)
+		case slave.PartnerDefaulted():
+			m.log(ctx).Logf("Bond %s: slave %s NOT NEGOTIATED - no LACPDUs from partner (switch port not configured for LACP?)", bond.Name, slave.Name)
+		case slave.AggregatorID != bond.Aggregator:
+			m.log(ctx).Logf("Bond %s: slave %s NOT NEGOTIATED - in aggregator %d, not the active aggregator %d (different partner or key?)",
+				bond.Name, slave.Name, slave.AggregatorID, bond.Aggregator)
+		case len(missing) > 0:
+			m.log(ctx).Logf("Bond %s: slave %s NOT NEGOTIATED - missing %s", bond.Name, slave.Name, strings.Join(missing, ","))
+		}
+	}
+}
+
+// trackChurn logs when a slave of an 802.3ad bond enters or leaves LACP
+// churn, which unlike a port still negotiating won't resolve by waiting
+func (m *Monitor) trackChurn(ctx context.Context, bond *network.BondStatus) {
+	for _, slave := range bond.Slaves {
+		key := bond.Name + "/" + slave.Name
+		churned := slave.Churned()
+		if churned == m.lacpChurned[key] {
+			continue
+		}
+		m.lacpChurned[key] = churned
+		if churned {
+			m.log(ctx).Logf("Bond %s: slave %s LACP CHURN - actor churn=%s, partner churn=%s (switch port not in the same LAG, or LACP disabled on it?)",
+				bond.Name, slave.Name, slave.ActorChurn, slave.PartnerChurn)
 		} else {
-			m.logger.Log("ARP table: EMPTY")
-			return false
+			m.log(ctx).Logf("Bond %s: slave %s LACP churn cleared", bond.Name, slave.Name)
 		}
 	}
 }
 
-// checkRoutingTable validates routing table convergence
-func (m *Monitor) checkRoutingTable() bool {
-	m.logger.Log("--- Routing Table Status ---")
+// lacpPartner returns the switch system MAC expected as a bond's LACP
+// partner, or "" if any partner is accepted
+func (m *Monitor) lacpPartner(iface string) string {
+	if mac, ok := m.config.BondPartnerMACs[iface]; ok {
+		return mac
+	}
+	return m.config.LACPPartnerMAC
+}
+
+// minSlaves returns the usable slaves a bond needs, or 0 if any one will do
+func (m *Monitor) minSlaves(iface string) int {
+	if policy, ok := m.config.InterfacePolicies[iface]; ok && policy.MinSlaves > 0 {
+		return policy.MinSlaves
+	}
+	if count, ok := m.config.BondSlaves[iface]; ok {
+		return count
+	}
+	return m.config.BondMinSlaves
+}
+
+// minSpeed returns the minimum negotiated speed for an interface, or 0 if none is required
+func (m *Monitor) minSpeed(iface string) int {
+	if policy, ok := m.config.InterfacePolicies[iface]; ok && policy.MinSpeed > 0 {
+		return policy.MinSpeed
+	}
+	if speed, ok := m.config.InterfaceSpeeds[iface]; ok {
+		return speed
+	}
+	return m.config.MinSpeed
+}
+
+// logSRIOV reports the SR-IOV relationships of an interface: the parent PF
+// and administrative link state of a VF, or the VFs of a PF and where they
+// are. A VF whose link state is "disable" has no carrier regardless of the PF.
+func (m *Monitor) logSRIOV(ctx context.Context, iface string) {
+	if pf, index, isVF := m.ifaceMonitor.VFParent(iface); isVF {
+		linkState := "unknown"
+		if sriov, err := m.ifaceMonitor.CheckSRIOVStatus(pf); err == nil && index >= 0 && index < len(sriov.VFs) {
+			linkState = sriov.VFs[index].LinkState
+		}
+		m.log(ctx).Logf("Interface %s: SR-IOV VF %d of %s, vf link state=%s", iface, index, pf, linkState)
+		return
+	}
 	
-	routeStatus, err := m.routeMonitor.CheckRoutingTable()
+	if !m.ifaceMonitor.IsSRIOVPF(iface) {
+		return
+	}
+	sriov, err := m.ifaceMonitor.CheckSRIOVStatus(iface)
 	if err != nil {
-		m.logger.Logf("Routing table: ERROR - %v", err)
-		return false
+		m.log(ctx).Logf("SR-IOV %s: ERROR - %v", iface, err)
+		return
 	}
 	
-	m.logger.Logf("Routing table: %d total routes", routeStatus.TotalRoutes)
-	m.logger.Logf("Routing table: %d default routes", routeStatus.DefaultRoutes)
-	m.logger.Logf("Routing table: %d network routes", routeStatus.NetworkRoutes)
-	m.logger.Logf("Routing table: %d host routes", routeStatus.HostRoutes)
+	onHost, passthrough := 0, 0
+	var vfs []string
+	for _, vf := range sriov.VFs {
+		location := vf.Netdev
+		switch {
+		case vf.Netdev != "":
+			onHost++
+		case vf.Driver == "vfio-pci":
+			passthrough++
+			location = "vfio-pci"
+		case vf.Driver != "":
+			location = vf.Driver
+		default:
+			location = "unbound"
+		}
+		vfs = append(vfs, fmt.Sprintf("%d=%s/%s", vf.Index, location, vf.LinkState))
+	}
+	m.log(ctx).Logf("SR-IOV %s: %d/%d VFs enabled, %d on host, %d passed through, vfs=[%s]",
+		iface, len(sriov.VFs), sriov.TotalVFs, onHost, passthrough, strings.Join(vfs, " "))
+}
+
+// checkTunnel reports whether a tunnel interface is connected: a WireGuard
+// interface needs a peer with a handshake within the configured age, a tun/tap
+// device a process attached to it. Other tunnels only need carrier.
+func (m *Monitor) checkTunnel(ctx context.Context, iface string) bool {
+	tunnel, err := m.ifaceMonitor.CheckTunnelStatus(iface)
+	if err != nil {
+		m.log(ctx).Logf("Tunnel %s: ERROR - %v", iface, err)
+		return false
+	}
 	
-	if routeStatus.HasDefaultRoute {
-		// Get detailed default route information
-		defaultRoutes, err := m.routeMonitor.GetDefaultRoutes()
-		if err == nil {
-			for _, route := range defaultRoutes {
-				m.logger.Logf("Default route: %s", route.String())
+	switch tunnel.Kind {
+	case "wireguard":
+		if len(tunnel.Peers) == 0 {
+			m.log(ctx).Logf("Tunnel %s: wireguard, NO PEERS CONFIGURED", iface)
+			return false
+		}
+		connected := 0
+		for _, peer := range tunnel.Peers {
+			endpoint := peer.Endpoint
+			if endpoint == "" {
+				endpoint = "no endpoint"
+			}
+			if peer.LastHandshake.IsZero() {
+				m.log(ctx).Logf("Tunnel %s: peer %s (%s) - NO HANDSHAKE", iface, shortKey(peer.PublicKey), endpoint)
+				continue
+			}
+			age := time.Since(peer.LastHandshake).Round(time.Second)
+			if age > m.config.TunnelHandshakeAge {
+				m.log(ctx).Logf("Tunnel %s: peer %s (%s) - STALE HANDSHAKE (%s ago, max %s)",
+					iface, shortKey(peer.PublicKey), endpoint, age, m.config.TunnelHandshakeAge)
+				continue
 			}
+			m.log(ctx).Logf("Tunnel %s: peer %s (%s) - handshake %s ago", iface, shortKey(peer.PublicKey), endpoint, age)
+			connected++
 		}
-		
-		m.logger.Log("*** ROUTING TABLE HAS DEFAULT ROUTE ***")
+		m.log(ctx).Logf("Tunnel %s: wireguard, %d/%d peers with a recent handshake", iface, connected, len(tunnel.Peers))
+		return connected > 0
+	case "tun", "tap":
+		if !tunnel.Attached {
+			m.log(ctx).Logf("Tunnel %s: %s, NO PROCESS ATTACHED (VPN daemon not running?)", iface, tunnel.Kind)
+			return false
+		}
+		m.log(ctx).Logf("Tunnel %s: %s, process attached", iface, tunnel.Kind)
+		return true
+	default:
+		m.log(ctx).Logf("Tunnel %s: %s, only carrier is checked", iface, tunnel.Kind)
 		return true
-	} else {
-		m.logger.Log("Routing table: NO DEFAULT ROUTE")
-		return false
 	}
 }
 
-// updateStates updates internal state and logs transitions
-func (m *Monitor) updateStates(allUp, gwReachable, servicesReady, dnsWorking, nmConnectivity, arpValid, routingValid bool) {
-	// Interface state transitions
-	if allUp && !m.allInterfacesUp {
-		m.logger.Log("*** ALL INTERFACES ARE NOW UP ***")
-		m.allInterfacesUp = true
-	} else if !allUp && m.allInterfacesUp {
-		m.logger.Log("*** SOME INTERFACES ARE DOWN ***")
-		m.allInterfacesUp = false
-	}
-	
-	// Gateway state transitions
-	if gwReachable && !m.gatewayReachable {
-		m.logger.Log("*** GATEWAY IS NOW REACHABLE ***")
-		m.gatewayReachable = true
-	} else if !gwReachable && m.gatewayReachable {
-		m.logger.Log("*** GATEWAY IS NO LONGER REACHABLE ***")
-		m.gatewayReachable = false
-	}
-	
-	// Services state transitions
-	if servicesReady && !m.servicesReady {
-		m.logger.Log("*** NETWORK SERVICES ARE NOW READY ***")
-		m.servicesReady = true
-	} else if !servicesReady && m.servicesReady {
-		m.logger.Log("*** NETWORK SERVICES NO LONGER READY ***")
-		m.servicesReady = false
-	}
-	
-	// DNS state transitions
-	if dnsWorking && !m.dnsWorking {
-		m.logger.Log("*** DNS RESOLUTION IS NOW WORKING ***")
-		m.dnsWorking = true
-	} else if !dnsWorking && m.dnsWorking {
-		m.logger.Log("*** DNS RESOLUTION NO LONGER WORKING ***")
-		m.dnsWorking = false
-	}
-	
-	// NetworkManager connectivity state transitions
-	if nmConnectivity && !m.nmConnectivityFull {
-		m.logger.Log("*** NETWORKMANAGER CONNECTIVITY IS NOW FULL ***")
-		m.nmConnectivityFull = true
-	} else if !nmConnectivity && m.nmConnectivityFull {
-		m.logger.Log("*** NETWORKMANAGER CONNECTIVITY NO LONGER FULL ***")
-		m.nmConnectivityFull = false
-	}
-	
-	// ARP table state transitions
-	if arpValid && !m.arpTableValid {
-		m.logger.Log("*** ARP TABLE IS NOW VALID ***")
-		m.arpTableValid = true
-	} else if !arpValid && m.arpTableValid {
-		m.logger.Log("*** ARP TABLE NO LONGER VALID ***")
-		m.arpTableValid = false
-	}
-	
-	// Routing table state transitions
-	if routingValid && !m.routingTableValid {
-		m.logger.Log("*** ROUTING TABLE IS NOW VALID ***")
-		m.routingTableValid = true
-	} else if !routingValid && m.routingTableValid {
-		m.logger.Log("*** ROUTING TABLE NO LONGER VALID ***")
-		m.routingTableValid = false
-	}
-}
\ No newline at end of file
+// checkWireless reports whether a wireless interface is ready: wpa_supplicant
+// has completed association and key negotiation, with one of the expected
+// SSIDs when any are configured, at or above the minimum signal strength.
+// Interfaces wpa_supplicant doesn't manage only need carrier unless SSIDs or
+// a signal threshold were configured, which can't be verified without it.
+func (m *Monitor) checkWireless(ctx context.Context, iface string) bool {
+	enforced := len(m.config.WirelessSSIDs) > 0 || m.config.WirelessMinSignal < 0
+	
+	wireless, err := m.wireless.Status(ctx, iface)
+	if errors.Is(err, system.ErrWPASupplicantUnavailable) {
+		if enforced {
+			m.log(ctx).Logf("Wireless %s: NOT AVAILABLE - can't verify SSID or signal (%v)", iface, err)
+			return false
+		}
+		m.log(ctx).Logf("Wireless %s: NOT AVAILABLE - only carrier is checked (%v)", iface, err)
+		return true
+	}
+	if err != nil {
+		m.log(ctx).Logf("Wireless %s: ERROR - %v", iface, err)
+		return false
+	}
+	
+	if !wireless.Associated() {
+		m.log(ctx).Logf("Wireless %s: NOT ASSOCIATED (wpa_supplicant state %s)", iface, wireless.State)
+		return false
+	}
+	
+	signal := "unknown"
+	if wireless.Signal != 0 {
+		signal = fmt.Sprintf("%ddBm", wireless.Signal)
+	}
+	m.log(ctx).Logf("Wireless %s: ssid=%q, bssid=%s, signal=%s, state=%s", iface, wireless.SSID, wireless.BSSID, signal, wireless.State)
+	
+	if len(m.config.WirelessSSIDs) > 0 && !containsString(m.config.WirelessSSIDs, wireless.SSID) {
+		m.log(ctx).Logf("Wireless %s: UNEXPECTED SSID %q (expected %s)", iface, wireless.SSID, strings.Join(m.config.WirelessSSIDs, " "))
+		return false
+	}
+	
+	if m.config.WirelessMinSignal < 0 {
+		if wireless.Signal == 0 {
+			m.log(ctx).Logf("Wireless %s: SIGNAL UNKNOWN (minimum %ddBm)", iface, m.config.WirelessMinSignal)
+			return false
+		}
+		if wireless.Signal < m.config.WirelessMinSignal {
+			m.log(ctx).Logf("Wireless %s: WEAK SIGNAL (%ddBm, minimum %ddBm)", iface, wireless.Signal, m.config.WirelessMinSignal)
+			return false
+		}
+	}
+	
+	return true
+}
+
+// shortKey abbreviates a WireGuard public key for logging
+func shortKey(key string) string {
+	if len(key) > 8 {
+		return key[:8] + "..."
+	}
+	return key
+}
+
+// checkGatewayConnectivity tests gateway reachability, or the configured
+// ping targets instead when any are set
+func (m *Monitor) checkGatewayConnectivity(ctx context.Context) *CheckResult {
+	result := newResult()
+	if len(m.config.PingTargets) > 0 {
+		return m.checkPingTargets(ctx, result)
+	}
+	
+	if err := m.checkDefaultGateway(ctx, result); err != nil {
+		// A gateway that answers too slowly is reported as degraded rather than down
+		if isLatencyError(err) {
+			result.detail("degraded", true)
+		}
+		return result.failWith(err)
+	}
+	return result.pass()
+}
+
+// checkDefaultGateway tests reachability of the auto-discovered default
+// gateway(s) of the configured address family
+func (m *Monitor) checkDefaultGateway(ctx context.Context, result *CheckResult) error {
+	switch m.config.GatewayFamily {
+	case "ipv6":
+		return m.checkDefaultGateway6(ctx, result)
+	case "either":
+		// Test both so the log shows the state of each family
+		err4 := m.checkDefaultGateway4(ctx, result)
+		err6 := m.checkDefaultGateway6(ctx, result)
+		if err4 != nil && err6 != nil {
+			return fmt.Errorf("no default gateway reachable: %w; %w", err4, err6)
+		}
+		return nil
+	case "both":
+		err4 := m.checkDefaultGateway4(ctx, result)
+		err6 := m.checkDefaultGateway6(ctx, result)
+		if err4 != nil && (err6 == nil || !isLatencyError(err4)) {
+			return err4
+		}
+		return err6
+	default:
+		return m.checkDefaultGateway4(ctx, result)
+	}
+}
+
+// checkDefaultGateway4 tests reachability of the IPv4 default gateway, or
+// of each path of an ECMP default route
+func (m *Monitor) checkDefaultGateway4(ctx context.Context, result *CheckResult) error {
+	hops, err := m.connectivity.GetDefaultNextHops()
+	if err != nil {
+		m.log(ctx).Logf("Gateway: ERROR - %v", err)
+		return err
+	}
+	if len(hops) > 1 {
+		return m.checkNextHops(ctx, hops, result)
+	}
+	
+	gateway := hops[0].Gateway
+	result.detail("gateway", gateway)
+	return m.probeGateway4(ctx, fmt.Sprintf("Gateway %s", gateway), gateway, result)
+}
+
+// checkNextHops checks each path of an ECMP default route on its own: its
+// gateway must answer the gateway probe and be resolved in the neighbor
+// table of the path's interface. The kernel keeps hashing flows onto a path
+// whose gateway is dead, so every path has to be healthy.
+func (m *Monitor) checkNextHops(ctx context.Context, hops []network.NextHop, result *CheckResult) error {
+	m.log(ctx).Logf("Default route: ECMP with %d paths", len(hops))
+	result.detail("gateway", hops[0].Gateway)
+	result.detail("ecmp_paths", len(hops))
+	
+	var failed []string
+	for i, hop := range hops {
+		label := fmt.Sprintf("Gateway %s dev %s (path %d/%d, weight %d)", hop.Gateway, hop.Interface, i+1, len(hops), hop.Weight)
+		err := m.probeGateway4(ctx, label, hop.Gateway, result)
+		if err == nil {
+			err = m.resolveNextHop(ctx, label, hop)
+		}
+		if err != nil {
+			failed = append(failed, hop.Gateway.String())
+		}
+	}
+	
+	healthy := len(hops) - len(failed)
+	result.detail("ecmp_healthy", healthy)
+	if len(failed) > 0 {
+		m.log(ctx).Logf("Default route: ECMP DEGRADED - %d/%d paths healthy (failed: %s)", healthy, len(hops), strings.Join(failed, " "))
+		return fmt.Errorf("%d/%d ECMP paths unhealthy: %s", len(failed), len(hops), strings.Join(failed, " "))
+	}
+	m.log(ctx).Logf("Default route: all %d ECMP paths healthy", len(hops))
+	return nil
+}
+
+// resolveNextHop checks that the gateway of an ECMP path has a resolved
+// neighbor entry on the path's own interface
+func (m *Monitor) resolveNextHop(ctx context.Context, label string, hop network.NextHop) error {
+	status, err := m.arpMonitor.CheckARPTable(ctx, []string{hop.Interface}, hop.Gateway)
+	if err != nil {
+		m.log(ctx).Logf("%s: ARP ERROR - %v", label, err)
+		return err
+	}
+	if !status.GatewayResolved {
+		m.log(ctx).Logf("%s: ARP NOT RESOLVED on %s", label, hop.Interface)
+		return fmt.Errorf("gateway %s not resolved on %s", hop.Gateway, hop.Interface)
+	}
+	m.log(ctx).Logf("%s: ARP resolved to %s", label, status.GatewayMAC)
+	return nil
+}
+
+// probeGateway4 tests reachability of an IPv4 gateway by ping, ARP or both,
+// per the configured gateway probe
+func (m *Monitor) probeGateway4(ctx context.Context, label string, gateway net.IP, result *CheckResult) error {
+	if m.config.GatewayProbe == "arp" {
+		return m.arpGateway(ctx, label, gateway, result)
+	}
+	
+	stats, err := m.pingTarget(ctx, label, "gateway", &net.IPAddr{IP: gateway})
+	if stats.Received > 0 {
+		result.detail("rtt", stats.AvgRTT)
+		result.detail("loss", stats.LossPercent())
+		m.recordGatewayRTT("ipv4", stats.AvgRTT)
+	}
+	if err == nil && m.config.PathMTUProbe {
+		err = m.probePathMTU(ctx, label, &net.IPAddr{IP: gateway}, result, "path_mtu")
+	}
+	
+	switch m.config.GatewayProbe {
+	case "any":
+		// Fall back to ARP for gateways that drop ICMP; a slow answer to
+		// ping still counts as degraded
+		if err != nil && !isLatencyError(err) && m.arpGateway(ctx, label, gateway, result) == nil {
+			m.log(ctx).Logf("%s: REACHABLE by ARP (ICMP filtered?)", label)
+			return nil
+		}
+	case "all":
+		if arpErr := m.arpGateway(ctx, label, gateway, result); err == nil {
+			err = arpErr
+		}
+	}
+	return err
+}
+
+// arpGateway probes the gateway with an ARP request on its egress interface,
+// which answers even when the gateway drops ICMP
+func (m *Monitor) arpGateway(ctx context.Context, label string, gateway net.IP, result *CheckResult) error {
+	reply, err := network.ARPPing(ctx, gateway, m.config.PingTimeout)
+	if err != nil {
+		m.log(ctx).Logf("%s: NO ARP REPLY - %v", label, err)
+		return err
+	}
+	m.log(ctx).Logf("%s: ARP REPLY from %s via %s (rtt %s)", label, reply.MAC, reply.Interface, reply.RTT)
+	result.detail("arp_rtt", reply.RTT)
+	result.detail("gateway_mac", reply.MAC)
+	return nil
+}
+
+// checkDefaultGateway6 tests reachability of the IPv6 default gateway via ICMPv6
+func (m *Monitor) checkDefaultGateway6(ctx context.Context, result *CheckResult) error {
+	gateway, err := m.connectivity.GetDefaultGateway6()
+	if err != nil {
+		m.log(ctx).Logf("Gateway IPv6: ERROR - %v", err)
+		return err
+	}
+	result.detail("gateway6", gateway)
+	
+	label := fmt.Sprintf("Gateway %s", gateway)
+	stats, err := m.pingTarget(ctx, label, "gateway", gateway)
+	if stats.Received > 0 {
+		result.detail("rtt6", stats.AvgRTT)
+		result.detail("loss6", stats.LossPercent())
+		m.recordGatewayRTT("ipv6", stats.AvgRTT)
+	}
+	if err == nil && m.config.PathMTUProbe {
+		err = m.probePathMTU(ctx, label, gateway, result, "path_mtu6")
+	}
+	return err
+}
+
+// probePathMTU pings a reachable gateway with an unfragmentable packet of the
+// expected MTU of its interface (the interface MTU when none is configured),
+// catching jumbo frame mismatches on the switch path that small pings miss
+func (m *Monitor) probePathMTU(ctx context.Context, label string, addr *net.IPAddr, result *CheckResult, detail string) error {
+	iface, mtu, err := m.connectivity.RouteInterface(addr.IP)
+	if err != nil {
+		m.log(ctx).Logf("%s: PATH MTU UNKNOWN - %v", label, err)
+		return err
+	}
+	if expected := m.expectedMTU(iface); expected > 0 {
+		mtu = expected
+	}
+	
+	rtt, err := network.ProbeMTU(ctx, addr, mtu, m.config.PingTimeout)
+	if err != nil {
+		m.log(ctx).Logf("%s: PATH MTU %d FAILED via %s - %v", label, mtu, iface, err)
+		return fmt.Errorf("path MTU %d via %s: %w", mtu, iface, err)
+	}
+	m.log(ctx).Logf("%s: PATH MTU %d OK via %s (rtt %s)", label, mtu, iface, rtt)
+	result.detail(detail, mtu)
+	return nil
+}
+
+// latencyError reports a target that answers, but more slowly than allowed
+type latencyError struct {
+	what  string  // "average rtt" or "resolution time"
+	rtt   time.Duration
+	limit time.Duration
+}
+
+func (e *latencyError) Error() string {
+	return fmt.Sprintf("%s %s exceeds %s", e.what, e.rtt, e.limit)
+}
+
+// isLatencyError reports whether err is a reachable target exceeding its latency limit
+func isLatencyError(err error) bool {
+	var latency *latencyError
+	return errors.As(err, &latency)
+}
+
+// recordDNSLatency exports a resolution time when metrics are enabled
+func (m *Monitor) recordDNSLatency(hostname, server string, latency time.Duration) {
+	if m.metrics != nil {
+		m.metrics.SetDNSLatency(hostname, server, latency)
+	}
+}
+
+// recordGatewayRTT exports the gateway round-trip time when metrics are enabled
+func (m *Monitor) recordGatewayRTT(family string, rtt time.Duration) {
+	if m.metrics != nil {
+		m.metrics.SetGatewayRTT(family, rtt)
+	}
+}
+
+// pingTarget pings a gateway or ping target with its echo request count and
+// checks the replies against its loss and latency limits, logging the
+// outcome under label
+func (m *Monitor) pingTarget(ctx context.Context, label, target string, addr *net.IPAddr) (*network.PingStats, error) {
+	count, maxLoss, maxRTT := m.config.PingSettings(target)
+	stats := m.connectivity.PingTarget(ctx, addr, count)
+	
+	var err error
+	switch {
+	case stats.Received == 0 && stats.Err != nil:
+		err = fmt.Errorf("ping failed: %w", stats.Err)
+	case stats.Received == 0:
+		err = fmt.Errorf("ping failed: no echo requests sent")
+	case stats.LossPercent() > maxLoss:
+		err = fmt.Errorf("%d%% packet loss (%d/%d replies) exceeds %d%%", stats.LossPercent(), stats.Received, stats.Sent, maxLoss)
+	case maxRTT > 0 && stats.AvgRTT > maxRTT:
+		err = &latencyError{what: "average rtt", rtt: stats.AvgRTT, limit: maxRTT}
+		m.log(ctx).Logf("%s: DEGRADED - %v", label, err)
+		return stats, err
+	}
+	if err != nil {
+		m.log(ctx).Logf("%s: NOT REACHABLE - %v", label, err)
+		return stats, err
+	}
+	
+	if stats.Sent == 1 {
+		m.log(ctx).Logf("%s: REACHABLE (rtt %s)", label, stats.AvgRTT)
+	} else {
+		m.log(ctx).Logf("%s: REACHABLE (%d/%d replies, rtt min/avg/max %s/%s/%s)",
+			label, stats.Received, stats.Sent, stats.MinRTT, stats.AvgRTT, stats.MaxRTT)
+	}
+	return stats, nil
+}
+
+// checkPingTargets tests reachability of the configured ping targets under
+// the configured any/all policy
+func (m *Monitor) checkPingTargets(ctx context.Context, result *CheckResult) *CheckResult {
+	reachable := 0
+	total := len(m.config.PingTargets)
+	
+	// The default gateway optionally counts as one more target
+	if m.config.PingGateway {
+		total++
+		if m.checkDefaultGateway(ctx, result) == nil {
+			reachable++
+		}
+	}
+	
+	for _, target := range m.config.PingTargets {
+		ip, err := m.connectivity.ResolveTarget(ctx, target)
+		if err != nil {
+			m.log(ctx).Logf("Ping target %s: NOT RESOLVED (%s timeout) - %v", target, m.config.DNSTimeout, err)
+			continue
+		}
+		
+		label := fmt.Sprintf("Ping target %s (%s)", target, ip)
+		if _, err := m.pingTarget(ctx, label, target, &net.IPAddr{IP: ip}); err != nil {
+			continue
+		}
+		reachable++
+	}
+	result.detail("reachable", reachable)
+	result.detail("targets", total)
+	
+	if m.config.PingPolicy == "any" {
+		if reachable > 0 {
+			m.log(ctx).Logf("Ping targets: %d/%d REACHABLE (any target sufficient)", reachable, total)
+			return result.pass()
+		}
+		m.log(ctx).Logf("Ping targets: NONE REACHABLE (%d total)", total)
+		return result.fail()
+	}
+	
+	if reachable == total {
+		m.log(ctx).Logf("Ping targets: ALL REACHABLE (%d/%d)", reachable, total)
+		return result.pass()
+	}
+	m.log(ctx).Logf("Ping targets: %d NOT REACHABLE, %d reachable (need all %d)", total-reachable, reachable, total)
+	return result.fail()
+}
+
+// checkDNSResolution tests DNS resolution of the resolver hostnames; the
+// configured quorum of hostnames must resolve
+func (m *Monitor) checkDNSResolution(ctx context.Context) *CheckResult {
+	result := newResult()
+	hostnames := m.config.ResolverHostnames
+	if len(hostnames) == 1 {
+		if err := m.checkDNSHostname(ctx, hostnames[0]); err != nil {
+			// A resolver that answers too slowly is reported as degraded rather than failing
+			if isLatencyError(err) {
+				result.detail("degraded", true)
+			}
+			return result.failWith(err)
+		}
+		return result.pass()
+	}
+	
+	resolved, slow := 0, 0
+	var lastErr error
+	for _, hostname := range hostnames {
+		err := m.checkDNSHostname(ctx, hostname)
+		switch {
+		case err == nil:
+			resolved++
+		case isLatencyError(err):
+			slow++
+			lastErr = err
+		default:
+			lastErr = err
+		}
+	}
+	
+	total := len(hostnames)
+	needed, _ := config.ParseQuorum(m.config.DNSQuorum, total)
+	result.detail("resolved", resolved)
+	result.detail("quorum", needed)
+	if resolved >= needed {
+		m.log(ctx).Logf("DNS resolution: %d/%d hostnames resolved (quorum %d met)", resolved, total, needed)
+		return result.pass()
+	}
+	if resolved+slow >= needed {
+		result.detail("degraded", true)
+		m.log(ctx).Logf("DNS resolution: %d/%d hostnames resolved, %d too slowly (need %d within %s)",
+			resolved, total, slow, needed, m.config.DNSMaxLatency)
+		return result.failWith(lastErr)
+	}
+	m.log(ctx).Logf("DNS resolution: %d/%d hostnames resolved (need %d)", resolved, total, needed)
+	return result.failWith(lastErr)
+}
+
+// checkDNSHostname resolves a single resolver hostname entry ("host" or
+// "host/TYPE") through the system resolver, or through every configured DNS
+// server when any are set, or through each of the system resolver's
+// nameservers when they are to be checked individually
+func (m *Monitor) checkDNSHostname(ctx context.Context, hostname string) error {
+	if len(m.config.DNSServers) > 0 {
+		return m.checkDNSServers(ctx, hostname, m.config.DNSServers)
+	}
+	if m.config.DNSSystemServers {
+		servers, err := m.connectivity.UpstreamNameservers()
+		if err == nil && len(servers) == 0 {
+			err = fmt.Errorf("no nameservers configured")
+		}
+		if err != nil {
+			m.log(ctx).Logf("DNS resolution for %s: FAILED - %v", hostname, err)
+			return err
+		}
+		return m.checkDNSServers(ctx, hostname, servers)
+	}
+	
+	name, recordType, _ := config.ParseResolverHostname(hostname)
+	elapsed, err := m.connectivity.CheckDNSResolution(ctx, name, recordType)
+	if err != nil {
+		m.log(ctx).Logf("DNS resolution for %s: FAILED (%s timeout) - %v", 
+			hostname, m.config.DNSTimeout, err)
+		return err
+	}
+	m.recordDNSLatency(hostname, "system", elapsed)
+	
+	if latency := m.dnsLatencyError(elapsed); latency != nil {
+		m.log(ctx).Logf("DNS resolution for %s: SLOW - %v", hostname, latency)
+		return latency
+	}
+	m.log(ctx).Logf("DNS resolution for %s: SUCCESS in %s (%s timeout)", 
+		hostname, elapsed, m.config.DNSTimeout)
+	return nil
+}
+
+// dnsLatencyError returns a latency error when a lookup took longer than the
+// configured DNS max latency; a resolver working through a dead primary
+// answers, but only after timing out on it for every query
+func (m *Monitor) dnsLatencyError(elapsed time.Duration) *latencyError {
+	if m.config.DNSMaxLatency > 0 && elapsed > m.config.DNSMaxLatency {
+		return &latencyError{what: "resolution time", rtt: elapsed, limit: m.config.DNSMaxLatency}
+	}
+	return nil
+}
+
+// checkDNSServers resolves a hostname through each of the DNS servers;
+// every server must answer
+func (m *Monitor) checkDNSServers(ctx context.Context, hostname string, servers []string) error {
+	name, recordType, _ := config.ParseResolverHostname(hostname)
+	failed := 0
+	var slowest *latencyError
+	for _, server := range servers {
+		elapsed, err := m.connectivity.CheckDNSServer(ctx, server, name, recordType)
+		if err != nil {
+			m.log(ctx).Logf("DNS resolution for %s via %s: FAILED (%s timeout) - %v",
+				hostname, server, m.config.DNSTimeout, err)
+			failed++
+			continue
+		}
+		m.recordDNSLatency(hostname, server, elapsed)
+		
+		if latency := m.dnsLatencyError(elapsed); latency != nil {
+			m.log(ctx).Logf("DNS resolution for %s via %s: SLOW - %v", hostname, server, latency)
+			if slowest == nil || latency.rtt > slowest.rtt {
+				slowest = latency
+			}
+		} else {
+			m.log(ctx).Logf("DNS resolution for %s via %s: SUCCESS in %s (%s timeout)",
+				hostname, server, elapsed, m.config.DNSTimeout)
+		}
+	}
+	
+	total := len(servers)
+	if failed > 0 {
+		m.log(ctx).Logf("DNS servers for %s: %d/%d FAILED", hostname, failed, total)
+		return fmt.Errorf("%d/%d DNS servers failed to resolve %s", failed, total, hostname)
+	}
+	if slowest != nil {
+		m.log(ctx).Logf("DNS servers for %s: ALL %d RESPONDING, some too slowly", hostname, total)
+		return slowest
+	}
+	m.log(ctx).Logf("DNS servers for %s: ALL %d RESPONDING", hostname, total)
+	return nil
+}
+
+// checkEncryptedDNS checks that every DNS-over-TLS and DNS-over-HTTPS server
+// resolves the DNS quorum of resolver hostnames over its encrypted transport,
+// which a lookup through the system resolver doesn't prove
+func (m *Monitor) checkEncryptedDNS(ctx context.Context) *CheckResult {
+	result := newResult()
+	servers := m.config.EncryptedDNS
+	hostnames := m.config.ResolverHostnames
+	needed, _ := config.ParseQuorum(m.config.DNSQuorum, len(hostnames))
+	
+	working := 0
+	var lastErr error
+	for _, server := range servers {
+		transport, address, serverName, _ := config.ParseEncryptedDNSServer(server)
+		resolved := 0
+		for _, hostname := range hostnames {
+			name, recordType, _ := config.ParseResolverHostname(hostname)
+			elapsed, err := network.CheckEncryptedDNS(ctx, transport, address, serverName, name, recordType, m.config.DNSTimeout)
+			if err != nil {
+				m.log(ctx).Logf("Encrypted DNS resolution for %s via %s: FAILED (%s timeout) - %v",
+					hostname, server, m.config.DNSTimeout, err)
+				lastErr = err
+				continue
+			}
+			m.log(ctx).Logf("Encrypted DNS resolution for %s via %s: SUCCESS in %s (%s timeout)",
+				hostname, server, elapsed, m.config.DNSTimeout)
+			resolved++
+		}
+		if resolved >= needed {
+			working++
+		}
+	}
+	result.detail("working", working)
+	result.detail("servers", len(servers))
+	
+	if working == len(servers) {
+		m.log(ctx).Logf("Encrypted DNS servers: ALL WORKING (%d/%d)", working, len(servers))
+		return result.pass()
+	}
+	m.log(ctx).Logf("Encrypted DNS servers: %d FAILED, %d working (need all %d)", len(servers)-working, working, len(servers))
+	return result.failWith(lastErr)
+}
+
+// checkResolvConf checks that /etc/resolv.conf exists, isn't a dangling
+// symlink, lists nameservers and that each of them answers queries. With
+// systemd-resolved running, a resolv.conf that bypasses it is only logged,
+// while one pointing at the stub without resolved running fails. Whether
+// resolved runs is taken from its unit state; when that can't be read,
+// neither the failure nor the warning is reported.
+func (m *Monitor) checkResolvConf(ctx context.Context) *CheckResult {
+	result := newResult()
+	rc, err := network.InspectResolvConf()
+	if err != nil {
+		m.log(ctx).Logf("resolv.conf: BROKEN - %v", err)
+		return result.failWith(err)
+	}
+	
+	source := "regular file"
+	if rc.Target != "" {
+		source = "symlink to " + rc.Target
+	}
+	if len(rc.Nameservers) == 0 {
+		m.log(ctx).Logf("resolv.conf: NO NAMESERVERS (%s)", source)
+		return result.fail()
+	}
+	m.log(ctx).Logf("resolv.conf: %s, nameservers %s", source, strings.Join(rc.Nameservers, " "))
+	
+	running, state, known := m.resolvedState(ctx)
+	if known && !running && rc.UsesResolvedStub() {
+		m.log(ctx).Logf("resolv.conf: BROKEN - points at the systemd-resolved stub but %s is %s", resolvedUnit, state)
+		return result.fail()
+	}
+	if running && !rc.ManagedByResolved {
+		m.log(ctx).Log("resolv.conf: Warning - systemd-resolved is running but resolv.conf isn't linked to its stub-resolv.conf; lookups bypass it")
+	}
+	
+	reachable := 0
+	for _, server := range rc.Nameservers {
+		rtt, err := network.ProbeNameserver(ctx, server, m.config.DNSTimeout)
+		if err != nil {
+			m.log(ctx).Logf("resolv.conf nameserver %s: NOT ANSWERING - %v", server, err)
+			continue
+		}
+		m.log(ctx).Logf("resolv.conf nameserver %s: ANSWERING (%s)", server, rtt)
+		reachable++
+	}
+	result.detail("reachable", reachable)
+	result.detail("nameservers", len(rc.Nameservers))
+	
+	if reachable == len(rc.Nameservers) {
+		m.log(ctx).Logf("resolv.conf: OK (%d/%d nameservers answering)", reachable, len(rc.Nameservers))
+		return result.pass()
+	}
+	m.log(ctx).Logf("resolv.conf: %d NAMESERVERS NOT ANSWERING, %d answering (need all %d)",
+		len(rc.Nameservers)-reachable, reachable, len(rc.Nameservers))
+	return result.fail()
+}
+
+// checkDNSSEC checks that every resolver validates DNSSEC: the signed name
+// must come back with the AD flag and the bogus name must be refused with
+// SERVFAIL, which only a validating resolver does
+func (m *Monitor) checkDNSSEC(ctx context.Context) *CheckResult {
+	result := newResult()
+	servers := m.config.DNSServers
+	if len(servers) == 0 {
+		var err error
+		servers, err = m.connectivity.SystemNameservers()
+		if err == nil && len(servers) == 0 {
+			err = fmt.Errorf("no nameservers configured")
+		}
+		if err != nil {
+			m.log(ctx).Logf("DNSSEC: ERROR - %v", err)
+			return result.failWith(err)
+		}
+	}
+	
+	validating := 0
+	var lastErr error
+	for _, server := range servers {
+		if err := m.checkDNSSECServer(ctx, server); err != nil {
+			lastErr = err
+			continue
+		}
+		validating++
+	}
+	result.detail("validating", validating)
+	result.detail("servers", len(servers))
+	
+	if validating == len(servers) {
+		m.log(ctx).Logf("DNSSEC: VALIDATED (%d/%d servers validating)", validating, len(servers))
+		return result.pass()
+	}
+	m.log(ctx).Logf("DNSSEC: %d SERVERS NOT VALIDATING, %d validating (need all %d)",
+		len(servers)-validating, validating, len(servers))
+	return result.failWith(lastErr)
+}
+
+// checkDNSSECServer queries one server for the signed and bogus names
+func (m *Monitor) checkDNSSECServer(ctx context.Context, server string) error {
+	signed := m.config.DNSSECSignedName
+	answer, err := network.QueryDNSSEC(ctx, server, signed, m.config.DNSTimeout)
+	if err != nil {
+		m.log(ctx).Logf("DNSSEC via %s: FAILED (%s timeout) - %v", server, m.config.DNSTimeout, err)
+		return err
+	}
+	if answer.Rcode != 0 {
+		m.log(ctx).Logf("DNSSEC via %s: %s FAILED (%s)", server, signed, answer.RcodeName())
+		return fmt.Errorf("%s answered %s for %s", server, answer.RcodeName(), signed)
+	}
+	if !answer.Authenticated {
+		m.log(ctx).Logf("DNSSEC via %s: NOT VALIDATING (%s answered without the AD flag)", server, signed)
+		return fmt.Errorf("%s doesn't validate DNSSEC", server)
+	}
+	m.log(ctx).Logf("DNSSEC via %s: %s AUTHENTICATED in %s", server, signed, answer.RTT)
+	
+	bogus := m.config.DNSSECBogusName
+	if bogus == "" {
+		return nil
+	}
+	answer, err = network.QueryDNSSEC(ctx, server, bogus, m.config.DNSTimeout)
+	if err != nil {
+		m.log(ctx).Logf("DNSSEC via %s: FAILED (%s timeout) - %v", server, m.config.DNSTimeout, err)
+		return err
+	}
+	if !answer.Bogus() {
+		m.log(ctx).Logf("DNSSEC via %s: ACCEPTS BOGUS SIGNATURES (%s answered %s)", server, bogus, answer.RcodeName())
+		return fmt.Errorf("%s answered %s for the mis-signed %s", server, answer.RcodeName(), bogus)
+	}
+	m.log(ctx).Logf("DNSSEC via %s: %s REJECTED as bogus", server, bogus)
+	return nil
+}
+
+// checkHostname checks that the hostname resolves through nsswitch, as
+// `hostname -f` does, to an address of this host; daemons that bind or
+// register themselves by name fail at boot when it doesn't
+func (m *Monitor) checkHostname(ctx context.Context) *CheckResult {
+	result := newResult()
+	hostname, err := os.Hostname()
+	if err != nil {
+		m.log(ctx).Logf("Hostname: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	if hostname == "" || hostname == "localhost" || hostname == "(none)" {
+		m.log(ctx).Logf("Hostname: NOT SET (%q)", hostname)
+		return result.fail()
+	}
+	
+	addrs, fqdn, err := m.connectivity.ResolveHostname(ctx, hostname)
+	if err != nil {
+		m.log(ctx).Logf("Hostname %s: NOT RESOLVING (%s timeout) - %v", hostname, m.config.DNSTimeout, err)
+		return result.failWith(err)
+	}
+	result.detail("fqdn", fqdn)
+	
+	var all, local, loopback []string
+	for _, addr := range addrs {
+		all = append(all, addr.String())
+		if !network.IsLocalAddress(addr) {
+			continue
+		}
+		if addr.IsLoopback() {
+			loopback = append(loopback, addr.String())
+		} else {
+			local = append(local, addr.String())
+		}
+	}
+	
+	if len(local) == 0 && len(loopback) == 0 {
+		m.log(ctx).Logf("Hostname %s: RESOLVES TO NO LOCAL ADDRESS (%s -> %s)", hostname, fqdn, strings.Join(all, " "))
+		return result.fail()
+	}
+	
+	// A Debian-style "127.0.1.1 host.example.com host" entry resolves, but
+	// services registering by name would advertise an unreachable address
+	if len(local) == 0 {
+		m.log(ctx).Logf("Hostname %s: RESOLVES ONLY TO LOOPBACK (%s -> %s)", hostname, fqdn, strings.Join(loopback, " "))
+		return result.fail()
+	}
+	if !strings.Contains(fqdn, ".") {
+		m.log(ctx).Logf("Hostname %s: Warning - no domain, hostname -f returns the short name", hostname)
+	}
+	m.log(ctx).Logf("Hostname %s: OK (%s -> %s)", hostname, fqdn, strings.Join(local, " "))
+	return result.pass()
+}
+
+// checkReverseDNS checks that each of the host's primary addresses has a PTR
+// record, which Kerberos, Kafka and Hadoop among others need at startup; a
+// PTR name that doesn't resolve back to the address is logged but accepted
+func (m *Monitor) checkReverseDNS(ctx context.Context) *CheckResult {
+	result := newResult()
+	addrs := m.connectivity.PrimaryAddresses()
+	if len(addrs) == 0 {
+		m.log(ctx).Log("Reverse DNS: NO PRIMARY ADDRESSES (no default route with a source address)")
+		return result.fail()
+	}
+	
+	resolved := 0
+	var lastErr error
+	for _, addr := range addrs {
+		names, err := m.connectivity.ReverseLookup(ctx, addr)
+		if err != nil {
+			m.log(ctx).Logf("Reverse DNS %s: FAILED (%s timeout) - %v", addr, m.config.DNSTimeout, err)
+			lastErr = err
+			continue
+		}
+		if len(names) == 0 {
+			m.log(ctx).Logf("Reverse DNS %s: FAILED - no names returned", addr)
+			lastErr = fmt.Errorf("no PTR names for %s", addr)
+			continue
+		}
+		
+		confirmed := ""
+		if !m.connectivity.ForwardConfirms(ctx, names[0], addr) {
+			confirmed = fmt.Sprintf(" - warning: %s doesn't resolve back to %s", names[0], addr)
+		}
+		m.log(ctx).Logf("Reverse DNS %s: %s%s", addr, strings.Join(names, " "), confirmed)
+		result.detail(addr.String(), names[0])
+		resolved++
+	}
+	
+	if resolved == len(addrs) {
+		m.log(ctx).Logf("Reverse DNS: ALL RESOLVED (%d/%d addresses)", resolved, len(addrs))
+		return result.pass()
+	}
+	m.log(ctx).Logf("Reverse DNS: %d NOT RESOLVED, %d resolved (need all %d)", len(addrs)-resolved, resolved, len(addrs))
+	return result.failWith(lastErr)
+}
+
+// checkResolved checks that systemd-resolved has received DNS servers it can
+// use for names outside any link's routing domains, from DHCP, NetworkManager,
+// networkd or resolved.conf; a lookup alone can't tell that the servers
+// haven't arrived yet
+func (m *Monitor) checkResolved(ctx context.Context) *CheckResult {
+	result := newResult()
+	status, err := m.resolved.Status(ctx)
+	if errors.Is(err, system.ErrResolvedUnavailable) {
+		m.log(ctx).Logf("Resolved: NOT AVAILABLE - skipping (%v)", err)
+		return result.pass() // Don't block if resolved isn't in use
+	}
+	if err != nil {
+		m.log(ctx).Logf("Resolved: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	
+	usable := 0
+	for _, server := range status.Servers {
+		scope := "global"
+		if server.Interface != "" {
+			scope = "link " + server.Interface
+		}
+		route := ""
+		if !server.DefaultRoute {
+			route = " (routing domains only)"
+		} else {
+			usable++
+		}
+		m.log(ctx).Logf("Resolved %s: DNS %s%s", scope, server.Address, route)
+	}
+	m.log(ctx).Logf("Resolved DNSSEC: %s (supported by current servers: %t)", status.DNSSEC, status.DNSSECSupported)
+	result.detail("servers", len(status.Servers))
+	result.detail("dnssec", status.DNSSEC)
+	
+	if len(status.Servers) == 0 {
+		m.log(ctx).Log("Resolved: NO DNS SERVERS - DHCP/NetworkManager configuration not received yet?")
+		return result.fail()
+	}
+	if usable == 0 {
+		m.log(ctx).Logf("Resolved: NO DEFAULT ROUTE DNS SERVERS (%d for routing domains only)", len(status.Servers))
+		return result.fail()
+	}
+	if status.CurrentServer != nil {
+		m.log(ctx).Logf("Resolved: CONFIGURED (%d default route servers, current %s)", usable, status.CurrentServer)
+	} else {
+		m.log(ctx).Logf("Resolved: CONFIGURED (%d default route servers)", usable)
+	}
+	return result.pass()
+}
+
+// checkNetworkManagerConnectivity checks NetworkManager connectivity
+func (m *Monitor) checkNetworkManagerConnectivity(ctx context.Context) *CheckResult {
+	result := newResult()
+	connectivity, err := m.connectivity.CheckNetworkManagerConnectivity(ctx)
+	if err != nil {
+		// Only a host without NetworkManager is let through; one that is
+		// installed but failed or stopped can't report connectivity
+		if status := m.networkManagerStatus(ctx); status != nil && status.ActiveState != system.ServiceActive {
+			m.log(ctx).Logf("NetworkManager connectivity: FAIL - %s is %s", networkManagerUnit, status.ActiveState)
+			result.detail("state", string(status.ActiveState))
+			return result.failWith(err)
+		}
+		m.log(ctx).Logf("NetworkManager connectivity: SERVICE NOT AVAILABLE - %v", err)
+		return result.pass() // Don't block if service unavailable
+	}
+	
+	m.log(ctx).Logf("NetworkManager connectivity: %s", connectivity)
+	result.detail("connectivity", connectivity)
+	return result.passIf(connectivity == "full")
+}
+
+// checkARPTable validates ARP table entries
+func (m *Monitor) checkARPTable(ctx context.Context) *CheckResult {
+	result := newResult()
+	m.log(ctx).Log("--- ARP Table Status ---")
+	
+	interfaces, err := m.ifaceMonitor.GetActiveInterfaces(m.config.RequiredInterfaces)
+	if err != nil {
+		m.log(ctx).Logf("ARP table: ERROR getting interfaces - %v", err)
+		return result.failWith(err)
+	}
+	
+	if len(interfaces) == 0 {
+		m.log(ctx).Log("ARP table: No interfaces to check")
+		return result.fail()
+	}
+	
+	gateway, err := m.connectivity.GetDefaultGateway()
+	if err != nil {
+		gateway = nil // Continue without gateway check
+	}
+	
+	arpStatus, err := m.arpMonitor.CheckARPTable(ctx, interfaces, gateway)
+	if err != nil {
+		m.log(ctx).Logf("ARP table: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	
+	// Log per-interface ARP counts
+	for _, iface := range interfaces {
+		count := arpStatus.InterfaceEntries[iface]
+		if gateway != nil && arpStatus.GatewayResolved && arpStatus.GatewayMAC != nil {
+			m.log(ctx).Logf("ARP table %s: %d entries (gateway %s -> %s)", 
+				iface, count, gateway, arpStatus.GatewayMAC)
+		} else {
+			m.log(ctx).Logf("ARP table %s: %d entries", iface, count)
+		}
+	}
+	
+	m.log(ctx).Logf("ARP table total: %d entries", arpStatus.TotalEntries)
+	result.detail("entries", arpStatus.TotalEntries)
+	if arpStatus.GatewayMAC != nil {
+		result.detail("gateway_mac", arpStatus.GatewayMAC)
+	}
+	
+	if arpStatus.GatewayProbed {
+		m.log(ctx).Logf("ARP table gateway: %s probed (%s timeout)", gateway, m.config.ARPProbeTimeout)
+	}
+	
+	if gateway != nil {
+		if arpStatus.GatewayResolved {
+			m.log(ctx).Logf("ARP table gateway: %s RESOLVED", gateway)
+			return result.pass()
+		} else {
+			m.log(ctx).Logf("ARP table gateway: %s NOT RESOLVED", gateway)
+			return result.fail()
+		}
+	} else {
+		if arpStatus.TotalEntries > 0 {
+			m.log(ctx).Log("ARP table: POPULATED (no gateway to check)")
+			return result.pass()
+		} else {
+			m.log(ctx).Log("ARP table: EMPTY")
+			return result.fail()
+		}
+	}
+}
+
+// checkNDPTable validates the IPv6 neighbor table: the IPv6 gateway's
+// link-layer address must be resolved, or without an IPv6 default route at
+// least one neighbor must be present
+func (m *Monitor) checkNDPTable(ctx context.Context) *CheckResult {
+	result := newResult()
+	m.log(ctx).Log("--- NDP Table Status ---")
+	
+	interfaces, err := m.ifaceMonitor.GetActiveInterfaces(m.config.RequiredInterfaces)
+	if err != nil {
+		m.log(ctx).Logf("NDP table: ERROR getting interfaces - %v", err)
+		return result.failWith(err)
+	}
+	
+	if len(interfaces) == 0 {
+		m.log(ctx).Log("NDP table: No interfaces to check")
+		return result.fail()
+	}
+	
+	gateway, err := m.connectivity.GetDefaultGateway6()
+	if err != nil {
+		gateway = nil // Continue without gateway check
+	}
+	
+	ndpStatus, err := m.arpMonitor.CheckNDPTable(ctx, interfaces, gateway)
+	if err != nil {
+		m.log(ctx).Logf("NDP table: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	
+	// Log per-interface neighbor counts
+	for _, iface := range interfaces {
+		count := ndpStatus.InterfaceEntries[iface]
+		if gateway != nil && ndpStatus.GatewayResolved && ndpStatus.GatewayMAC != nil {
+			m.log(ctx).Logf("NDP table %s: %d entries (gateway %s -> %s)",
+				iface, count, gateway, ndpStatus.GatewayMAC)
+		} else {
+			m.log(ctx).Logf("NDP table %s: %d entries", iface, count)
+		}
+	}
+	
+	m.log(ctx).Logf("NDP table total: %d entries", ndpStatus.TotalEntries)
+	result.detail("entries", ndpStatus.TotalEntries)
+	if ndpStatus.GatewayMAC != nil {
+		result.detail("gateway_mac", ndpStatus.GatewayMAC)
+	}
+	
+	if ndpStatus.GatewayProbed {
+		m.log(ctx).Logf("NDP table gateway: %s probed (%s timeout)", gateway, m.config.ARPProbeTimeout)
+	}
+	
+	if gateway != nil {
+		if ndpStatus.GatewayResolved {
+			m.log(ctx).Logf("NDP table gateway: %s RESOLVED", gateway)
+			return result.pass()
+		}
+		m.log(ctx).Logf("NDP table gateway: %s NOT RESOLVED", gateway)
+		return result.fail()
+	}
+	
+	if ndpStatus.TotalEntries > 0 {
+		m.log(ctx).Log("NDP table: POPULATED (no IPv6 gateway to check)")
+		return result.pass()
+	}
+	m.log(ctx).Log("NDP table: EMPTY")
+	return result.fail()
+}
+
+// checkRoutingTable validates routing table convergence
+func (m *Monitor) checkRoutingTable(ctx context.Context) *CheckResult {
+	result := newResult()
+	if m.config.RouteTable == network.MainRouteTable {
+		m.log(ctx).Log("--- Routing Table Status ---")
+	} else {
+		m.log(ctx).Logf("--- Routing Table Status (%s) ---", config.RouteTableName(m.config.RouteTable))
+	}
+	
+	routeStatus, err := m.routeMonitor.CheckRoutingTable()
+	if err != nil {
+		m.log(ctx).Logf("Routing table: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	
+	m.log(ctx).Logf("Routing table: %d total routes", routeStatus.TotalRoutes)
+	m.log(ctx).Logf("Routing table: %d default routes", routeStatus.DefaultRoutes)
+	m.log(ctx).Logf("Routing table: %d network routes", routeStatus.NetworkRoutes)
+	m.log(ctx).Logf("Routing table: %d host routes", routeStatus.HostRoutes)
+	result.detail("routes", routeStatus.TotalRoutes)
+	result.detail("default_routes", routeStatus.DefaultRoutes)
+	
+	if routeStatus.IPv6Err != nil {
+		m.log(ctx).Logf("Routing table IPv6: ERROR - %v", routeStatus.IPv6Err)
+	} else {
+		m.log(ctx).Logf("Routing table IPv6: %d total, %d default, %d network, %d host routes",
+			routeStatus.TotalRoutes6, routeStatus.DefaultRoutes6, routeStatus.NetworkRoutes6, routeStatus.HostRoutes6)
+		result.detail("routes6", routeStatus.TotalRoutes6)
+		result.detail("default_routes6", routeStatus.DefaultRoutes6)
+	}
+	
+	if routeStatus.HasDefaultRoute || routeStatus.HasDefaultRoute6 {
+		// Get detailed default route information
+		defaultRoutes, err := m.routeMonitor.GetDefaultRoutes()
+		if err == nil {
+			for _, route := range defaultRoutes {
+				m.log(ctx).Logf("Default route: %s", route.String())
+			}
+		}
+	}
+	
+	// IPv6-only hosts don't need an IPv4 default route
+	requireV4 := m.config.GatewayFamily != "ipv6"
+	requireV6 := m.config.IPv6DefaultRoute || m.config.GatewayFamily == "ipv6"
+	
+	ok := true
+	if requireV4 && !routeStatus.HasDefaultRoute {
+		m.log(ctx).Log("Routing table: NO DEFAULT ROUTE")
+		ok = false
+	}
+	if requireV6 && !routeStatus.HasDefaultRoute6 {
+		m.log(ctx).Log("Routing table: NO IPv6 DEFAULT ROUTE")
+		ok = false
+	}
+	if len(m.config.RequiredRoutes) > 0 {
+		missing := m.checkRequiredRoutes(ctx)
+		result.detail("required_routes_missing", len(missing))
+		if len(missing) > 0 {
+			m.log(ctx).Logf("Routing table: %d/%d REQUIRED ROUTES MISSING (%s)",
+				len(missing), len(m.config.RequiredRoutes), strings.Join(missing, "; "))
+			ok = false
+		}
+	}
+	if len(m.config.PolicyTables) > 0 && !m.checkPolicyTables(ctx, requireV4, requireV6) {
+		ok = false
+	}
+	if routeStatus.HasDefaultRoute && !m.checkGatewayOnLink(ctx) {
+		ok = false
+	}
+	if !ok {
+		return result.fail()
+	}
+	
+	m.log(ctx).Log("*** ROUTING TABLE HAS DEFAULT ROUTE ***")
+	return result.pass()
+}
+
+// checkRequiredRoutes logs each required route as present, with the route
+// that satisfies it, or MISSING, and returns the missing ones. Routes pushed
+// late by DHCP option 121 or networkd show up here while the default route
+// is already in place.
+func (m *Monitor) checkRequiredRoutes(ctx context.Context) []string {
+	routes, err := m.routeMonitor.GetAllRoutes()
+	if err != nil {
+		m.log(ctx).Logf("Required routes: ERROR - %v", err)
+		return m.config.RequiredRoutes
+	}
+	
+	var missing []string
+	for _, spec := range m.config.RequiredRoutes {
+		destination, gateway, device, err := config.ParseRequiredRoute(spec)
+		if err != nil {
+			m.log(ctx).Logf("Required route %s: ERROR - %v", spec, err)
+			missing = append(missing, spec)
+			continue
+		}
+		if route := network.FindRoute(routes, destination, gateway, device); route != nil {
+			m.log(ctx).Logf("Required route %s: present (%s)", spec, route.String())
+		} else {
+			m.log(ctx).Logf("Required route %s: MISSING", spec)
+			missing = append(missing, spec)
+		}
+	}
+	return missing
+}
+
+// checkGatewayOnLink checks that each IPv4 default gateway is on-link on its
+// egress interface; a gateway outside every connected subnet, from a DHCP or
+// static addressing mistake, can never be reached whatever the link state
+func (m *Monitor) checkGatewayOnLink(ctx context.Context) bool {
+	hops, err := m.connectivity.GetDefaultNextHops()
+	if err != nil {
+		return true // A default route without a gateway, e.g. "default dev wg0"
+	}
+	
+	ok := true
+	for _, hop := range hops {
+		reason, err := m.connectivity.OnLinkReason(hop)
+		switch {
+		case err != nil:
+			m.log(ctx).Logf("Gateway %s: ON-LINK CHECK ERROR - %v", hop.Gateway, err)
+			ok = false
+		case reason == "":
+			m.log(ctx).Logf("Gateway %s: NOT ON-LINK - outside every connected subnet of %s and not flagged onlink", hop.Gateway, hop.Interface)
+			ok = false
+		default:
+			m.log(ctx).Logf("Gateway %s: on-link on %s (%s)", hop.Gateway, hop.Interface, reason)
+		}
+	}
+	return ok
+}
+
+// checkPolicyTables logs the ip rules and checks that every policy routing
+// table is selected by a rule and holds a default route of each required
+// family; a table no rule looks up is never consulted, however complete
+func (m *Monitor) checkPolicyTables(ctx context.Context, requireV4, requireV6 bool) bool {
+	rules, err := network.ListPolicyRules()
+	if err != nil {
+		m.log(ctx).Logf("Policy routing: ERROR - %v", err)
+		return false
+	}
+	selected := make(map[int]bool)
+	for _, rule := range rules {
+		if rule.IPv6 {
+			m.log(ctx).Logf("Policy rule IPv6: %s", rule.String())
+		} else {
+			m.log(ctx).Logf("Policy rule: %s", rule.String())
+		}
+		selected[rule.Table] = true
+	}
+	
+	ok := true
+	for _, name := range m.config.PolicyTables {
+		table, err := config.ParseRouteTable(name)
+		if err != nil {
+			m.log(ctx).Logf("Policy routing: ERROR - %v", err)
+			ok = false
+			continue
+		}
+		tableName := config.RouteTableName(table)
+		if !selected[table] {
+			m.log(ctx).Logf("Routing %s: NO IP RULE selects this table", tableName)
+			ok = false
+		}
+		
+		defaultRoutes, err := m.routeMonitor.TableDefaultRoutes(table)
+		if err != nil {
+			m.log(ctx).Logf("Routing %s: ERROR - %v", tableName, err)
+			ok = false
+			continue
+		}
+		hasV4, hasV6 := false, false
+		for _, route := range defaultRoutes {
+			m.log(ctx).Logf("Routing %s: default route %s", tableName, route.String())
+			if route.IPv6 {
+				hasV6 = true
+			} else {
+				hasV4 = true
+			}
+		}
+		if requireV4 && !hasV4 {
+			m.log(ctx).Logf("Routing %s: NO DEFAULT ROUTE", tableName)
+			ok = false
+		}
+		if requireV6 && !hasV6 {
+			m.log(ctx).Logf("Routing %s: NO IPv6 DEFAULT ROUTE", tableName)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// checkTCPEndpoints checks that every configured TCP endpoint accepts a
+// connection; the endpoints are dialed concurrently
+func (m *Monitor) checkTCPEndpoints(ctx context.Context) *CheckResult {
+	result := newResult()
+	endpoints := m.config.TCPChecks
+	
+	elapsed := make([]time.Duration, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			elapsed[i], errs[i] = m.connectivity.CheckTCP(ctx, endpoint, m.config.TCPTimeout)
+		}(i, endpoint)
+	}
+	wg.Wait()
+	
+	connected := 0
+	for i, endpoint := range endpoints {
+		if errs[i] != nil {
+			m.log(ctx).Logf("TCP %s: NOT CONNECTED - %v", endpoint, errs[i])
+			continue
+		}
+		m.log(ctx).Logf("TCP %s: CONNECTED (%s)", endpoint, elapsed[i])
+		connected++
+	}
+	result.detail("connected", connected)
+	result.detail("endpoints", len(endpoints))
+	
+	if connected == len(endpoints) {
+		m.log(ctx).Logf("TCP endpoints: ALL CONNECTED (%d/%d)", connected, len(endpoints))
+		return result.pass()
+	}
+	m.log(ctx).Logf("TCP endpoints: %d NOT CONNECTED, %d connected (need all %d)", len(endpoints)-connected, connected, len(endpoints))
+	return result.fail()
+}
+
+// checkPath probes the first hops toward the path target one hop limit at a
+// time, so a broken path shows whether the top-of-rack, aggregation or WAN
+// layer stopped responding
+func (m *Monitor) checkPath(ctx context.Context) *CheckResult {
+	result := newResult()
+	target := m.config.PathTarget
+	
+	ip, err := m.connectivity.ResolveTarget(ctx, target)
+	if err != nil {
+		m.log(ctx).Logf("Path to %s: NOT RESOLVED (%s timeout) - %v", target, m.config.DNSTimeout, err)
+		return result.fail()
+	}
+	result.detail("target", ip)
+	
+	label := fmt.Sprintf("Path to %s", target)
+	if target != ip.String() {
+		label = fmt.Sprintf("Path to %s (%s)", target, ip)
+	}
+	
+	for ttl := 1; ttl <= m.config.PathHops; ttl++ {
+		hop, err := m.connectivity.TraceHop(ctx, &net.IPAddr{IP: ip}, ttl)
+		if err != nil {
+			status := "NO RESPONSE"
+			if hop != nil {
+				status = "UNREACHABLE"
+			}
+			m.log(ctx).Logf("%s: hop %d %s - %v", label, ttl, status, err)
+			m.log(ctx).Logf("%s: BROKEN at hop %d (%d/%d hops responded)", label, ttl, ttl-1, m.config.PathHops)
+			result.detail("failed_hop", ttl)
+			return result.fail()
+		}
+		m.log(ctx).Logf("%s: hop %d %s (rtt %s)", label, ttl, hop.Addr, hop.RTT)
+		result.detail(fmt.Sprintf("hop%d", ttl), hop.Addr)
+		
+		if hop.Reached {
+			m.log(ctx).Logf("%s: OK (destination reached at hop %d)", label, ttl)
+			return result.pass()
+		}
+	}
+	
+	m.log(ctx).Logf("%s: OK (first %d hops responded)", label, m.config.PathHops)
+	return result.pass()
+}
+
+// checkTLSEndpoints checks that every configured TLS endpoint presents a
+// certificate chain the system trust store verifies, valid for at least the
+// configured number of days; the endpoints are checked concurrently
+func (m *Monitor) checkTLSEndpoints(ctx context.Context) *CheckResult {
+	result := newResult()
+	endpoints := m.config.TLSChecks
+	
+	certs := make([]*network.TLSCertificate, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			certs[i], errs[i] = network.CheckTLS(ctx, config.TLSEndpoint(endpoint), m.config.TLSTimeout)
+		}(i, endpoint)
+	}
+	wg.Wait()
+	
+	valid := 0
+	minValidity := time.Duration(m.config.TLSMinDays) * 24 * time.Hour
+	for i, endpoint := range endpoints {
+		endpoint = config.TLSEndpoint(endpoint)
+		if errs[i] != nil {
+			m.log(ctx).Logf("TLS %s: INVALID - %v%s", endpoint, errs[i], tlsHint(errs[i]))
+			continue
+		}
+		
+		cert := certs[i]
+		remaining := time.Until(cert.NotAfter)
+		days := int(remaining.Hours() / 24)
+		if remaining < minValidity {
+			m.log(ctx).Logf("TLS %s: EXPIRES SOON - %s expires %s (%d days left, need %d)",
+				endpoint, cert.Subject, cert.NotAfter.Format("2006-01-02"), days, m.config.TLSMinDays)
+			continue
+		}
+		m.log(ctx).Logf("TLS %s: VALID (%s, issued by %s, expires %s in %d days, handshake %s)",
+			endpoint, cert.Subject, cert.Issuer, cert.NotAfter.Format("2006-01-02"), days, cert.Elapsed)
+		valid++
+	}
+	result.detail("valid", valid)
+	result.detail("endpoints", len(endpoints))
+	
+	if valid == len(endpoints) {
+		m.log(ctx).Logf("TLS endpoints: ALL VALID (%d/%d)", valid, len(endpoints))
+		return result.pass()
+	}
+	m.log(ctx).Logf("TLS endpoints: %d INVALID, %d valid (need all %d)", len(endpoints)-valid, valid, len(endpoints))
+	return result.fail()
+}
+
+// tlsHint points at the usual boot-time causes of a certificate verification failure
+func tlsHint(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		return " (is the CA bundle installed?)"
+	}
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+		return fmt.Sprintf(" (system clock is %s; is it set?)", time.Now().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// checkHTTPEndpoints checks that every configured URL answers with an accepted
+// status and, when required, a body containing the expected text; the URLs
+// are fetched concurrently
+func (m *Monitor) checkHTTPEndpoints(ctx context.Context) *CheckResult {
+	result := newResult()
+	checks := m.config.HTTPChecks
+	
+	errs := make([]error, len(checks))
+	responses := make([]*network.HTTPResponse, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check config.HTTPCheck) {
+			defer wg.Done()
+			responses[i], errs[i] = m.fetchHTTPCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	
+	healthy := 0
+	for i, check := range checks {
+		if errs[i] != nil {
+			m.log(ctx).Logf("HTTP %s: FAILED - %v", check.URL, errs[i])
+			continue
+		}
+		m.log(ctx).Logf("HTTP %s: OK (%d in %s)", check.URL, responses[i].Status, responses[i].Elapsed)
+		healthy++
+	}
+	result.detail("healthy", healthy)
+	result.detail("endpoints", len(checks))
+	
+	if healthy == len(checks) {
+		m.log(ctx).Logf("HTTP endpoints: ALL HEALTHY (%d/%d)", healthy, len(checks))
+		return result.pass()
+	}
+	m.log(ctx).Logf("HTTP endpoints: %d FAILED, %d healthy (need all %d)", len(checks)-healthy, healthy, len(checks))
+	return result.fail()
+}
+
+// checkCaptivePortal fetches the captive portal URL, which answers 204 when
+// the internet is reachable directly. A redirect or any other answer means
+// something on the path intercepted the request, usually a captive portal
+// waiting for a login.
+func (m *Monitor) checkCaptivePortal(ctx context.Context) *CheckResult {
+	result := newResult()
+	portalURL := m.config.CaptivePortalURL
+	
+	resp, err := network.FetchURL(ctx, portalURL, network.FetchOptions{Timeout: m.config.HTTPTimeout, NoRedirect: true})
+	if err != nil {
+		m.log(ctx).Logf("Captive portal: UNKNOWN - %s not reachable: %v", portalURL, err)
+		result.detail("state", "unreachable")
+		return result.failWith(err)
+	}
+	result.detail("status", resp.Status)
+	
+	switch {
+	case resp.Status == http.StatusNoContent:
+		m.log(ctx).Logf("Captive portal: NONE (204 in %s)", resp.Elapsed)
+		result.detail("state", "none")
+		return result.pass()
+	case resp.Location != "":
+		m.log(ctx).Logf("Captive portal: PORTAL - %s redirected to %s (%d)", portalURL, resp.Location, resp.Status)
+		result.detail("location", resp.Location)
+	default:
+		m.log(ctx).Logf("Captive portal: PORTAL - %s intercepted (%d with %d byte body, expected 204)", portalURL, resp.Status, len(resp.Body))
+	}
+	result.detail("state", "portal")
+	return result.fail()
+}
+
+// checkProxy fetches the proxy test URL through the proxy. Through an HTTP
+// proxy an https URL tests that the proxy accepts CONNECT and an http URL that
+// it forwards a GET; a SOCKS5 proxy tunnels either.
+func (m *Monitor) checkProxy(ctx context.Context) *CheckResult {
+	result := newResult()
+	testURL := m.config.ProxyTestURL
+	
+	proxy, err := m.proxy()
+	if err != nil {
+		m.log(ctx).Logf("Proxy: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	result.detail("proxy", proxy.Redacted())
+	
+	request := proxyRequest(proxy, testURL)
+	
+	resp, err := network.FetchURL(ctx, testURL, network.FetchOptions{Timeout: m.config.HTTPTimeout, Proxy: proxy})
+	if err != nil {
+		m.log(ctx).Logf("Proxy %s: FAILED (%s) - %v", proxy.Redacted(), request, err)
+		return result.failWith(err)
+	}
+	result.detail("status", resp.Status)
+	
+	low, high, err := config.ParseStatusRange(m.config.HTTPStatus)
+	if err != nil {
+		return result.failWith(err)
+	}
+	if resp.Status < low || resp.Status > high {
+		m.log(ctx).Logf("Proxy %s: FAILED (%s) - status %d not in %s", proxy.Redacted(), request, resp.Status, m.config.HTTPStatus)
+		return result.fail()
+	}
+	
+	m.log(ctx).Logf("Proxy %s: OK (%s, %d in %s)", proxy.Redacted(), request, resp.Status, resp.Elapsed)
+	return result.pass()
+}
+
+// proxy returns the configured proxy, falling back to the one the
+// environment selects for the proxy test URL
+func (m *Monitor) proxy() (*url.URL, error) {
+	if m.config.ProxyURL != "" {
+		return url.Parse(m.config.ProxyURL)
+	}
+	
+	proxy, err := network.EnvironmentProxy(m.config.ProxyTestURL)
+	if err != nil {
+		return nil, err
+	}
+	if proxy == nil {
+		return nil, errors.New("no proxy configured (set proxy_url, or http_proxy/https_proxy in the service environment)")
+	}
+	return proxy, nil
+}
+
+// proxyRequest describes what the proxy is asked to do to fetch testURL, as
+// logged by the proxy check
+func proxyRequest(proxy *url.URL, testURL string) string {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		return "GET " + testURL
+	}
+	
+	switch strings.ToLower(proxy.Scheme) {
+	case "socks5", "socks5h":
+		return strings.ToUpper(proxy.Scheme) + " CONNECT " + canonicalHostPort(u)
+	}
+	if u.Scheme == "https" {
+		return "CONNECT " + canonicalHostPort(u)
+	}
+	return "GET " + testURL
+}
+
+// canonicalHostPort returns the host:port a request for u connects to
+func canonicalHostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+// gatewayDegraded reports whether the last gateway check failed only because
+// the gateway answered more slowly than allowed
+func (m *Monitor) gatewayDegraded() bool {
+	result, ok := m.results["gateway"]
+	return ok && !result.Passed && result.Details["degraded"] == "true"
+}
+
+// dnsDegraded reports whether the last DNS check failed only because the
+// resolver answered more slowly than allowed
+func (m *Monitor) dnsDegraded() bool {
+	result, ok := m.results["dns"]
+	return ok && !result.Passed && result.Details["degraded"] == "true"
+}
+
+// portalDetected reports whether the last captive portal check found a portal
+// rather than failing to reach the URL at all
+func (m *Monitor) portalDetected() bool {
+	result, ok := m.results["captive_portal"]
+	return ok && result.Details["state"] == "portal"
+}
+
+// fetchHTTPCheck fetches one URL and checks the response against the
+// accepted status range and expected body text
+func (m *Monitor) fetchHTTPCheck(ctx context.Context, check config.HTTPCheck) (*network.HTTPResponse, error) {
+	status, timeout := m.httpCheckSettings(check)
+	low, high, err := config.ParseStatusRange(status)
+	if err != nil {
+		return nil, err
+	}
+	
+	opts := network.FetchOptions{Timeout: timeout, Insecure: check.Insecure}
+	if m.config.HTTPViaProxy {
+		if opts.Proxy, err = url.Parse(m.config.ProxyURL); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := network.FetchURL(ctx, check.URL, opts)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status < low || resp.Status > high {
+		return resp, fmt.Errorf("status %d not in %s", resp.Status, status)
+	}
+	if check.Body != "" && !strings.Contains(string(resp.Body), check.Body) {
+		return resp, fmt.Errorf("body does not contain %q", check.Body)
+	}
+	return resp, nil
+}
+
+// httpCheckSettings returns the accepted status range and timeout of a URL,
+// falling back to the global settings
+func (m *Monitor) httpCheckSettings(check config.HTTPCheck) (status string, timeout time.Duration) {
+	status, timeout = check.Status, check.Timeout
+	if status == "" {
+		status = m.config.HTTPStatus
+	}
+	if timeout == 0 {
+		timeout = m.config.HTTPTimeout
+	}
+	return status, timeout
+}
+
+// describeHTTPCheck summarizes the settings of a URL for the dry run
+func (m *Monitor) describeHTTPCheck(check config.HTTPCheck) string {
+	status, timeout := m.httpCheckSettings(check)
+	parts := []string{"status " + status, timeout.String() + " timeout"}
+	if check.Body != "" {
+		parts = append(parts, fmt.Sprintf("body contains %q", check.Body))
+	}
+	if check.Insecure {
+		parts = append(parts, "TLS not verified")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkDHCPLeases validates that monitored interfaces using DHCP hold an unexpired lease
+func (m *Monitor) checkDHCPLeases(ctx context.Context) *CheckResult {
+	result := newResult()
+	m.log(ctx).Log("--- DHCP Lease Status ---")
+	
+	interfaces, err := m.ifaceMonitor.GetActiveInterfaces(m.config.RequiredInterfaces)
+	if err != nil {
+		m.log(ctx).Logf("DHCP leases: ERROR getting interfaces - %v", err)
+		return result.failWith(err)
+	}
+	
+	allValid := true
+	leaseCount := 0
+	
+	for _, iface := range interfaces {
+		lease, err := m.dhcpMonitor.CheckLease(iface)
+		if err != nil {
+			m.log(ctx).Logf("DHCP lease %s: ERROR - %v", iface, err)
+			allValid = false
+			continue
+		}
+		
+		if lease == nil {
+			m.log(ctx).Logf("DHCP lease %s: no lease file - skipping", iface)
+			continue
+		}
+		
+		leaseCount++
+		if lease.Permanent() {
+			m.log(ctx).Logf("DHCP lease %s: %s VALID (%s, no expiry)", iface, lease.Address, lease.Source)
+		} else if lease.Expired() {
+			m.log(ctx).Logf("DHCP lease %s: %s EXPIRED (%s, expired %s ago)",
+				iface, lease.Address, lease.Source, (-lease.Remaining()).Round(time.Second))
+			allValid = false
+		} else {
+			m.log(ctx).Logf("DHCP lease %s: %s VALID (%s, %s remaining)",
+				iface, lease.Address, lease.Source, lease.Remaining().Round(time.Second))
+		}
+	}
+	
+	if allValid {
+		m.log(ctx).Logf("DHCP leases: ALL VALID (%d leases)", leaseCount)
+	} else {
+		m.log(ctx).Log("DHCP leases: NOT VALID")
+	}
+	
+	result.detail("leases", leaseCount)
+	return result.passIf(allValid)
+}
+
+// checkTimeSync checks that the system clock is NTP synchronized
+func (m *Monitor) checkTimeSync(ctx context.Context) *CheckResult {
+	result := newResult()
+	synchronized, err := m.timeSync.CheckSynchronized(ctx)
+	if errors.Is(err, system.ErrTimeSyncUnavailable) {
+		m.log(ctx).Logf("Time sync: NOT AVAILABLE - skipping (%v)", err)
+		return result.pass() // Don't block if no time daemon is present
+	}
+	if err != nil {
+		m.log(ctx).Logf("Time sync: ERROR - %v", err)
+		return result.failWith(err)
+	}
+	
+	if synchronized {
+		m.log(ctx).Log("Time sync: SYNCHRONIZED")
+	} else {
+		m.log(ctx).Log("Time sync: NOT SYNCHRONIZED")
+	}
+	return result.passIf(synchronized)
+}
+
+// execCheckList returns the external check commands to run this cycle, sharing
+// an environment that describes the current network state
+func (m *Monitor) execCheckList() []namedCheck {
+	if len(m.execChecks) == 0 {
+		return nil
+	}
+	
+	// Give the commands some context about the current network state
+	env := map[string]string{
+		"NETWORK_MONITOR_ROUTE_TABLE": config.RouteTableName(m.config.RouteTable),
+	}
+	if gateway, err := m.connectivity.GetDefaultGateway(); err == nil {
+		env["NETWORK_MONITOR_GATEWAY"] = gateway.String()
+	}
+	if gateway, err := m.connectivity.GetDefaultGateway6(); err == nil {
+		env["NETWORK_MONITOR_GATEWAY6"] = gateway.String()
+	}
+	if interfaces, err := m.ifaceMonitor.GetActiveInterfaces(m.config.RequiredInterfaces); err == nil {
+		env["NETWORK_MONITOR_INTERFACES"] = strings.Join(interfaces, " ")
+	}
+	
+	var checks []namedCheck
+	for _, check := range m.execChecks {
+		check := check
+		checks = append(checks, namedCheck{check.Name, func(ctx context.Context) *CheckResult {
+			return m.runExecCheck(ctx, check, env)
+		}})
+	}
+	
+	return checks
+}
+
+// runExecCheck runs a single external check command and logs its output
+func (m *Monitor) runExecCheck(ctx context.Context, check *system.ExecCheck, env map[string]string) *CheckResult {
+	result := newResult()
+	execResult, err := check.Run(ctx, env)
+	if execResult != nil && execResult.Output != "" {
+		for _, line := range strings.Split(execResult.Output, "\n") {
+			m.log(ctx).Logf("Check %s: %s", check.Name, line)
+		}
+	}
+	
+	if err != nil {
+		m.log(ctx).Logf("Check %s: ERROR - %v", check.Name, err)
+		return result.failWith(err)
+	}
+	
+	result.detail("exit_status", execResult.ExitCode)
+	if execResult.Passed {
+		m.log(ctx).Logf("Check %s: PASSED", check.Name)
+	} else {
+		m.log(ctx).Logf("Check %s: FAILED (exit status %d)", check.Name, execResult.ExitCode)
+	}
+	return result.passIf(execResult.Passed)
+}
+
+// logExecSummary logs the latest external check results in configuration order
+func (m *Monitor) logExecSummary() {
+	if len(m.execChecks) == 0 {
+		return
+	}
+	
+	var summary strings.Builder
+	summary.WriteString("External checks:")
+	for _, check := range m.execChecks {
+		if passed(m.results, check.Name) {
+			summary.WriteString(" " + check.Name + "=PASS")
+		} else {
+			summary.WriteString(" " + check.Name + "=FAIL")
+		}
+	}
+	m.logger.Log(summary.String())
+}
+
+// updateStates applies the latest results of the checks that just ran to
+// their states and logs the transitions
+func (m *Monitor) updateStates(checks []namedCheck) {
+	for _, c := range checks {
+		m.updateState(c.name, passed(m.results, c.name))
+	}
+}
+
+// updateState applies the consecutive-result thresholds to a single check and
+// logs the transition once the new result has been seen often enough
+func (m *Monitor) updateState(name string, current bool) {
+	if m.metrics != nil {
+		defer func() { m.metrics.SetState(name, m.states[name]) }()
+	}
+	
+	if current == m.states[name] {
+		m.pendingCounts[name] = 0
+		return
+	}
+	
+	// Going down and coming back up can require different run lengths
+	fail, recover := m.config.Thresholds(name)
+	threshold, direction := fail, "DOWN"
+	if current {
+		threshold, direction = recover, "UP"
+	}
+	
+	m.pendingCounts[name]++
+	if m.pendingCounts[name] < threshold {
+		m.logger.Logf("State %s: %s pending (%d/%d consecutive results)",
+			name, direction, m.pendingCounts[name], threshold)
+		return
+	}
+	
+	m.pendingCounts[name] = 0
+	m.states[name] = current
+	m.recordTransition(name, current)
+	if m.metrics != nil {
+		m.metrics.RecordTransition(name)
+	}
+	up, down := transitionMessages(name)
+	if current {
+		m.logger.Log(up)
+	} else {
+		m.logger.Log(down)
+	}
+}
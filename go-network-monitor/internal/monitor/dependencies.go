@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"context"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/system"
+)
+
+// networkManagerUnit is the service the NetworkManager connectivity check depends on
+const networkManagerUnit = "NetworkManager.service"
+
+// resolvedUnit is the service whose stub listener resolv.conf may point at
+const resolvedUnit = "systemd-resolved.service"
+
+// dependency is a precondition a check needs before running it is worthwhile
+type dependency struct {
+	name          string                     // Shown as "blocked by <name>"
+	met           func(context.Context) bool
+	passIfBlocked bool                       // Result recorded while blocked
+}
+
+// checkDependencies maps check names to their preconditions. Checks whose
+// precondition isn't met are skipped rather than left to time out, which
+// shortens cycles during early boot.
+func (m *Monitor) checkDependencies() map[string]dependency {
+	carrier := dependency{name: "carrier", met: m.anyCarrier}
+	
+	return map[string]dependency{
+		"gateway":        carrier,
+		"dns":            carrier,
+		"tcp":            carrier,
+		"http":           carrier,
+		"captive_portal": carrier,
+		"proxy":          carrier,
+		"tls":            carrier,
+		"path":           carrier,
+		"encrypted_dns":  carrier,
+		"reverse_dns":    carrier,
+		"dnssec":         carrier,
+		"resolv_conf":    carrier,
+		// An installed NetworkManager that has failed or stopped can't report
+		// connectivity, so the check fails; hosts without it aren't blocked
+		"nm_connectivity": {name: networkManagerUnit, met: m.networkManagerActive},
+	}
+}
+
+// anyCarrier reports whether at least one monitored interface has carrier
+func (m *Monitor) anyCarrier(ctx context.Context) bool {
+	interfaces, err := m.ifaceMonitor.GetActiveInterfaces(m.config.RequiredInterfaces)
+	if err != nil {
+		return true // Let the dependent checks report the real problem
+	}
+	
+	for _, iface := range interfaces {
+		status, err := m.ifaceMonitor.CheckInterfaceStatus(iface)
+		if err == nil && status.Carrier {
+			return true
+		}
+	}
+	return false
+}
+
+// networkManagerActive reports whether NetworkManager is running, or isn't
+// installed so there is nothing to wait for
+func (m *Monitor) networkManagerActive(ctx context.Context) bool {
+	status := m.networkManagerStatus(ctx)
+	return status == nil || status.ActiveState == system.ServiceActive || status.ActiveState == system.ServiceActivating
+}
+
+// networkManagerStatus returns the state of the NetworkManager unit, or nil
+// when it isn't installed or its state can't be read
+func (m *Monitor) networkManagerStatus(ctx context.Context) *system.ServiceStatus {
+	if m.systemd == nil {
+		return nil // Can't tell; let the check find out itself
+	}
+	
+	status, err := m.systemd.CheckServiceStatus(ctx, networkManagerUnit)
+	if err != nil || !status.Available {
+		return nil
+	}
+	switch status.LoadState {
+	case "not-found", "masked":
+		return nil
+	}
+	return status
+}
+
+// blockedBy evaluates the dependencies of the checks about to run, each
+// precondition once per cycle, and returns the unmet dependency per check
+func (m *Monitor) blockedBy(ctx context.Context, checks []namedCheck) map[string]dependency {
+	dependencies := m.checkDependencies()
+	met := make(map[string]bool)
+	blocked := make(map[string]dependency)
+	
+	for _, c := range checks {
+		dep, ok := dependencies[c.name]
+		if !ok {
+			continue
+		}
+		result, evaluated := met[dep.name]
+		if !evaluated {
+			result = dep.met(ctx)
+			met[dep.name] = result
+		}
+		if !result {
+			blocked[c.name] = dep
+		}
+	}
+	
+	return blocked
+}
+
+// resolvedState reports whether systemd-resolved is running according to its
+// unit state, and that state; known is false when the state can't be read
+func (m *Monitor) resolvedState(ctx context.Context) (running bool, state string, known bool) {
+	if m.systemd == nil {
+		return false, "", false
+	}
+	
+	status, err := m.systemd.CheckServiceStatus(ctx, resolvedUnit)
+	if err != nil || !status.Available {
+		return false, "", false
+	}
+	if status.LoadState == "not-found" {
+		return false, "not installed", true
+	}
+	return status.ActiveState == system.ServiceActive, string(status.ActiveState), true
+}
@@ -1,19 +1,70 @@
 package monitor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	
 	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/config"
 	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/logger"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/metrics"
 	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/network"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/notify"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/readiness"
 	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/system"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/version"
 )
 
+// eventSettleTime is how long event-driven mode waits for related netlink
+// events to arrive before re-running the checks
+const eventSettleTime = 100 * time.Millisecond
+
+// Process exit codes, so unit files and scripts can act on the outcome
+const (
+	ExitReady       = 0   // Network became ready (or run-after-success completed)
+	ExitFailure     = 1   // Unexpected runtime error
+	ExitTimeout     = 2   // Total timeout reached without the network being ready
+	ExitLocked      = 3   // Another instance holds the lock file
+	ExitConfigError = 4   // Configuration failed to load or validate
+	ExitSignal      = 130 // Stopped by SIGTERM/SIGINT
+)
+
+var (
+	// ErrTimeout is returned by Run when the total timeout expires before the network is ready
+	ErrTimeout = errors.New("total timeout reached without network ready")
+	
+	// ErrLocked is returned by Run when another instance holds the lock file
+	ErrLocked = errors.New("network monitor already running")
+	
+	// ErrInterrupted is returned by Run when it was stopped by a signal
+	ErrInterrupted = errors.New("interrupted by signal")
+)
+
+// ExitCode maps the result of Run to a process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitReady
+	case errors.Is(err, ErrTimeout):
+		return ExitTimeout
+	case errors.Is(err, ErrLocked):
+		return ExitLocked
+	case errors.Is(err, ErrInterrupted):
+		return ExitSignal
+	default:
+		return ExitFailure
+	}
+}
+
 // Monitor represents the main network monitoring service
 type Monitor struct {
 	config      *config.Config
@@ -22,53 +73,140 @@ type Monitor struct {
 	connectivity *network.ConnectivityChecker
 	arpMonitor   *network.ARPMonitor
 	routeMonitor *network.RoutingMonitor
-	systemd      *system.SystemdMonitor
+	dhcpMonitor  *network.DHCPMonitor
+	timeSync     *system.TimeSyncMonitor
+	resolved     *system.ResolvedMonitor
+	wireless     *system.WirelessMonitor
+	systemd      system.ServiceMonitor
+	execChecks   []*system.ExecCheck
+	webhook      *notify.Webhook
+	sdNotifier   *system.Notifier
+	hooks        *notify.Hooks
+	readyExpr    *readiness.Expression  // Replaces the default readiness rule when set
+	metrics      *metrics.Metrics
 	lockFile     *os.File
 	
+	// Cancelled on SIGTERM/SIGINT; checks, systemd queries, commands, webhook
+	// deliveries and hooks in flight all derive from it and abort at once
+	shutdownCtx context.Context
+	shutdown    context.CancelFunc
+	
 	// State tracking
-	allInterfacesUp    bool
-	gatewayReachable   bool
-	servicesReady      bool
-	dnsWorking         bool
-	nmConnectivityFull bool
-	arpTableValid      bool
-	routingTableValid  bool
+	states             map[string]bool  // Current state of each enabled check, changed once a result clears its threshold
+	enabledServices    []string         // Network services found enabled at startup
+	interfaceNames     map[int]string   // Last name seen for each monitored ifindex, to spot hotplug and udev renames
+	interfacesSeen     bool             // The first interface check has run; later interfaces are hotplugged
+	activeSlaves       map[string]string  // Last active slave of each bond, to spot failovers
+	failovers          map[string]int     // Failovers seen per bond
+	lacpChurned        map[string]bool    // Keyed by "<bond>/<slave>", slaves last seen in LACP churn
+	
+	// Consecutive results disagreeing with the current state, per check
+	pendingCounts map[string]int
+	
+	// Most recent result of each check, and each check's history over the run
+	results  map[string]*CheckResult
+	timeline map[string]*checkTimeline
 	
 	networkCompleteTime time.Time
+	firstReadyTime     time.Time  // Unlike networkCompleteTime, not reset when readiness is lost
+	stateChanged       bool       // A check changed state since the state file was written
 	startTime          time.Time
 }
 
 // New creates a new monitor instance
 func New(cfg *config.Config) (*Monitor, error) {
-	// Create logger
-	log, err := logger.New(cfg.LogFile)
+	// Create logger; one-shot checks never touch the on-disk log
+	logPath := cfg.LogFile
+	if cfg.Once || cfg.DryRun || cfg.NoLogFile {
+		logPath = ""
+	}
+	log, err := logger.New(logPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logger: %w", err)
 	}
 	
-	// Create systemd monitor
-	systemdMonitor, err := system.NewSystemdMonitor()
+	// Keep stdout clean for the JSON document
+	if cfg.Console != nil {
+		log.SetConsole(cfg.Console)
+	} else if cfg.Output == "json" {
+		log.SetConsole(os.Stderr)
+	}
+	
+	// Create systemd monitor, falling back to systemctl if D-Bus is unavailable
+	systemdMonitor, err := system.NewServiceMonitor(cfg.SystemdTimeout)
 	if err != nil {
-		log.Log("Warning: Failed to connect to systemd, service monitoring disabled")
+		log.Logf("Warning: Failed to connect to systemd, service monitoring disabled: %v", err)
 		systemdMonitor = nil
+	} else {
+		log.Logf("Service monitoring backend: %s", systemdMonitor.Backend())
+	}
+	
+	var arpProbeTimeout time.Duration
+	if cfg.ARPProbe {
+		arpProbeTimeout = cfg.ARPProbeTimeout
 	}
 	
 	monitor := &Monitor{
-		config:       cfg,
-		logger:       log,
-		ifaceMonitor: network.NewInterfaceMonitor(cfg.InterfaceTypes),
-		connectivity: network.NewConnectivityChecker(cfg.PingTimeout, cfg.DNSTimeout),
-		arpMonitor:   network.NewARPMonitor(),
-		routeMonitor: network.NewRoutingMonitor(),
-		systemd:      systemdMonitor,
-		startTime:    time.Now(),
+		config:         cfg,
+		logger:         log,
+		ifaceMonitor:   network.NewInterfaceMonitor(cfg.InterfaceTypes, cfg.ExcludedInterfaces, cfg.IgnoreSRIOVVFs),
+		connectivity:   network.NewConnectivityChecker(cfg.PingTimeout, cfg.DNSTimeout, cfg.NMTimeout, cfg.RouteTable),
+		arpMonitor:     network.NewARPMonitor(arpProbeTimeout),
+		routeMonitor:   network.NewRoutingMonitor(cfg.RouteTable),
+		dhcpMonitor:    network.NewDHCPMonitor(),
+		timeSync:       system.NewTimeSyncMonitor(cfg.SystemdTimeout),
+		resolved:       system.NewResolvedMonitor(cfg.SystemdTimeout),
+		wireless:       system.NewWirelessMonitor(cfg.SystemdTimeout),
+		systemd:        systemdMonitor,
+		states:         make(map[string]bool),
+		interfaceNames: make(map[int]string),
+		activeSlaves:   make(map[string]string),
+		failovers:      make(map[string]int),
+		lacpChurned:    make(map[string]bool),
+		pendingCounts:  make(map[string]int),
+		results:        make(map[string]*CheckResult),
+		timeline:       make(map[string]*checkTimeline),
+		startTime:      time.Now(),
+	}
+	monitor.shutdownCtx, monitor.shutdown = context.WithCancel(context.Background())
+	
+	execNames := cfg.ExecCheckNames()
+	for i, command := range cfg.ExecChecks {
+		check, err := system.NewExecCheck(command, cfg.ExecCheckTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exec check %q: %w", command, err)
+		}
+		check.Name = execNames[i]
+		monitor.execChecks = append(monitor.execChecks, check)
+	}
+	
+	if cfg.ReadinessExpr != "" {
+		expr, err := readiness.Parse(cfg.ReadinessExpr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid readiness expression: %w", err)
+		}
+		monitor.readyExpr = expr
+	}
+	
+	if cfg.MetricsListen != "" && !cfg.Once {
+		monitor.metrics = metrics.New(cfg.MetricsListen)
+	}
+	
+	monitor.sdNotifier = system.NewNotifier()
+	
+	if cfg.WebhookURL != "" && !cfg.Once {
+		monitor.webhook = notify.NewWebhook(cfg.WebhookURL, cfg.WebhookTimeout, log)
+	}
+	
+	if (cfg.OnReady != "" || cfg.OnDegraded != "" || cfg.OnTimeout != "") && !cfg.Once {
+		monitor.hooks = notify.NewHooks(cfg.OnReady, cfg.OnDegraded, cfg.OnTimeout, cfg.HookTimeout, log)
 	}
 	
 	return monitor, nil
 }
 
 // Run starts the monitoring loop
-func (m *Monitor) Run() error {
+func (m *Monitor) Run() (err error) {
 	// Acquire lock file
 	if err := m.acquireLock(); err != nil {
 		return err
@@ -82,185 +220,865 @@ func (m *Monitor) Run() error {
 	}
 	
 	m.logger.Banner(
+		version.Get().String(),
 		os.Getpid(),
 		mode,
 		m.config.TotalTimeout,
 		m.config.RunAfterSuccess,
 		m.config.SleepInterval,
 		m.config.InterfaceTypes,
-		m.config.ResolverHostname,
+		strings.Join(m.config.ResolverHostnames, " "),
 		m.config.PingTimeout,
 		m.config.DNSTimeout,
 	)
 	
-	// Set up signal handling
+	// Summarize the run however it ends
+	defer m.logTimeline()
+	defer func() { m.saveState(outcome(err)) }()
+	m.saveState("running")
+	
+	// Record where the configuration came from
+	if m.config.ConfigFile != "" {
+		m.logger.Logf("Config file: %s", m.config.ConfigFile)
+	}
+	for _, fragment := range m.config.ConfigFragments {
+		m.logger.Logf("Config drop-in: %s", fragment)
+	}
+	if m.config.Profile != "" {
+		m.logger.Logf("Config profile: %s", m.config.Profile)
+	}
+	
+	// Warn if a slow cycle could take longer than the check interval
+	if worstCase := m.config.WorstCaseCycleTime(); worstCase > m.config.SleepInterval {
+		m.logger.Logf("Warning: Sleep interval %s is shorter than the worst-case check cycle %s - cycles may run back to back",
+			m.config.SleepInterval, worstCase)
+	}
+	
+	// Report readiness to systemd for Type=notify units
+	if m.sdNotifier.Enabled() {
+		m.logger.Log("systemd notification socket found - will send READY=1 once the network is ready")
+		defer m.sdNotifier.Stopping()
+	}
+	
+	// Serve metrics for the lifetime of the main loop
+	if m.metrics != nil {
+		m.metrics.Start(m.logger)
+		defer m.metrics.Close()
+	}
+	
+	// Set up signal handling; a signal cancels the root context so in-flight
+	// checks abort promptly instead of running into their own timeouts
+	ctx, cancel := context.WithCancel(m.shutdownCtx)
+	defer cancel()
+	
+	runDone := make(chan struct{})
+	defer close(runDone)
+	
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigChan)
+	go m.handleSignals(sigChan, runDone)
+	
+	// SIGHUP reloads the safe-to-change configuration options
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	defer signal.Stop(hupChan)
+	
+	// SIGUSR1 dumps the current state to the log and a JSON file
+	usr1Chan := make(chan os.Signal, 1)
+	signal.Notify(usr1Chan, syscall.SIGUSR1)
+	defer signal.Stop(usr1Chan)
+	
+	// SIGUSR2 forces an immediate check cycle, e.g. from a udev rule or NM
+	// dispatcher script after a cable or service has been fixed
+	usr2Chan := make(chan os.Signal, 1)
+	signal.Notify(usr2Chan, syscall.SIGUSR2)
+	defer signal.Stop(usr2Chan)
 	
 	// Get enabled services at startup
-	var enabledServices []string
-	if m.systemd != nil {
-		services, err := m.systemd.GetEnabledServices(m.config.NetworkServices)
+	m.discoverServices(ctx)
+	
+	m.logger.Logf("Network monitor starting (%s mode - timeout: %s)", mode, m.config.TotalTimeout)
+	
+	// Subscribe to netlink events in watch and event-driven modes; the ticker
+	// remains as a backstop
+	var watcher *network.LinkWatcher
+	var linkEvents <-chan string
+	if m.config.Watch || m.config.EventDriven {
+		w, err := network.NewLinkWatcher()
 		if err != nil {
-			m.logger.Logf("Warning: Failed to get enabled services: %v", err)
+			m.logger.Logf("Warning: Failed to subscribe to netlink events, falling back to polling every %s: %v", m.config.SleepInterval, err)
 		} else {
-			enabledServices = services
-			for _, service := range services {
-				m.logger.Logf("Service %s: found and enabled - will monitor", service)
+			watcher = w
+			defer watcher.Close()
+			linkEvents = watcher.Events()
+			if m.config.EventDriven {
+				m.logger.Logf("Event-driven mode: subscribed to netlink link, address, route and neighbor events (backstop poll every %s)", m.config.EventBackstop)
+			} else {
+				m.logger.Log("Watch mode: subscribed to netlink link, address, route and neighbor events")
 			}
 		}
 	}
+	eventDriven := m.config.EventDriven && watcher != nil
 	
-	if len(enabledServices) == 0 {
-		m.logger.Log("Network services: NONE FOUND")
+	// Start monitoring loop; event-driven mode runs the first cycle right away
+	// and then only polls at the slower backstop interval
+	pollInterval := m.config.SleepInterval
+	var firstCycle <-chan time.Time
+	if eventDriven {
+		pollInterval = m.config.EventBackstop
+		firstCycle = time.After(0)
 	}
-	
-	m.logger.Logf("Network monitor starting (%s mode - timeout: %s)", mode, m.config.TotalTimeout)
-	
-	// Start monitoring loop
-	ticker := time.NewTicker(m.config.SleepInterval)
+	ticker := time.NewTicker(m.jitter(pollInterval))
 	defer ticker.Stop()
+	if m.config.SleepJitter > 0 {
+		m.logger.Logf("Check interval jitter: +/-%d%%", m.config.SleepJitter)
+	}
 	
 	totalTimeout := time.NewTimer(m.config.TotalTimeout)
 	defer totalTimeout.Stop()
 	
+	// Keep the systemd watchdog fed from the main loop, so a hung check
+	// gets the service restarted rather than leaving it silently stuck
+	var watchdogTick <-chan time.Time
+	if interval := m.sdNotifier.WatchdogInterval(); interval > 0 {
+		watchdog := time.NewTicker(interval / 2)
+		defer watchdog.Stop()
+		watchdogTick = watchdog.C
+		m.logger.Logf("systemd watchdog enabled (%s) - sending keep-alives every %s", interval, interval/2)
+		if worstCase := m.config.WorstCaseCycleTime(); worstCase > interval/2 {
+			m.logger.Logf("Warning: Worst-case check cycle %s exceeds the watchdog keep-alive interval %s - slow cycles may trigger a restart",
+				worstCase, interval/2)
+		}
+	}
+	
 	for {
 		select {
-		case <-sigChan:
-			m.logger.Log("Received signal, shutting down")
-			return nil
+		case <-ctx.Done():
+			return ErrInterrupted
+			
+		case <-hupChan:
+			previousInterval := m.config.SleepInterval
+			m.reloadConfig()
+			if m.config.SleepInterval != previousInterval && !eventDriven {
+				pollInterval = m.config.SleepInterval
+				ticker.Reset(m.jitter(pollInterval))
+			}
+			
+		case <-usr1Chan:
+			m.dumpState()
+			
+		case <-usr2Chan:
+			m.logger.Log("SIGUSR2 received - running checks now")
+			ticker.Reset(m.jitter(pollInterval))
+			if done, err := m.runCycle(ctx); done {
+				return err
+			}
+			
+		case <-watchdogTick:
+			if err := m.sdNotifier.Watchdog(); err != nil {
+				m.logger.Logf("Warning: Failed to notify systemd watchdog: %v", err)
+			}
 			
 		case <-totalTimeout.C:
 			m.logger.Logf("*** TOTAL TIMEOUT REACHED (%s) - EXITING ***", m.config.TotalTimeout)
+			if m.networkCompleteTime.IsZero() {
+				m.notify(notify.EventTimeout)
+				return ErrTimeout
+			}
 			return nil
 			
+		case <-firstCycle:
+			if done, err := m.runCycle(ctx); done {
+				return err
+			}
+			
 		case <-ticker.C:
-			if err := m.performChecks(enabledServices); err != nil {
-				m.logger.Logf("Error during checks: %v", err)
+			ticker.Reset(m.jitter(pollInterval))
+			if done, err := m.runCycle(ctx); done {
+				return err
+			}
+			
+		case event := <-linkEvents:
+			if eventDriven {
+				// Let a burst of related events (carrier, address, route) settle into one cycle
+				m.logger.Logf("Netlink event: %s - re-running checks", event)
+				select {
+				case <-ctx.Done():
+					return ErrInterrupted
+				case <-time.After(eventSettleTime):
+				}
+				watcher.Drain()
+				
+				done, err := m.runCycle(ctx)
+				watcher.Drain()
+				if done {
+					return err
+				}
 				continue
 			}
 			
-			// Check if we should exit
+			m.logger.Logf("Netlink event: %s - re-running link checks", event)
+			if err := m.performLinkChecks(ctx); err != nil {
+				if ctx.Err() != nil {
+					return ErrInterrupted
+				}
+				m.logger.Logf("Error during checks: %v", err)
+			}
+			
+			// Discard events generated by the checks themselves (ARP, ping)
+			watcher.Drain()
+			
+			m.reportStatus()
 			if m.shouldExit() {
 				return nil
 			}
+			if m.stateChanged {
+				m.saveState("running")
+			}
 		}
 	}
 }
 
-// performChecks performs all network status checks
-func (m *Monitor) performChecks(enabledServices []string) error {
-	m.logger.Log("=== Network Status Check ===")
+// runCycle runs a full check cycle and reports whether the main loop should
+// return, along with the error to return
+func (m *Monitor) runCycle(ctx context.Context) (bool, error) {
+	if err := m.performChecks(ctx); err != nil {
+		if ctx.Err() != nil {
+			return true, ErrInterrupted
+		}
+		m.logger.Logf("Error during checks: %v", err)
+		return false, nil
+	}
+	
+	m.reportStatus()
 	
-	// Check services
-	currentServicesReady := m.checkNetworkServices(enabledServices)
+	// Check if we should exit
+	done := m.shouldExit()
+	if m.stateChanged {
+		m.saveState("running")
+	}
+	return done, nil
+}
+
+// handleSignals cancels the root context on SIGTERM/SIGINT and forces an exit
+// if the process hasn't exited within the shutdown grace period, which covers
+// the main loop returning as well as Close waiting on webhooks and hooks
+func (m *Monitor) handleSignals(sigChan <-chan os.Signal, runDone <-chan struct{}) {
+	select {
+	case sig := <-sigChan:
+		m.logger.Logf("Received signal %s, shutting down", sig)
+		m.shutdown()
+	case <-runDone:
+		return
+	}
 	
-	// Check interfaces
-	currentAllInterfacesUp := m.checkNetworkInterfaces()
+	time.Sleep(m.config.ShutdownTimeout)
+	m.logger.Logf("*** SHUTDOWN GRACE PERIOD EXPIRED (%s) - FORCING EXIT ***", m.config.ShutdownTimeout)
+	// The lock isn't released here: Run still owns the lock file, and the
+	// kernel drops the flock when the process exits
+	os.Exit(ExitSignal)
+}
+
+// reloadConfig re-reads the configuration sources and applies the options
+// that can change without a restart; a broken config file keeps the current settings
+func (m *Monitor) reloadConfig() {
+	m.logger.Log("Received SIGHUP, reloading configuration")
 	
-	// Check gateway connectivity
-	currentGatewayReachable := m.checkGatewayConnectivity()
+	next, err := config.Load()
+	if err != nil {
+		m.logger.Logf("Config reload: FAILED - keeping current configuration: %v", err)
+		return
+	}
+	if err := next.Validate(); err != nil {
+		m.logger.Logf("Config reload: INVALID - keeping current configuration: %v", err)
+		return
+	}
+	// The set of checks only changes on a restart, so the readiness settings
+	// must fit the checks that are running now
+	if err := next.ValidateReadiness(m.config.EnabledChecks()); err != nil {
+		m.logger.Logf("Config reload: INVALID for the running checks - keeping current configuration: %v", err)
+		return
+	}
 	
-	// Check DNS resolution
-	currentDNSWorking := m.checkDNSResolution()
+	var nextExpr *readiness.Expression
+	if next.ReadinessExpr != "" {
+		// Already validated, so this can't fail
+		nextExpr, _ = readiness.Parse(next.ReadinessExpr)
+	}
 	
-	// Check NetworkManager connectivity
-	currentNMConnectivity := m.checkNetworkManagerConnectivity()
+	changes := m.config.ApplyReloadable(next)
+	m.readyExpr = nextExpr
+	restart := m.config.RestartRequired(next)
+	if len(changes) == 0 && len(restart) == 0 {
+		m.logger.Log("Config reload: no changes")
+		return
+	}
+	for _, change := range changes {
+		m.logger.Logf("Config reload: %s", change)
+	}
+	if len(restart) > 0 {
+		m.logger.Logf("Config reload: changed but needs a restart to take effect: %s", strings.Join(restart, ", "))
+	}
+}
+
+// RunOnce performs a single check cycle without acquiring the lock file,
+// prints the per-check results and returns whether the network is ready
+func (m *Monitor) RunOnce() bool {
+	// A single cycle can never satisfy a multi-cycle threshold
+	m.config.FailureThreshold = 1
+	m.config.RecoveryThreshold = 1
+	m.config.CheckThresholds = nil
 	
-	// Check ARP table
-	currentARPTableValid := m.checkARPTable()
+	ctx := m.shutdownCtx
+	m.discoverServices(ctx)
+	if err := m.performChecks(ctx); err != nil {
+		m.logger.Logf("Error during checks: %v", err)
+	}
 	
-	// Check routing table
-	currentRoutingTableValid := m.checkRoutingTable()
+	ready := m.isReady()
+	if m.config.Output == "json" {
+		if err := m.writeJSONReport(os.Stdout, ready); err != nil {
+			m.logger.Logf("Error writing JSON report: %v", err)
+		}
+		return ready
+	}
 	
-	// Log status summary
-	m.logStatusSummary(
-		currentAllInterfacesUp,
-		currentGatewayReachable,
-		currentServicesReady,
-		currentDNSWorking,
-		currentNMConnectivity,
-		currentARPTableValid,
-		currentRoutingTableValid,
-	)
+	fmt.Println("")
+	fmt.Println("Check results:")
+	for _, name := range m.config.EnabledChecks() {
+		m.printResult(name, m.states[name])
+	}
 	
-	// Update state and log transitions
-	m.updateStates(
-		currentAllInterfacesUp,
-		currentGatewayReachable,
-		currentServicesReady,
-		currentDNSWorking,
-		currentNMConnectivity,
-		currentARPTableValid,
-		currentRoutingTableValid,
-	)
+	if ready {
+		fmt.Println("Network: READY")
+	} else {
+		fmt.Println("Network: NOT READY")
+	}
 	
-	return nil
+	return ready
 }
 
-// logStatusSummary logs a concise summary of all component states
-func (m *Monitor) logStatusSummary(interfaces, gateway, services, dns, nm, arp, routing bool) {
-	var summary strings.Builder
-	summary.WriteString("Status:")
+// Poll runs check cycles every sleep interval until ctx is done, passing the
+// readiness and per-check states to onCycle after each one. Unlike Run it
+// takes no lock file, installs no signal handlers, sends no notifications and
+// never exits on its own, so it can be embedded in other programs.
+func (m *Monitor) Poll(ctx context.Context, onCycle func(ready bool, states map[string]bool)) error {
+	m.discoverServices(ctx)
 	
-	if interfaces {
-		summary.WriteString(" Interfaces=UP")
-	} else {
-		summary.WriteString(" Interfaces=DOWN")
+	ticker := time.NewTicker(m.jitter(m.config.SleepInterval))
+	defer ticker.Stop()
+	
+	for {
+		if err := m.performChecks(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			m.logger.Logf("Error during checks: %v", err)
+		} else {
+			onCycle(m.isReady(), m.stateMap())
+		}
+		
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			ticker.Reset(m.jitter(m.config.SleepInterval))
+		}
+	}
+}
+
+// jitter randomizes an interval by up to the configured percentage either way,
+// so a fleet of hosts booting together doesn't check shared services in lockstep
+func (m *Monitor) jitter(interval time.Duration) time.Duration {
+	if m.config.SleepJitter <= 0 {
+		return interval
+	}
+	spread := float64(interval) * float64(m.config.SleepJitter) / 100
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+// printResult prints a single check result for RunOnce
+func (m *Monitor) printResult(name string, state bool) {
+	result := "FAIL"
+	if state {
+		result = "PASS"
+	}
+	if m.config.IsAdvisory(name) {
+		result += " (advisory)"
+	}
+	fmt.Printf("  %-16s %s\n", name, result)
+}
+
+// InterfaceTypeWarnings lists the required interfaces present on the host
+// whose type isn't one of the monitored interface types. They are monitored
+// anyway, but usually mean interface_types needs the type added.
+func InterfaceTypeWarnings(cfg *config.Config) []string {
+	if len(cfg.RequiredInterfaces) == 0 {
+		return nil
+	}
+	ifaceMonitor := network.NewInterfaceMonitor(cfg.InterfaceTypes, cfg.ExcludedInterfaces, cfg.IgnoreSRIOVVFs)
+	interfaces, err := ifaceMonitor.DiscoverInterfaces(cfg.RequiredInterfaces)
+	if err != nil {
+		return nil
 	}
 	
-	if gateway {
-		summary.WriteString(" Gateway=UP")
-	} else {
-		summary.WriteString(" Gateway=DOWN")
+	var warnings []string
+	for _, iface := range interfaces {
+		if iface.Required && !iface.Excluded && !iface.TypeMonitored {
+			warnings = append(warnings, fmt.Sprintf("required interface %s is of type %s, which is not in interface_types (%s); it is monitored because it is required",
+				iface.Name, iface.Type, strings.Join(cfg.InterfaceTypes, " ")))
+		}
 	}
+	return warnings
+}
+
+// DryRun prints what would be monitored with the current configuration:
+// interfaces and their types, enabled services, the gateway and resolvers.
+// No checks run and the lock file is not touched.
+func (m *Monitor) DryRun() {
+	ctx := m.shutdownCtx
 	
-	if services {
-		summary.WriteString(" Services=READY")
-	} else {
-		summary.WriteString(" Services=NOT_READY")
+	mode := "MONITORING"
+	if m.config.BlockingMode {
+		mode = "BLOCKING"
+	}
+	fmt.Println("")
+	fmt.Printf("Dry run (%s mode) - no checks will be run\n", mode)
+	if m.config.ConfigFile != "" {
+		fmt.Printf("  Config file: %s\n", m.config.ConfigFile)
+	}
+	for _, fragment := range m.config.ConfigFragments {
+		fmt.Printf("  Config drop-in: %s\n", fragment)
+	}
+	if m.config.Profile != "" {
+		fmt.Printf("  Config profile: %s\n", m.config.Profile)
+	}
+	
+	// Interfaces, including the ones that would be skipped and why
+	fmt.Println("")
+	fmt.Printf("Interfaces (types: %s):\n", strings.Join(m.config.InterfaceTypes, " "))
+	interfaces, err := m.ifaceMonitor.DiscoverInterfaces(m.config.RequiredInterfaces)
+	if err != nil {
+		fmt.Printf("  ERROR: %v\n", err)
+	}
+	for _, iface := range interfaces {
+		state := "monitored"
+		if iface.Excluded && iface.PF != "" && m.config.IgnoreSRIOVVFs {
+			state = fmt.Sprintf("excluded (SR-IOV VF of %s)", iface.PF)
+		} else if iface.Excluded {
+			state = "excluded"
+		} else if !iface.Monitored {
+			state = "not monitored (type)"
+		} else if !iface.TypeMonitored {
+			state = "monitored (required, type not monitored)"
+		}
+		fmt.Printf("  %-16s %-10s %s\n", iface.Name, iface.Type, state)
+	}
+	if len(m.config.RequiredInterfaces) > 0 {
+		fmt.Printf("  Required: %s\n", strings.Join(m.config.RequiredInterfaces, " "))
+	}
+	for _, warning := range InterfaceTypeWarnings(m.config) {
+		fmt.Printf("  WARNING: %s\n", warning)
+	}
+	if m.config.MinInterfacesUp > 0 {
+		fmt.Printf("  Minimum up: %d\n", m.config.MinInterfacesUp)
+	}
+	if len(m.config.WirelessSSIDs) > 0 {
+		fmt.Printf("  Wireless SSIDs: %s\n", strings.Join(m.config.WirelessSSIDs, " "))
+	}
+	if m.config.WirelessMinSignal < 0 {
+		fmt.Printf("  Wireless minimum signal: %ddBm\n", m.config.WirelessMinSignal)
 	}
 	
-	if dns {
-		summary.WriteString(" DNS=OK")
+	// Services found via systemd
+	fmt.Println("")
+	fmt.Println("Services:")
+	if m.systemd == nil {
+		fmt.Println("  Service monitoring disabled (systemd not available)")
 	} else {
-		summary.WriteString(" DNS=FAIL")
+		enabled, err := m.systemd.GetEnabledServices(ctx, m.config.NetworkServices)
+		if err != nil {
+			fmt.Printf("  ERROR: %v\n", err)
+		}
+		for _, service := range m.config.NetworkServices {
+			state := "not found"
+			if containsString(enabled, service) {
+				state = "enabled - will monitor"
+			}
+			fmt.Printf("  %-40s %s\n", service, state)
+		}
+		for _, service := range m.config.RequiredServices {
+			fmt.Printf("  %-40s required\n", service)
+		}
 	}
 	
-	if nm {
-		summary.WriteString(" NetworkManager=FULL")
+	// Gateway and ping targets
+	fmt.Println("")
+	fmt.Printf("Gateway (%s):\n", config.RouteTableName(m.config.RouteTable))
+	if gateway, err := m.connectivity.GetDefaultGateway(); err != nil {
+		fmt.Printf("  Default gateway: %v\n", err)
 	} else {
-		summary.WriteString(" NetworkManager=LIMITED")
+		fmt.Printf("  Default gateway: %s\n", gateway)
+	}
+	if m.config.GatewayFamily != "ipv4" {
+		if gateway, err := m.connectivity.GetDefaultGateway6(); err != nil {
+			fmt.Printf("  IPv6 default gateway: %v\n", err)
+		} else {
+			fmt.Printf("  IPv6 default gateway: %s\n", gateway)
+		}
+		fmt.Printf("  Gateway family: %s\n", m.config.GatewayFamily)
+	}
+	if m.config.GatewayProbe != "icmp" {
+		fmt.Printf("  Gateway probe: %s\n", m.config.GatewayProbe)
+	}
+	if len(m.config.PingTargets) > 0 {
+		fmt.Printf("  Ping targets: %s (policy: %s)\n", strings.Join(m.config.PingTargets, " "), m.config.PingPolicy)
+		if m.config.PingGateway {
+			fmt.Println("  Default gateway is pinged as an additional target")
+		}
+	}
+	rttLimit := func(rtt time.Duration) string {
+		if rtt == 0 {
+			return "no limit"
+		}
+		return rtt.String()
+	}
+	fmt.Printf("  Echo requests: %d per target (max loss %d%%, max average rtt %s)\n",
+		m.config.PingCount, m.config.PingMaxLoss, rttLimit(m.config.PingMaxRTT))
+	var overridden []string
+	for target := range m.config.PingThresholds {
+		overridden = append(overridden, target)
+	}
+	if _, ok := m.config.PingThresholds["gateway"]; !ok && m.config.GatewayMaxRTT > 0 {
+		overridden = append(overridden, "gateway")
+	}
+	sort.Strings(overridden)
+	for _, target := range overridden {
+		count, maxLoss, maxRTT := m.config.PingSettings(target)
+		fmt.Printf("  %s: %d echo requests (max loss %d%%, max average rtt %s)\n", target, count, maxLoss, rttLimit(maxRTT))
 	}
 	
-	if arp {
-		summary.WriteString(" ARP=VALID")
+	// Resolver configuration
+	fmt.Println("")
+	fmt.Println("DNS:")
+	fmt.Printf("  Resolver hostnames: %s (quorum: %s)\n", strings.Join(m.config.ResolverHostnames, " "), m.config.DNSQuorum)
+	if len(m.config.DNSServers) > 0 {
+		fmt.Printf("  Servers (queried directly): %s\n", strings.Join(m.config.DNSServers, " "))
+	} else if m.config.DNSSystemServers {
+		if servers, err := m.connectivity.UpstreamNameservers(); err != nil {
+			fmt.Printf("  System resolver: %v\n", err)
+		} else {
+			fmt.Printf("  System nameservers (queried directly): %s\n", strings.Join(servers, " "))
+		}
+	} else if servers, err := m.connectivity.SystemNameservers(); err != nil {
+		fmt.Printf("  System resolver: %v\n", err)
 	} else {
-		summary.WriteString(" ARP=INVALID")
+		fmt.Printf("  System resolver nameservers: %s\n", strings.Join(servers, " "))
+	}
+	if len(m.config.EncryptedDNS) > 0 {
+		fmt.Printf("  Encrypted servers: %s\n", strings.Join(m.config.EncryptedDNS, " "))
 	}
 	
-	if routing {
-		summary.WriteString(" Routing=VALID")
+	if len(m.config.TCPChecks) > 0 {
+		fmt.Println("")
+		fmt.Printf("TCP endpoints (%s timeout):\n", m.config.TCPTimeout)
+		for _, endpoint := range m.config.TCPChecks {
+			fmt.Printf("  %s\n", endpoint)
+		}
+	}
+	
+	if len(m.config.TLSChecks) > 0 {
+		fmt.Println("")
+		fmt.Printf("TLS endpoints (%s timeout, valid for at least %d days):\n", m.config.TLSTimeout, m.config.TLSMinDays)
+		for _, endpoint := range m.config.TLSChecks {
+			fmt.Printf("  %s\n", config.TLSEndpoint(endpoint))
+		}
+	}
+	
+	if m.config.PathTarget != "" {
+		fmt.Println("")
+		fmt.Printf("Path check: first %d hops toward %s must respond\n", m.config.PathHops, m.config.PathTarget)
+	}
+	
+	if len(m.config.HTTPChecks) > 0 {
+		fmt.Println("")
+		if m.config.HTTPViaProxy {
+			fmt.Printf("HTTP endpoints (via %s):\n", m.config.ProxyURL)
+		} else {
+			fmt.Println("HTTP endpoints:")
+		}
+		for _, check := range m.config.HTTPChecks {
+			fmt.Printf("  %s (%s)\n", check.URL, m.describeHTTPCheck(check))
+		}
+	}
+	
+	if m.config.CaptivePortal {
+		fmt.Printf("Captive portal URL: %s (%s timeout)\n", m.config.CaptivePortalURL, m.config.HTTPTimeout)
+	}
+	if m.config.CheckProxy {
+		if proxy, err := m.proxy(); err != nil {
+			fmt.Printf("Proxy: %v\n", err)
+		} else {
+			fmt.Printf("Proxy: %s, tested with %s (%s timeout)\n", proxy.Redacted(), m.config.ProxyTestURL, m.config.HTTPTimeout)
+		}
+	}
+	
+	// Checks that would decide readiness
+	fmt.Println("")
+	fmt.Println("Checks:")
+	for _, name := range m.config.EnabledChecks() {
+		m.printCheck(name)
+	}
+	if m.readyExpr != nil {
+		fmt.Printf("  Readiness: %s\n", m.readyExpr)
 	} else {
-		summary.WriteString(" Routing=INVALID")
+		fmt.Printf("  Readiness quorum: %s\n", m.config.ReadinessQuorum)
+	}
+}
+
+// printCheck prints an enabled check name for a dry run
+func (m *Monitor) printCheck(name string) {
+	if m.config.IsAdvisory(name) {
+		fmt.Printf("  %s (advisory)\n", name)
+	} else {
+		fmt.Printf("  %s\n", name)
+	}
+}
+
+// discoverServices finds the configured network services that are enabled,
+// the ones the services check monitors
+func (m *Monitor) discoverServices(ctx context.Context) {
+	var enabledServices []string
+	if m.systemd != nil {
+		services, err := m.systemd.GetEnabledServices(ctx, m.config.NetworkServices)
+		if err != nil {
+			m.logger.Logf("Warning: Failed to get enabled services: %v", err)
+		} else {
+			enabledServices = services
+			for _, service := range services {
+				m.logger.Logf("Service %s: found and enabled - will monitor", service)
+			}
+		}
+	}
+	
+	if len(enabledServices) == 0 {
+		m.logger.Log("Network services: NONE FOUND")
+	}
+	
+	m.enabledServices = enabledServices
+}
+
+// performChecks performs all network status checks
+func (m *Monitor) performChecks(ctx context.Context) error {
+	m.logger.Log("=== Network Status Check ===")
+	
+	// Independent checks run concurrently, so a cycle takes as long as the
+	// slowest check rather than the sum of all of them
+	var checks []namedCheck
+	for _, name := range m.config.EnabledChecks() {
+		if spec, ok := checkSpecs[name]; ok {
+			checks = append(checks, m.builtinCheck(name, spec))
+		}
+	}
+	checks = append(checks, m.execCheckList()...)
+	
+	m.runChecks(ctx, checks)
+	
+	// Results of cancelled checks are meaningless; don't record them
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	
+	m.logStatusSummary()
+	m.logExecSummary()
+	m.updateStates(checks)
+	
+	return nil
+}
+
+// performLinkChecks re-runs only the checks driven by kernel link, route and
+// neighbor state, keeping the last known results for services, DNS and NetworkManager
+func (m *Monitor) performLinkChecks(ctx context.Context) error {
+	m.logger.Log("=== Network Status Check (link event) ===")
+	
+	var checks []namedCheck
+	for _, name := range m.config.EnabledChecks() {
+		if spec, ok := checkSpecs[name]; ok && spec.link {
+			checks = append(checks, m.builtinCheck(name, spec))
+		}
+	}
+	
+	m.runChecks(ctx, checks)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	
+	m.logStatusSummary()
+	m.updateStates(checks)
+	
+	return nil
+}
+
+// builtinCheck binds a built-in check to the monitor
+func (m *Monitor) builtinCheck(name string, spec checkSpec) namedCheck {
+	return namedCheck{name, func(ctx context.Context) *CheckResult {
+		return spec.run(m, ctx)
+	}}
+}
+
+// namedCheck is a check function and the name its result is recorded under
+type namedCheck struct {
+	name  string
+	check func(context.Context) *CheckResult
+}
+
+// checkRun is a check's result and the log output buffered while it ran
+type checkRun struct {
+	result *CheckResult
+	log    *logger.Logger
+}
+
+// loggerKey is the context key for a running check's buffered logger
+type loggerKey struct{}
+
+// log returns the logger a check should write to: its own buffered logger
+// while running under runChecks, otherwise the monitor's logger
+func (m *Monitor) log(ctx context.Context) *logger.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*logger.Logger); ok {
+		return l
+	}
+	return m.logger
+}
+
+// runChecks runs the checks concurrently and waits for all of them. Each
+// check's log output is buffered and flushed in the order given, so the log
+// reads the same as if the checks had run one after another. The results are
+// recorded in m.results.
+func (m *Monitor) runChecks(ctx context.Context, checks []namedCheck) {
+	runs := make([]checkRun, len(checks))
+	blocked := m.blockedBy(ctx, checks)
+	
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		if dep, ok := blocked[c.name]; ok {
+			runs[i] = m.skipCheck(c.name, dep)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			runs[i] = m.timeCheck(ctx, c.check)
+		}(i, c)
+	}
+	wg.Wait()
+	
+	for i, c := range checks {
+		run := runs[i]
+		run.result.Name = c.name
+		run.result.Messages = run.log.Messages()
+		m.results[c.name] = run.result
+		m.recordRun(run.result)
+		run.log.Flush()
+		if m.metrics != nil {
+			m.metrics.ObserveDuration(c.name, run.result.Duration)
+		}
+	}
+}
+
+// skipCheck records a check whose dependency isn't met without running it
+func (m *Monitor) skipCheck(name string, dep dependency) checkRun {
+	buffer := m.logger.Buffered()
+	buffer.Logf("Check %s: BLOCKED by %s - skipped", name, dep.name)
+	result := newResult().passIf(dep.passIfBlocked)
+	result.BlockedBy = dep.name
+	return checkRun{result: result, log: buffer}
+}
+
+// timeCheck runs a single check with its own buffered logger and measures how long it takes
+func (m *Monitor) timeCheck(ctx context.Context, check func(context.Context) *CheckResult) checkRun {
+	buffer := m.logger.Buffered()
+	start := time.Now()
+	result := check(context.WithValue(ctx, loggerKey{}, buffer))
+	result.Duration = time.Since(start)
+	return checkRun{result: result, log: buffer}
+}
+
+// logStatusSummary logs a concise summary of the latest result of each
+// enabled built-in check
+func (m *Monitor) logStatusSummary() {
+	var summary strings.Builder
+	summary.WriteString("Status:")
+	
+	for _, name := range m.config.EnabledChecks() {
+		if spec, ok := checkSpecs[name]; ok {
+			summary.WriteString(" " + spec.label + "=" + m.summaryState(name, spec))
+		}
+	}
+	
+	if len(m.config.AdvisoryChecks) > 0 {
+		summary.WriteString(" Advisory=" + strings.Join(m.config.AdvisoryChecks, ","))
 	}
 	
 	m.logger.Log(summary.String())
 }
 
+// isReady reports whether the readiness quorum (all by default) of enabled,
+// required checks is currently passing; advisory checks are only logged.
+// A readiness expression, when configured, replaces this rule entirely
+func (m *Monitor) isReady() bool {
+	if m.readyExpr != nil {
+		return m.readyExpr.Eval(m.stateMap())
+	}
+	
+	required := m.config.RequiredChecks()
+	passing := 0
+	for _, name := range required {
+		if m.states[name] {
+			passing++
+		}
+	}
+	
+	needed, _ := config.ParseQuorum(m.config.ReadinessQuorum, len(required))
+	return passing >= needed
+}
+
+// readinessRule describes the condition isReady applies, e.g.
+// "services + interfaces + gateway" or "2 of dns, tcp"
+func (m *Monitor) readinessRule() string {
+	if m.readyExpr != nil {
+		return m.readyExpr.String()
+	}
+	
+	required := m.config.RequiredChecks()
+	needed, _ := config.ParseQuorum(m.config.ReadinessQuorum, len(required))
+	if needed < len(required) {
+		return fmt.Sprintf("%d of %s", needed, strings.Join(required, ", "))
+	}
+	return strings.Join(required, " + ")
+}
+
 // shouldExit determines if the monitor should exit
 func (m *Monitor) shouldExit() bool {
-	allReady := m.allInterfacesUp && m.gatewayReachable && m.servicesReady &&
-		m.dnsWorking && m.nmConnectivityFull && m.arpTableValid && m.routingTableValid
-	
-	if allReady {
+	if m.isReady() {
 		if m.networkCompleteTime.IsZero() {
 			m.networkCompleteTime = time.Now()
+			if m.firstReadyTime.IsZero() {
+				m.firstReadyTime = m.networkCompleteTime
+			}
+			if err := m.sdNotifier.Ready(); err != nil {
+				m.logger.Logf("Warning: Failed to notify systemd: %v", err)
+			}
+			if m.metrics != nil {
+				m.metrics.SetTimeToComplete(m.networkCompleteTime.Sub(m.startTime))
+			}
 			if m.config.BlockingMode {
 				m.logger.Log("*** NETWORK IS READY - UNBLOCKING BOOT PROCESS ***")
+				m.notify(notify.EventUnblocked)
 				return true
 			} else {
-				m.logger.Logf("*** NETWORK SETUP COMPLETE (services + interfaces + gateway + DNS + NetworkManager connectivity + ARP table + routing table) *** (will exit in %s)", m.config.RunAfterSuccess)
+				m.logger.Logf("*** NETWORK SETUP COMPLETE (%s) *** (will exit in %s)", m.readinessRule(), m.config.RunAfterSuccess)
+				m.notify(notify.EventNetworkReady)
 			}
 		} else if m.config.RunAfterSuccess > 0 {
 			elapsed := time.Since(m.networkCompleteTime)
@@ -276,6 +1094,7 @@ func (m *Monitor) shouldExit() bool {
 			} else {
 				m.logger.Log("*** NETWORK NO LONGER COMPLETE - RESETTING SUCCESS TIMER ***")
 			}
+			m.notify(notify.EventNetworkLost)
 			m.networkCompleteTime = time.Time{}
 		}
 	}
@@ -283,8 +1102,55 @@ func (m *Monitor) shouldExit() bool {
 	return false
 }
 
+// reportStatus sends a one-line readiness summary to systemd, shown by systemctl status
+func (m *Monitor) reportStatus() {
+	if !m.sdNotifier.Enabled() {
+		return
+	}
+	
+	status := "Network ready"
+	if !m.isReady() {
+		var failing []string
+		for _, name := range m.config.RequiredChecks() {
+			if !m.states[name] {
+				failing = append(failing, name)
+			}
+		}
+		status = "Waiting for network - failing: " + strings.Join(failing, ", ")
+	}
+	
+	if err := m.sdNotifier.Status(status); err != nil {
+		m.logger.Logf("Warning: Failed to notify systemd: %v", err)
+	}
+}
+
+// notify sends a readiness transition to the webhook and hooks, if configured
+func (m *Monitor) notify(event string) {
+	if m.webhook != nil {
+		m.webhook.Send(m.shutdownCtx, event, m.stateMap())
+	}
+	if m.hooks != nil {
+		m.hooks.Run(m.shutdownCtx, event, m.stateMap())
+	}
+}
+
+// stateMap returns the current state of each enabled check keyed by check name
+func (m *Monitor) stateMap() map[string]bool {
+	states := make(map[string]bool)
+	for _, name := range m.config.EnabledChecks() {
+		states[name] = m.states[name]
+	}
+	return states
+}
+
 // Close cleans up resources
 func (m *Monitor) Close() error {
+	if m.webhook != nil {
+		m.webhook.Close()
+	}
+	if m.hooks != nil {
+		m.hooks.Close()
+	}
 	if m.systemd != nil {
 		m.systemd.Close()
 	}
@@ -292,27 +1158,36 @@ func (m *Monitor) Close() error {
 		m.logger.Close()
 	}
 	m.releaseLock()
+	m.shutdown()
 	return nil
 }
 
-// acquireLock acquires the lock file
+// acquireLock takes an exclusive flock on the lock file and records our PID
+// in it. A lock file left behind by a crashed run isn't locked by anyone, so
+// it is taken over rather than blocking the next boot.
 func (m *Monitor) acquireLock() error {
-	// Check if lock file already exists
-	if _, err := os.Stat(m.config.LockFile); err == nil {
-		return fmt.Errorf("network monitor already running (lockfile exists)")
+	file, err := os.OpenFile(m.config.LockFile, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
 	}
 	
-	// Create lock file
-	file, err := os.Create(m.config.LockFile)
-	if err != nil {
-		return fmt.Errorf("failed to create lock file: %w", err)
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		pid := readLockPID(file)
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return fmt.Errorf("%w (lockfile %s held by pid %d)", ErrLocked, m.config.LockFile, pid)
+		}
+		return fmt.Errorf("failed to lock %s: %w", m.config.LockFile, err)
 	}
 	
-	// Write PID to lock file
-	_, err = fmt.Fprintf(file, "%d\n", os.Getpid())
-	if err != nil {
+	// The lock is ours; any PID already in the file is from a run that
+	// exited or crashed, and may since have been reused by another process
+	if err = file.Truncate(0); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to truncate lock file: %w", err)
+	}
+	if _, err = fmt.Fprintf(file, "%d\n", os.Getpid()); err != nil {
 		file.Close()
-		os.Remove(m.config.LockFile)
 		return fmt.Errorf("failed to write PID to lock file: %w", err)
 	}
 	
@@ -320,11 +1195,23 @@ func (m *Monitor) acquireLock() error {
 	return nil
 }
 
-// releaseLock releases the lock file
+// readLockPID returns the PID recorded in a lock file, or 0 if there is none
+func readLockPID(file *os.File) int {
+	data := make([]byte, 32)
+	n, _ := file.ReadAt(data, 0)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data[:n])))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+// releaseLock releases the lock by closing the file. The file is left in
+// place: removing it would let an instance waiting on the old inode and one
+// creating a new file both take the lock.
 func (m *Monitor) releaseLock() {
 	if m.lockFile != nil {
 		m.lockFile.Close()
-		os.Remove(m.config.LockFile)
 		m.lockFile = nil
 	}
 }
\ No newline at end of file
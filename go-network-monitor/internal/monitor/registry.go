@@ -0,0 +1,202 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+)
+
+// checkSpec describes how a built-in check runs and reports. Which checks run
+// is decided by the configuration (config.EnabledChecks); this table only
+// needs an entry per check name.
+type checkSpec struct {
+	run     func(*Monitor, context.Context) *CheckResult
+	label   string                   // Name shown in the status summary
+	pass    string                   // Summary state while passing
+	fail    string                   // Summary state while failing
+	failing func(*Monitor) string    // Optional finer failing state, e.g. DEGRADED; "" falls back to fail
+	up      string                   // Logged when the check starts passing
+	down    string                   // Logged when the check stops passing
+	link    bool                     // Driven by kernel link, route and neighbor state; re-run on link events
+}
+
+// checkSpecs maps each built-in check name to its spec
+var checkSpecs = map[string]checkSpec{
+	"services": {
+		run:   (*Monitor).checkNetworkServices,
+		label: "Services", pass: "READY", fail: "NOT_READY",
+		up:   "*** NETWORK SERVICES ARE NOW READY ***",
+		down: "*** NETWORK SERVICES NO LONGER READY ***",
+	},
+	"interfaces": {
+		run:   (*Monitor).checkNetworkInterfaces,
+		label: "Interfaces", pass: "UP", fail: "DOWN",
+		up:   "*** ALL INTERFACES ARE NOW UP ***",
+		down: "*** SOME INTERFACES ARE DOWN ***",
+		link: true,
+	},
+	"gateway": {
+		run:   (*Monitor).checkGatewayConnectivity,
+		label: "Gateway", pass: "UP", fail: "DOWN",
+		failing: func(m *Monitor) string {
+			if m.gatewayDegraded() {
+				return "DEGRADED"
+			}
+			return ""
+		},
+		up:   "*** GATEWAY IS NOW REACHABLE ***",
+		down: "*** GATEWAY IS NO LONGER REACHABLE ***",
+		link: true,
+	},
+	"dns": {
+		run:   (*Monitor).checkDNSResolution,
+		label: "DNS", pass: "OK", fail: "FAIL",
+		failing: func(m *Monitor) string {
+			if m.dnsDegraded() {
+				return "SLOW"
+			}
+			return ""
+		},
+		up:   "*** DNS RESOLUTION IS NOW WORKING ***",
+		down: "*** DNS RESOLUTION NO LONGER WORKING ***",
+	},
+	"nm_connectivity": {
+		run:   (*Monitor).checkNetworkManagerConnectivity,
+		label: "NetworkManager", pass: "FULL", fail: "LIMITED",
+		up:   "*** NETWORKMANAGER CONNECTIVITY IS NOW FULL ***",
+		down: "*** NETWORKMANAGER CONNECTIVITY NO LONGER FULL ***",
+	},
+	"arp": {
+		run:   (*Monitor).checkARPTable,
+		label: "ARP", pass: "VALID", fail: "INVALID",
+		up:   "*** ARP TABLE IS NOW VALID ***",
+		down: "*** ARP TABLE NO LONGER VALID ***",
+		link: true,
+	},
+	"routing": {
+		run:   (*Monitor).checkRoutingTable,
+		label: "Routing", pass: "VALID", fail: "INVALID",
+		up:   "*** ROUTING TABLE IS NOW VALID ***",
+		down: "*** ROUTING TABLE NO LONGER VALID ***",
+		link: true,
+	},
+	"dhcp": {
+		run:   (*Monitor).checkDHCPLeases,
+		label: "DHCP", pass: "VALID", fail: "INVALID",
+		up:   "*** DHCP LEASES ARE NOW VALID ***",
+		down: "*** DHCP LEASES NO LONGER VALID ***",
+	},
+	"timesync": {
+		run:   (*Monitor).checkTimeSync,
+		label: "TimeSync", pass: "SYNCED", fail: "UNSYNCED",
+		up:   "*** SYSTEM CLOCK IS NOW SYNCHRONIZED ***",
+		down: "*** SYSTEM CLOCK NO LONGER SYNCHRONIZED ***",
+	},
+	"ndp": {
+		run:   (*Monitor).checkNDPTable,
+		label: "NDP", pass: "VALID", fail: "INVALID",
+		up:   "*** NDP TABLE IS NOW VALID ***",
+		down: "*** NDP TABLE NO LONGER VALID ***",
+		link: true,
+	},
+	"tcp": {
+		run:   (*Monitor).checkTCPEndpoints,
+		label: "TCP", pass: "OK", fail: "FAIL",
+		up:   "*** TCP ENDPOINTS ARE NOW REACHABLE ***",
+		down: "*** TCP ENDPOINTS NO LONGER REACHABLE ***",
+	},
+	"http": {
+		run:   (*Monitor).checkHTTPEndpoints,
+		label: "HTTP", pass: "OK", fail: "FAIL",
+		up:   "*** HTTP ENDPOINTS ARE NOW HEALTHY ***",
+		down: "*** HTTP ENDPOINTS NO LONGER HEALTHY ***",
+	},
+	"captive_portal": {
+		run:   (*Monitor).checkCaptivePortal,
+		label: "Portal", pass: "NONE", fail: "FAIL",
+		failing: func(m *Monitor) string {
+			if m.portalDetected() {
+				return "PORTAL"
+			}
+			return ""
+		},
+		up:   "*** NO CAPTIVE PORTAL - INTERNET ACCESS IS DIRECT ***",
+		down: "*** CAPTIVE PORTAL OR NO INTERNET ACCESS ***",
+	},
+	"proxy": {
+		run:   (*Monitor).checkProxy,
+		label: "Proxy", pass: "OK", fail: "FAIL",
+		up:   "*** HTTP PROXY IS NOW ACCEPTING REQUESTS ***",
+		down: "*** HTTP PROXY NO LONGER ACCEPTING REQUESTS ***",
+	},
+	"tls": {
+		run:   (*Monitor).checkTLSEndpoints,
+		label: "TLS", pass: "VALID", fail: "INVALID",
+		up:   "*** TLS CERTIFICATES ARE NOW VALID ***",
+		down: "*** TLS CERTIFICATES NO LONGER VALID ***",
+	},
+	"path": {
+		run:   (*Monitor).checkPath,
+		label: "Path", pass: "VALID", fail: "BROKEN",
+		up:   "*** NETWORK PATH IS NOW RESPONDING HOP BY HOP ***",
+		down: "*** NETWORK PATH NO LONGER RESPONDING HOP BY HOP ***",
+	},
+	"resolved": {
+		run:   (*Monitor).checkResolved,
+		label: "Resolved", pass: "CONFIGURED", fail: "UNCONFIGURED",
+		up:   "*** SYSTEMD-RESOLVED NOW HAS DNS SERVERS ***",
+		down: "*** SYSTEMD-RESOLVED NO LONGER HAS DNS SERVERS ***",
+	},
+	"encrypted_dns": {
+		run:   (*Monitor).checkEncryptedDNS,
+		label: "EncryptedDNS", pass: "OK", fail: "FAIL",
+		up:   "*** ENCRYPTED DNS IS NOW WORKING ***",
+		down: "*** ENCRYPTED DNS NO LONGER WORKING ***",
+	},
+	"reverse_dns": {
+		run:   (*Monitor).checkReverseDNS,
+		label: "ReverseDNS", pass: "VALID", fail: "FAIL",
+		up:   "*** REVERSE DNS NOW RESOLVES THE HOST ADDRESSES ***",
+		down: "*** REVERSE DNS NO LONGER RESOLVES THE HOST ADDRESSES ***",
+	},
+	"resolv_conf": {
+		run:   (*Monitor).checkResolvConf,
+		label: "ResolvConf", pass: "VALID", fail: "BROKEN",
+		up:   "*** RESOLV.CONF IS NOW VALID ***",
+		down: "*** RESOLV.CONF NO LONGER VALID ***",
+	},
+	"hostname": {
+		run:   (*Monitor).checkHostname,
+		label: "Hostname", pass: "RESOLVING", fail: "UNRESOLVED",
+		up:   "*** HOSTNAME IS NOW RESOLVING ***",
+		down: "*** HOSTNAME NO LONGER RESOLVING ***",
+	},
+	"dnssec": {
+		run:   (*Monitor).checkDNSSEC,
+		label: "DNSSEC", pass: "VALIDATED", fail: "UNVALIDATED",
+		up:   "*** DNSSEC IS NOW VALIDATED ***",
+		down: "*** DNSSEC NO LONGER VALIDATED ***",
+	},
+}
+
+// summaryState returns how a built-in check shows in the status summary
+func (m *Monitor) summaryState(name string, spec checkSpec) string {
+	if passed(m.results, name) {
+		return spec.pass
+	}
+	if spec.failing != nil {
+		if state := spec.failing(m); state != "" {
+			return state
+		}
+	}
+	return spec.fail
+}
+
+// transitionMessages returns the messages logged when a check starts and
+// stops passing
+func transitionMessages(name string) (up, down string) {
+	if spec, ok := checkSpecs[name]; ok {
+		return spec.up, spec.down
+	}
+	return fmt.Sprintf("*** EXTERNAL CHECK %s IS NOW PASSING ***", name),
+		fmt.Sprintf("*** EXTERNAL CHECK %s NO LONGER PASSING ***", name)
+}
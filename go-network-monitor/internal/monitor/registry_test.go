@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"testing"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/config"
+)
+
+// TestCheckSpecsCoverEnabledChecks keeps the check table in step with the
+// checks the configuration can enable; a check without a spec would never run
+func TestCheckSpecsCoverEnabledChecks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CheckDHCP = true
+	cfg.CheckTimeSync = true
+	cfg.CheckNDP = true
+	cfg.TCPChecks = []string{"proxy:3128"}
+	cfg.HTTPChecks = []config.HTTPCheck{{URL: "https://example.com/health"}}
+	cfg.CaptivePortal = true
+	cfg.CheckProxy = true
+	cfg.TLSChecks = []string{"example.com"}
+	cfg.PathTarget = "192.0.2.1"
+	cfg.CheckResolved = true
+	cfg.EncryptedDNS = []string{"tls://1.1.1.1"}
+	cfg.CheckReverseDNS = true
+	cfg.CheckResolvConf = true
+	cfg.CheckHostname = true
+	cfg.CheckDNSSEC = true
+	
+	enabled := cfg.EnabledChecks()
+	for _, name := range enabled {
+		spec, ok := checkSpecs[name]
+		if !ok {
+			t.Errorf("check %s has no entry in checkSpecs", name)
+			continue
+		}
+		if spec.run == nil || spec.label == "" || spec.pass == "" || spec.fail == "" || spec.up == "" || spec.down == "" {
+			t.Errorf("check %s has an incomplete spec", name)
+		}
+	}
+	if len(enabled) != len(checkSpecs) {
+		t.Errorf("%d checks enabled with every option set, %d specs; a spec isn't reachable from config.EnabledChecks", len(enabled), len(checkSpecs))
+	}
+}
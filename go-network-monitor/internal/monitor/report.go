@@ -0,0 +1,206 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// snapshotFileName is the SIGUSR1 state dump, written next to the lock file
+const snapshotFileName = "network_monitor_state.json"
+
+// Report is the machine-readable result of a check cycle (-once -output json)
+type Report struct {
+	Hostname  string        `json:"hostname"`
+	Timestamp time.Time     `json:"timestamp"`
+	Ready     bool          `json:"ready"`
+	Checks    []CheckReport `json:"checks"`
+}
+
+// CheckReport is the outcome of a single check within a Report
+type CheckReport struct {
+	Name       string            `json:"name"`
+	Passed     bool              `json:"passed"`
+	Advisory   bool              `json:"advisory"`
+	DurationMS float64           `json:"duration_ms"`
+	Error      string            `json:"error,omitempty"`
+	BlockedBy  string            `json:"blocked_by,omitempty"`  // Unmet dependency; the check was skipped
+	Details    map[string]string `json:"details"`
+	Messages   []string          `json:"messages"`
+}
+
+// report builds a Report from the latest check results, in check order
+func (m *Monitor) report(ready bool) Report {
+	hostname, _ := os.Hostname()
+	report := Report{
+		Hostname:  hostname,
+		Timestamp: time.Now(),
+		Ready:     ready,
+		Checks:    []CheckReport{},
+	}
+	
+	for _, name := range m.config.EnabledChecks() {
+		check := CheckReport{
+			Name:     name,
+			Passed:   m.states[name],
+			Advisory: m.config.IsAdvisory(name),
+			Details:  map[string]string{},
+			Messages: []string{},
+		}
+		if result := m.results[name]; result != nil {
+			check.DurationMS = float64(result.Duration) / float64(time.Millisecond)
+			check.BlockedBy = result.BlockedBy
+			if result.Err != nil {
+				check.Error = result.Err.Error()
+			}
+			if len(result.Details) > 0 {
+				check.Details = result.Details
+			}
+			if result.Messages != nil {
+				check.Messages = result.Messages
+			}
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	
+	return report
+}
+
+// writeJSONReport writes the latest check results as an indented JSON document
+func (m *Monitor) writeJSONReport(w io.Writer, ready bool) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m.report(ready))
+}
+
+// Snapshot is the full monitor state dumped on SIGUSR1
+type Snapshot struct {
+	Report
+	StartTime           time.Time      `json:"start_time"`
+	UptimeSeconds       float64        `json:"uptime_seconds"`
+	NetworkCompleteTime *time.Time     `json:"network_complete_time,omitempty"`
+	ReadySeconds        float64        `json:"ready_seconds,omitempty"`  // Time since the network became ready
+	PendingCounts       map[string]int `json:"pending_counts"`           // Consecutive results awaiting a state change
+}
+
+// snapshot captures the current state of every check and the readiness timers
+func (m *Monitor) snapshot() Snapshot {
+	snapshot := Snapshot{
+		Report:        m.report(m.isReady()),
+		StartTime:     m.startTime,
+		UptimeSeconds: time.Since(m.startTime).Seconds(),
+		PendingCounts: make(map[string]int),
+	}
+	if !m.networkCompleteTime.IsZero() {
+		completeTime := m.networkCompleteTime
+		snapshot.NetworkCompleteTime = &completeTime
+		snapshot.ReadySeconds = time.Since(completeTime).Seconds()
+	}
+	for name, count := range m.pendingCounts {
+		if count > 0 {
+			snapshot.PendingCounts[name] = count
+		}
+	}
+	return snapshot
+}
+
+// dumpState logs the current state and writes it as JSON next to the lock
+// file, for debugging a stuck boot without restarting the service
+func (m *Monitor) dumpState() {
+	snapshot := m.snapshot()
+	
+	m.logger.Log("=== State Snapshot (SIGUSR1) ===")
+	m.logger.Logf("Uptime: %s", time.Since(m.startTime).Round(time.Millisecond))
+	if snapshot.NetworkCompleteTime != nil {
+		m.logger.Logf("Network ready since: %s (%s ago)",
+			snapshot.NetworkCompleteTime.Format(time.RFC3339), time.Since(*snapshot.NetworkCompleteTime).Round(time.Millisecond))
+	} else {
+		m.logger.Log("Network ready since: NOT READY")
+	}
+	for _, check := range snapshot.Checks {
+		result := "FAIL"
+		if check.Passed {
+			result = "PASS"
+		}
+		if check.Advisory {
+			result += " (advisory)"
+		}
+		if count := snapshot.PendingCounts[check.Name]; count > 0 {
+			result += fmt.Sprintf(", change pending %d", count)
+		}
+		m.logger.Logf("Check %s: %s, %.1fms - %s", check.Name, result, check.DurationMS, strings.Join(check.Messages, "; "))
+	}
+	
+	path := filepath.Join(filepath.Dir(m.config.LockFile), snapshotFileName)
+	if err := writeJSONFile(path, snapshot); err != nil {
+		m.logger.Logf("Warning: Failed to write state snapshot: %v", err)
+		return
+	}
+	m.logger.Logf("State snapshot written to %s", path)
+}
+
+// RunState is the outcome of the current or last run, persisted to the state
+// file for other tooling
+type RunState struct {
+	Snapshot
+	Outcome            string  `json:"outcome"`  // "running" until exit, then "ready", "timeout", "interrupted" or "failed"
+	TimeToReadySeconds float64 `json:"time_to_ready_seconds,omitempty"`
+}
+
+// saveState writes the run state to the state file, if one is configured
+func (m *Monitor) saveState(outcome string) {
+	m.stateChanged = false
+	if m.config.StateFile == "" {
+		return
+	}
+	
+	state := RunState{
+		Snapshot: m.snapshot(),
+		Outcome:  outcome,
+	}
+	if !m.firstReadyTime.IsZero() {
+		state.TimeToReadySeconds = m.firstReadyTime.Sub(m.startTime).Seconds()
+	}
+	
+	if err := os.MkdirAll(filepath.Dir(m.config.StateFile), 0755); err != nil {
+		m.logger.Logf("Warning: Failed to write state file: %v", err)
+		return
+	}
+	if err := writeJSONFile(m.config.StateFile, state); err != nil {
+		m.logger.Logf("Warning: Failed to write state file: %v", err)
+	}
+}
+
+// outcome names how a run ended, for the state file
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ready"
+	case errors.Is(err, ErrTimeout):
+		return "timeout"
+	case errors.Is(err, ErrInterrupted):
+		return "interrupted"
+	default:
+		return "failed"
+	}
+}
+
+// writeJSONFile replaces path with the indented JSON encoding of v, via a
+// temporary file so readers never see a partial document
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// CheckResult is the outcome of a single check run. Check functions fill in
+// Passed, Err and Details; runChecks adds the name, timing, log lines and
+// any unmet dependency.
+type CheckResult struct {
+	Name      string
+	Passed    bool
+	Err       error              // Error behind a failure, when the check failed on one
+	Duration  time.Duration
+	Details   map[string]string  // Facts gathered by the check, e.g. "gateway" or "entries"
+	BlockedBy string             // Unmet dependency; the check was skipped
+	Messages  []string           // Log lines written by the check
+}
+
+// newResult returns an empty result for a check to fill in
+func newResult() *CheckResult {
+	return &CheckResult{Details: make(map[string]string)}
+}
+
+// detail records a fact about the result
+func (r *CheckResult) detail(key string, value interface{}) {
+	r.Details[key] = fmt.Sprint(value)
+}
+
+// pass marks the check as passing
+func (r *CheckResult) pass() *CheckResult {
+	r.Passed = true
+	return r
+}
+
+// fail marks the check as failing without an underlying error
+func (r *CheckResult) fail() *CheckResult {
+	r.Passed = false
+	return r
+}
+
+// failWith marks the check as failing on err
+func (r *CheckResult) failWith(err error) *CheckResult {
+	r.Passed = false
+	r.Err = err
+	return r
+}
+
+// passIf marks the check as passing when ok holds
+func (r *CheckResult) passIf(ok bool) *CheckResult {
+	r.Passed = ok
+	return r
+}
+
+// passed reports whether the named check ran in this cycle and passed
+func passed(results map[string]*CheckResult, name string) bool {
+	result, ok := results[name]
+	return ok && result.Passed
+}
@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// checkTimeline tracks a single check over the whole run, for the readiness
+// timeline logged at exit
+type checkTimeline struct {
+	firstPassed time.Time
+	ups         int            // Confirmed transitions to passing
+	downs       int            // Confirmed transitions back to failing after passing
+	slowest     time.Duration
+	
+	// Raw results, before thresholds are applied
+	ran                    bool
+	lastPassed             bool
+	consecutiveFailures    int
+	maxConsecutiveFailures int
+	changes                []time.Time  // Result changes within the flap window
+	flapping               bool
+}
+
+// timelineFor returns the timeline of a check, creating it on first use
+func (m *Monitor) timelineFor(name string) *checkTimeline {
+	timeline, ok := m.timeline[name]
+	if !ok {
+		timeline = &checkTimeline{}
+		m.timeline[name] = timeline
+	}
+	return timeline
+}
+
+// recordTransition notes a confirmed state change of a check
+func (m *Monitor) recordTransition(name string, passing bool) {
+	m.stateChanged = true
+	timeline := m.timelineFor(name)
+	if !passing {
+		timeline.downs++
+		return
+	}
+	timeline.ups++
+	if timeline.firstPassed.IsZero() {
+		timeline.firstPassed = time.Now()
+	}
+}
+
+// recordRun notes the duration and raw result of a check run, and warns when
+// the result keeps changing within the flap window
+func (m *Monitor) recordRun(result *CheckResult) {
+	timeline := m.timelineFor(result.Name)
+	if result.Duration > timeline.slowest {
+		timeline.slowest = result.Duration
+	}
+	
+	if result.Passed {
+		timeline.consecutiveFailures = 0
+	} else {
+		timeline.consecutiveFailures++
+		if timeline.consecutiveFailures > timeline.maxConsecutiveFailures {
+			timeline.maxConsecutiveFailures = timeline.consecutiveFailures
+		}
+	}
+	
+	now := time.Now()
+	if timeline.ran && result.Passed != timeline.lastPassed {
+		timeline.changes = append(timeline.changes, now)
+	}
+	timeline.ran = true
+	timeline.lastPassed = result.Passed
+	
+	// Forget changes that have aged out of the window
+	recent := timeline.changes[:0]
+	for _, change := range timeline.changes {
+		if now.Sub(change) <= m.config.FlapWindow {
+			recent = append(recent, change)
+		}
+	}
+	timeline.changes = recent
+	
+	flapping := m.config.FlapThreshold > 0 && len(timeline.changes) > m.config.FlapThreshold
+	if flapping && !timeline.flapping {
+		m.logger.Logf("Warning: Check %s is FLAPPING (%d result changes in %s)",
+			result.Name, len(timeline.changes), m.config.FlapWindow)
+	} else if !flapping && timeline.flapping {
+		m.logger.Logf("Check %s is no longer flapping", result.Name)
+	}
+	timeline.flapping = flapping
+}
+
+// logTimeline summarizes the run: when each check first passed, the total
+// time to ready, the slowest check and each check's transition and failure counters
+func (m *Monitor) logTimeline() {
+	if len(m.timeline) == 0 {
+		return
+	}
+	
+	m.logger.Log("=== Readiness Timeline ===")
+	
+	var slowestName string
+	var slowest time.Duration
+	for _, name := range m.config.EnabledChecks() {
+		timeline, ok := m.timeline[name]
+		if !ok {
+			continue
+		}
+		
+		firstPassed := "never passed"
+		if !timeline.firstPassed.IsZero() {
+			firstPassed = fmt.Sprintf("first passed +%s", timeline.firstPassed.Sub(m.startTime).Round(time.Millisecond))
+		}
+		flapping := ""
+		if timeline.flapping {
+			flapping = ", FLAPPING"
+		}
+		m.logger.Logf("Timeline %s: %s, slowest run %s, %d up/%d down transitions, %d max consecutive failures%s",
+			name, firstPassed, timeline.slowest.Round(time.Microsecond), timeline.ups, timeline.downs,
+			timeline.maxConsecutiveFailures, flapping)
+		
+		if timeline.slowest > slowest {
+			slowestName, slowest = name, timeline.slowest
+		}
+	}
+	
+	if slowestName != "" {
+		m.logger.Logf("Slowest check: %s (%s)", slowestName, slowest.Round(time.Microsecond))
+	}
+	if m.firstReadyTime.IsZero() {
+		m.logger.Logf("Time to ready: NOT READY after %s", time.Since(m.startTime).Round(time.Millisecond))
+	} else {
+		m.logger.Logf("Time to ready: %s", m.firstReadyTime.Sub(m.startTime).Round(time.Millisecond))
+	}
+}
@@ -1,8 +1,10 @@
 package network
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"time"
 	
 	"github.com/vishvananda/netlink"
 )
@@ -20,27 +22,125 @@ type ARPTableStatus struct {
 	TotalEntries     int
 	GatewayResolved  bool
 	GatewayMAC       net.HardwareAddr
+	GatewayProbed    bool
 	InterfaceEntries map[string]int
 }
 
 // ARPMonitor handles ARP table monitoring
-type ARPMonitor struct{}
+type ARPMonitor struct {
+	probeTimeout time.Duration // Zero disables active gateway probing
+}
 
 // NewARPMonitor creates a new ARP monitor
-func NewARPMonitor() *ARPMonitor {
-	return &ARPMonitor{}
+func NewARPMonitor(probeTimeout time.Duration) *ARPMonitor {
+	return &ARPMonitor{probeTimeout: probeTimeout}
+}
+
+// CheckARPTable validates ARP table entries for given interfaces.
+// When probing is enabled and the gateway is not yet resolved, the gateway is
+// probed and the neighbor table re-read until it resolves or the probe times out.
+func (am *ARPMonitor) CheckARPTable(ctx context.Context, interfaces []string, gatewayIP net.IP) (*ARPTableStatus, error) {
+	return am.checkNeighbors(ctx, netlink.FAMILY_V4, interfaces, &net.IPAddr{IP: gatewayIP}, func() error {
+		return am.ProbeGateway(gatewayIP)
+	})
+}
+
+// CheckNDPTable is the IPv6 equivalent of CheckARPTable: it validates the
+// NDP neighbor entries for the given interfaces and whether the IPv6 gateway's
+// link-layer address has been resolved, probing it the same way.
+func (am *ARPMonitor) CheckNDPTable(ctx context.Context, interfaces []string, gateway *net.IPAddr) (*ARPTableStatus, error) {
+	if gateway == nil {
+		gateway = &net.IPAddr{}
+	}
+	return am.checkNeighbors(ctx, netlink.FAMILY_V6, interfaces, gateway, func() error {
+		return am.ProbeGateway6(gateway)
+	})
+}
+
+// checkNeighbors reads the neighbor table of one address family, probing the
+// gateway and re-reading until it resolves when probing is enabled
+func (am *ARPMonitor) checkNeighbors(ctx context.Context, family int, interfaces []string, gateway *net.IPAddr, probe func() error) (*ARPTableStatus, error) {
+	status, err := am.readNeighbors(family, interfaces, gateway)
+	if err != nil || gateway.IP == nil || status.GatewayResolved || am.probeTimeout <= 0 {
+		return status, err
+	}
+	
+	deadline := time.Now().Add(am.probeTimeout)
+	if err := probe(); err != nil {
+		return status, nil // Probe failures are reported as an unresolved gateway
+	}
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return status, nil
+		case <-time.After(50 * time.Millisecond):
+		}
+		
+		status, err = am.readNeighbors(family, interfaces, gateway)
+		if err != nil {
+			return nil, err
+		}
+		status.GatewayProbed = true
+		
+		if status.GatewayResolved || time.Now().After(deadline) {
+			return status, nil
+		}
+	}
 }
 
-// CheckARPTable validates ARP table entries for given interfaces
-func (am *ARPMonitor) CheckARPTable(interfaces []string, gatewayIP net.IP) (*ARPTableStatus, error) {
+// ProbeGateway sends a single UDP datagram toward the gateway, forcing the
+// kernel to issue an ARP request for it if no neighbor entry exists yet
+func (am *ARPMonitor) ProbeGateway(gatewayIP net.IP) error {
+	// Port 9 is the discard service; the payload is irrelevant, only the
+	// neighbor resolution triggered by sending it matters
+	conn, err := net.DialTimeout("udp4", net.JoinHostPort(gatewayIP.String(), "9"), am.probeTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to probe gateway %s: %w", gatewayIP, err)
+	}
+	defer conn.Close()
+	
+	conn.SetWriteDeadline(time.Now().Add(am.probeTimeout))
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return fmt.Errorf("failed to probe gateway %s: %w", gatewayIP, err)
+	}
+	
+	return nil
+}
+
+// ProbeGateway6 sends a single UDP datagram toward the IPv6 gateway, forcing
+// the kernel to issue a neighbor solicitation for it if no entry exists yet
+func (am *ARPMonitor) ProbeGateway6(gateway *net.IPAddr) error {
+	conn, err := net.DialTimeout("udp6", net.JoinHostPort(gateway.String(), "9"), am.probeTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to probe gateway %s: %w", gateway, err)
+	}
+	defer conn.Close()
+	
+	conn.SetWriteDeadline(time.Now().Add(am.probeTimeout))
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return fmt.Errorf("failed to probe gateway %s: %w", gateway, err)
+	}
+	
+	return nil
+}
+
+// readNeighbors reads the current neighbor table of one address family for
+// the given interfaces. A zoned (link-local) gateway only matches on its own interface.
+func (am *ARPMonitor) readNeighbors(family int, interfaces []string, gateway *net.IPAddr) (*ARPTableStatus, error) {
 	status := &ARPTableStatus{
 		InterfaceEntries: make(map[string]int),
 	}
 	
-	// Get all ARP entries
-	neighbors, err := netlink.NeighList(0, netlink.FAMILY_V4)
+	table := "ARP"
+	if family == netlink.FAMILY_V6 {
+		table = "NDP"
+	}
+	
+	// Get all neighbor entries
+	neighbors, err := netlink.NeighList(0, family)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ARP table: %w", err)
+		return nil, fmt.Errorf("failed to get %s table: %w", table, err)
 	}
 	
 	// Process ARP entries by interface
@@ -52,6 +152,7 @@ func (am *ARPMonitor) CheckARPTable(interfaces []string, gatewayIP net.IP) (*ARP
 		
 		interfaceIndex := link.Attrs().Index
 		entryCount := 0
+		gatewayHere := gateway.IP != nil && (gateway.Zone == "" || gateway.Zone == iface)
 		
 		for _, neighbor := range neighbors {
 			// Skip failed/incomplete entries
@@ -59,12 +160,17 @@ func (am *ARPMonitor) CheckARPTable(interfaces []string, gatewayIP net.IP) (*ARP
 				continue
 			}
 			
+			// Solicited-node and other multicast groups have static IPv6 entries
+			if family == netlink.FAMILY_V6 && neighbor.IP.IsMulticast() {
+				continue
+			}
+			
 			if neighbor.LinkIndex == interfaceIndex {
 				entryCount++
 				status.TotalEntries++
 				
 				// Check if this is the gateway
-				if gatewayIP != nil && neighbor.IP.Equal(gatewayIP) {
+				if gatewayHere && neighbor.IP.Equal(gateway.IP) {
 					status.GatewayResolved = true
 					status.GatewayMAC = neighbor.HardwareAddr
 				}
@@ -0,0 +1,143 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// ARP packet fields for Ethernet and IPv4
+const (
+	arpHardwareEthernet = 1
+	arpProtocolIPv4     = 0x0800
+	arpRequest          = 1
+	arpReply            = 2
+	arpPacketLen        = 28
+)
+
+// ARPReply is the answer to an ARP request
+type ARPReply struct {
+	MAC       net.HardwareAddr
+	Interface string
+	RTT       time.Duration
+}
+
+// ARPPing broadcasts an ARP request for ip on the interface the kernel routes
+// it through and waits up to timeout for the reply. Unlike ping it works when
+// the target filters ICMP. It needs root or CAP_NET_RAW.
+func ARPPing(ctx context.Context, ip net.IP, timeout time.Duration) (*ARPReply, error) {
+	ip = ip.To4()
+	if ip == nil {
+		return nil, errors.New("ARP only applies to IPv4 addresses")
+	}
+	
+	routes, err := netlink.RouteGet(ip)
+	if err != nil || len(routes) == 0 {
+		return nil, fmt.Errorf("no route to %s: %v", ip, err)
+	}
+	iface, err := net.InterfaceByIndex(routes[0].LinkIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up interface for %s: %w", ip, err)
+	}
+	src := routes[0].Src.To4()
+	if src == nil {
+		return nil, fmt.Errorf("no IPv4 source address on %s", iface.Name)
+	}
+	if len(iface.HardwareAddr) != 6 {
+		return nil, fmt.Errorf("%s has no Ethernet address", iface.Name)
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	proto := htons(syscall.ETH_P_ARP)
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, int(proto))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open packet socket (needs root or CAP_NET_RAW): %w", err)
+	}
+	// The file takes ownership of fd and makes reads honor deadlines
+	file := os.NewFile(uintptr(fd), "arp")
+	defer file.Close()
+	
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: proto, Ifindex: iface.Index}); err != nil {
+		return nil, fmt.Errorf("failed to bind to %s: %w", iface.Name, err)
+	}
+	
+	deadline, _ := ctx.Deadline()
+	file.SetReadDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { file.SetReadDeadline(time.Now()) })
+	defer stop()
+	
+	broadcast := &syscall.SockaddrLinklayer{Protocol: proto, Ifindex: iface.Index, Halen: 6}
+	copy(broadcast.Addr[:], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	
+	start := time.Now()
+	if err := syscall.Sendto(fd, arpRequestPacket(iface.HardwareAddr, src, ip), 0, broadcast); err != nil {
+		return nil, fmt.Errorf("failed to send ARP request on %s: %w", iface.Name, err)
+	}
+	
+	buf := make([]byte, 1500)
+	for {
+		n, err := file.Read(buf)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil, ctx.Err()
+			}
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil, fmt.Errorf("no ARP reply on %s within %s", iface.Name, timeout)
+			}
+			return nil, fmt.Errorf("failed to read ARP reply: %w", err)
+		}
+		
+		if mac := arpReplyFrom(buf[:n], ip, src); mac != nil {
+			return &ARPReply{
+				MAC:       mac,
+				Interface: iface.Name,
+				RTT:       time.Since(start).Round(time.Microsecond),
+			}, nil
+		}
+	}
+}
+
+// arpRequestPacket builds an ARP request asking who has target, from sender
+func arpRequestPacket(senderMAC net.HardwareAddr, sender, target net.IP) []byte {
+	packet := make([]byte, arpPacketLen)
+	binary.BigEndian.PutUint16(packet[0:], arpHardwareEthernet)
+	binary.BigEndian.PutUint16(packet[2:], arpProtocolIPv4)
+	packet[4] = 6
+	packet[5] = 4
+	binary.BigEndian.PutUint16(packet[6:], arpRequest)
+	copy(packet[8:], senderMAC)
+	copy(packet[14:], sender)
+	// Target hardware address stays zero
+	copy(packet[24:], target)
+	return packet
+}
+
+// arpReplyFrom returns the sender MAC of packet when it is target's reply to
+// our request, or nil otherwise
+func arpReplyFrom(packet []byte, target, sender net.IP) net.HardwareAddr {
+	if len(packet) < arpPacketLen ||
+		binary.BigEndian.Uint16(packet[0:]) != arpHardwareEthernet ||
+		binary.BigEndian.Uint16(packet[2:]) != arpProtocolIPv4 ||
+		binary.BigEndian.Uint16(packet[6:]) != arpReply {
+		return nil
+	}
+	if !bytes.Equal(packet[14:18], target) || !bytes.Equal(packet[24:28], sender) {
+		return nil
+	}
+	return net.HardwareAddr(append([]byte(nil), packet[8:14]...))
+}
+
+// htons converts a 16-bit value to network byte order
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
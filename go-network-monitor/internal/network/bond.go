@@ -0,0 +1,409 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+)
+
+// LACP port state bits of ad_actor_oper_port_state and
+// ad_partner_oper_port_state (IEEE 802.1AX)
+const (
+	lacpActivity        = 0x01
+	lacpTimeout         = 0x02
+	lacpAggregation     = 0x04
+	lacpSynchronization = 0x08
+	lacpCollecting      = 0x10
+	lacpDistributing    = 0x20
+	lacpDefaulted       = 0x40
+	lacpExpired         = 0x80
+)
+
+// lacpStateNames names the LACP port state bits in bit order
+var lacpStateNames = []struct {
+	bit  uint8
+	name string
+}{
+	{lacpActivity, "activity"},
+	{lacpTimeout, "short_timeout"},
+	{lacpAggregation, "aggregation"},
+	{lacpSynchronization, "synchronization"},
+	{lacpCollecting, "collecting"},
+	{lacpDistributing, "distributing"},
+	{lacpDefaulted, "defaulted"},
+	{lacpExpired, "expired"},
+}
+
+// lacpNegotiated are the bits a port needs before it carries traffic
+const lacpNegotiated = lacpSynchronization | lacpCollecting | lacpDistributing
+
+// LACPStateFlags names the bits set in an LACP port state
+func LACPStateFlags(state uint8) []string {
+	var flags []string
+	for _, s := range lacpStateNames {
+		if state&s.bit != 0 {
+			flags = append(flags, s.name)
+		}
+	}
+	return flags
+}
+
+// MissingLACPFlags names the synchronization, collecting and distributing
+// bits missing from an LACP port state
+func MissingLACPFlags(state uint8) []string {
+	return LACPStateFlags(^state & lacpNegotiated)
+}
+
+// SlaveStatus represents the state of one slave of a bond
+type SlaveStatus struct {
+	Name         string
+	MIIStatus    string  // "up", "down", "going down" or "going back"
+	State        string  // "active" or "backup"
+	Carrier      bool
+	Speed        int     // Negotiated speed in Mbps (-1 = unknown)
+	Duplex       string  // "full", "half" or "unknown"
+	LinkFailures int     // MII link failures since the slave was enslaved
+	AggregatorID int     // 802.3ad aggregator the slave is attached to (0 = none or not 802.3ad)
+	ActorState   uint8   // 802.3ad actor operational port state bits
+	PartnerState uint8   // 802.3ad partner operational port state bits, as last received
+	ActorChurn   string  // 802.3ad churn state: "none", "monitoring" or "churned" (empty = unknown)
+	PartnerChurn string
+}
+
+// Churned reports whether the actor or partner churn machine of the slave
+// has given up waiting for its port to synchronize, which during boot
+// usually means the switch side is misconfigured rather than still
+// negotiating
+func (bs *SlaveStatus) Churned() bool {
+	return bs.ActorChurn == "churned" || bs.PartnerChurn == "churned"
+}
+
+// PartnerDefaulted reports whether no LACPDUs have been received from the
+// partner, so its state is the administrative default rather than the switch's
+func (bs *SlaveStatus) PartnerDefaulted() bool {
+	return bs.ActorState&lacpDefaulted != 0
+}
+
+// CollectingDistributing reports whether the slave's LACP port is both
+// collecting and distributing, i.e. carrying traffic for the aggregate
+func (bs *SlaveStatus) CollectingDistributing() bool {
+	return bs.ActorState&(lacpCollecting|lacpDistributing) == lacpCollecting|lacpDistributing
+}
+
+// BondStatus represents the status of a bond interface
+type BondStatus struct {
+	Name           string
+	Mode           string  // Bonding mode as the kernel names it, e.g. "802.3ad" or "active-backup"
+	MIIStatus      string
+	ActiveSlave    string
+	SlaveCount     int     // Slaves with MII up
+	TotalSlaves    int
+	LACPComplete   bool    // 802.3ad only: every slave with MII up is aggregated
+	Aggregator     int     // Active 802.3ad aggregator ID (0 = not 802.3ad)
+	ActorSystem    string  // 802.3ad system MAC the bond advertises
+	ActorKey       int     // 802.3ad operational key of the active aggregator
+	PartnerSystem  string  // System MAC of the switch on the active aggregator (all zeroes = none)
+	PartnerKey     int
+	Slaves         []SlaveStatus
+}
+
+// IsBondInterface checks if an interface is a bond interface
+func (im *InterfaceMonitor) IsBondInterface(interfaceName string) bool {
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		return false
+	}
+	_, ok := link.(*netlink.Bond)
+	return ok
+}
+
+// CheckBondStatus checks the status of a bond interface and each of its
+// slaves from the bond and bond slave attributes reported over netlink,
+// which unlike /proc/net/bonding are available whatever the bonding module
+// was built with and inside containers
+func (im *InterfaceMonitor) CheckBondStatus(interfaceName string) (*BondStatus, error) {
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		return nil, fmt.Errorf("bond interface %s not found: %w", interfaceName, err)
+	}
+	bond, ok := link.(*netlink.Bond)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a bond interface", interfaceName)
+	}
+	
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slaves of %s: %w", interfaceName, err)
+	}
+	
+	var adInfo *netlink.BondAdInfo
+	if bond.Mode == netlink.BOND_MODE_802_3AD {
+		if adInfo, err = bondADInfo(bond.Index); err != nil {
+			return nil, fmt.Errorf("failed to read 802.3ad state of %s: %w", interfaceName, err)
+		}
+	}
+	
+	status := bondStatus(bond, links, adInfo)
+	if status.Mode == "802.3ad" {
+		readChurnStates(status)
+	}
+	return status, nil
+}
+
+// bondStatus builds the status of a bond from its netlink attributes, the
+// host's links (the bond's slaves among them) and, for an 802.3ad bond, its
+// active aggregator (nil when there is none yet)
+func bondStatus(bond *netlink.Bond, links []netlink.Link, adInfo *netlink.BondAdInfo) *BondStatus {
+	// A bond's carrier follows its slaves, so its operstate is the MII status
+	status := &BondStatus{
+		Name:      bond.Name,
+		Mode:      bond.Mode.String(),
+		MIIStatus: "down",
+	}
+	if bond.OperState == netlink.OperUp {
+		status.MIIStatus = "up"
+	}
+	
+	for _, slaveLink := range links {
+		attrs := slaveLink.Attrs()
+		bondSlave, ok := attrs.Slave.(*netlink.BondSlave)
+		if attrs.MasterIndex != bond.Index || !ok {
+			continue
+		}
+		if attrs.Index == bond.ActiveSlave {
+			status.ActiveSlave = attrs.Name
+		}
+		slave := slaveStatus(attrs, bondSlave)
+		status.TotalSlaves++
+		if slave.MIIStatus == "up" {
+			status.SlaveCount++
+		}
+		status.Slaves = append(status.Slaves, slave)
+	}
+	
+	if status.Mode == "802.3ad" {
+		if bond.AdActorSystem != nil {
+			status.ActorSystem = bond.AdActorSystem.String()
+		}
+		if adInfo != nil {
+			status.Aggregator = adInfo.AggregatorId
+			status.ActorKey = adInfo.ActorKey
+			status.PartnerKey = adInfo.PartnerKey
+			status.PartnerSystem = adInfo.PartnerMac.String()
+		}
+		status.LACPComplete = lacpComplete(status)
+	}
+	
+	return status
+}
+
+// readChurnStates fills in the churn state of each slave of an 802.3ad bond.
+// Netlink and sysfs don't report churn, so this is the one place
+// /proc/net/bonding is read; when it's absent the churn state stays unknown.
+func readChurnStates(status *BondStatus) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/net/bonding/%s", status.Name))
+	if err != nil {
+		return
+	}
+	parseChurnStates(status, string(data))
+}
+
+// parseChurnStates applies the churn states in the /proc/net/bonding text of
+// a bond to its slaves
+func parseChurnStates(status *BondStatus, data string) {
+	var slave *SlaveStatus
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Slave Interface":
+			slave = nil
+			for i := range status.Slaves {
+				if status.Slaves[i].Name == value {
+					slave = &status.Slaves[i]
+				}
+			}
+		case "Actor Churn State":
+			if slave != nil {
+				slave.ActorChurn = value
+			}
+		case "Partner Churn State":
+			if slave != nil {
+				slave.PartnerChurn = value
+			}
+		}
+	}
+}
+
+// slaveStatus converts the netlink bond slave attributes of a slave
+// interface; the 802.3ad attributes are only reported in 802.3ad mode and
+// are left zero otherwise
+func slaveStatus(attrs *netlink.LinkAttrs, bondSlave *netlink.BondSlave) SlaveStatus {
+	// MII status reads as "UP" or "GOING_DOWN", state as "ACTIVE" or "BACKUP"
+	slave := SlaveStatus{
+		Name:         attrs.Name,
+		MIIStatus:    strings.ToLower(strings.ReplaceAll(bondSlave.MiiStatus.String(), "_", " ")),
+		State:        strings.ToLower(bondSlave.State.String()),
+		Carrier:      attrs.OperState == netlink.OperUp,
+		LinkFailures: int(bondSlave.LinkFailureCount),
+		AggregatorID: int(bondSlave.AggregatorId),
+		ActorState:   bondSlave.AdActorOperPortState,
+		PartnerState: uint8(bondSlave.AdPartnerOperPortState),
+	}
+	slave.Speed, slave.Duplex = linkSpeed(attrs.Name)
+	return slave
+}
+
+// bondADInfo reads the active aggregator of an 802.3ad bond from its
+// IFLA_BOND_AD_INFO attribute, which netlink.Bond doesn't decode. It returns
+// nil when the bond has no active aggregator yet.
+func bondADInfo(index int) (*netlink.BondAdInfo, error) {
+	req := nl.NewNetlinkRequest(syscall.RTM_GETLINK, syscall.NLM_F_ACK)
+	msg := nl.NewIfInfomsg(syscall.AF_UNSPEC)
+	msg.Index = int32(index)
+	req.AddData(msg)
+	msgs, err := req.Execute(syscall.NETLINK_ROUTE, syscall.RTM_NEWLINK)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 || len(msgs[0]) < syscall.SizeofIfInfomsg {
+		return nil, fmt.Errorf("empty link reply")
+	}
+	
+	// IFLA_LINKINFO > IFLA_INFO_DATA > IFLA_BOND_AD_INFO
+	attrs, err := nl.ParseRouteAttr(msgs[0][syscall.SizeofIfInfomsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, nested := range []uint16{syscall.IFLA_LINKINFO, nl.IFLA_INFO_DATA, nl.IFLA_BOND_AD_INFO} {
+		value, ok := findAttr(attrs, nested)
+		if !ok {
+			return nil, nil
+		}
+		if attrs, err = nl.ParseRouteAttr(value); err != nil {
+			return nil, err
+		}
+	}
+	
+	native := nl.NativeEndian()
+	adInfo := &netlink.BondAdInfo{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nlaTypeMask {
+		case nl.IFLA_BOND_AD_INFO_AGGREGATOR:
+			adInfo.AggregatorId = int(native.Uint16(attr.Value))
+		case nl.IFLA_BOND_AD_INFO_NUM_PORTS:
+			adInfo.NumPorts = int(native.Uint16(attr.Value))
+		case nl.IFLA_BOND_AD_INFO_ACTOR_KEY:
+			adInfo.ActorKey = int(native.Uint16(attr.Value))
+		case nl.IFLA_BOND_AD_INFO_PARTNER_KEY:
+			adInfo.PartnerKey = int(native.Uint16(attr.Value))
+		case nl.IFLA_BOND_AD_INFO_PARTNER_MAC:
+			adInfo.PartnerMac = net.HardwareAddr(attr.Value)
+		}
+	}
+	return adInfo, nil
+}
+
+// findAttr returns the value of the first attribute of a type
+func findAttr(attrs []syscall.NetlinkRouteAttr, attrType uint16) ([]byte, bool) {
+	for _, attr := range attrs {
+		if attr.Attr.Type&nlaTypeMask == attrType {
+			return attr.Value, true
+		}
+	}
+	return nil, false
+}
+
+// ModeReady applies the readiness rule of the bond's mode and describes the
+// outcome:
+//   - active-backup: the active slave has MII up
+//   - 802.3ad: LACP negotiation is complete on every slave with MII up
+//   - balance-alb and balance-tlb: every slave has MII up, since peers are
+//     assigned to slaves and a dead slave strands them
+//   - balance-rr, balance-xor and broadcast: any slave has MII up
+func (bs *BondStatus) ModeReady() (bool, string) {
+	switch bs.Mode {
+	case "active-backup":
+		if bs.ActiveSlave == "" {
+			return false, "no active slave"
+		}
+		for _, slave := range bs.Slaves {
+			if slave.Name == bs.ActiveSlave && slave.MIIStatus == "up" {
+				return true, fmt.Sprintf("active slave %s up", bs.ActiveSlave)
+			}
+		}
+		return false, fmt.Sprintf("active slave %s not up", bs.ActiveSlave)
+	case "802.3ad":
+		if bs.LACPComplete {
+			return true, fmt.Sprintf("%d slaves aggregated", bs.UsableSlaves())
+		}
+		return false, fmt.Sprintf("LACP negotiation incomplete (%d/%d slaves aggregated)", bs.UsableSlaves(), bs.TotalSlaves)
+	case "balance-alb", "balance-tlb":
+		if bs.TotalSlaves > 0 && bs.SlaveCount == bs.TotalSlaves {
+			return true, fmt.Sprintf("all %d slaves up", bs.TotalSlaves)
+		}
+		return false, fmt.Sprintf("%d/%d slaves up, all required", bs.SlaveCount, bs.TotalSlaves)
+	default:
+		if bs.SlaveCount > 0 {
+			return true, fmt.Sprintf("%d/%d slaves up", bs.SlaveCount, bs.TotalSlaves)
+		}
+		return false, "no slaves up"
+	}
+}
+
+// MixedSpeeds reports whether the slaves with a known speed negotiated
+// different speeds, e.g. one 1G link in a pair of 25G links
+func (bs *BondStatus) MixedSpeeds() bool {
+	speed := 0
+	for _, slave := range bs.Slaves {
+		if slave.Speed <= 0 {
+			continue
+		}
+		if speed != 0 && slave.Speed != speed {
+			return true
+		}
+		speed = slave.Speed
+	}
+	return false
+}
+
+// UsableSlaves counts the slaves carrying traffic: MII up and, in 802.3ad
+// mode, collecting and distributing in the active aggregator
+func (bs *BondStatus) UsableSlaves() int {
+	usable := 0
+	for _, slave := range bs.Slaves {
+		if slave.MIIStatus != "up" {
+			continue
+		}
+		if bs.Mode == "802.3ad" && (slave.AggregatorID != bs.Aggregator || !slave.CollectingDistributing()) {
+			continue
+		}
+		usable++
+	}
+	return usable
+}
+
+// lacpComplete reports whether every slave with MII up has joined the bond's
+// active aggregator and is collecting and distributing, with at least one
+// such slave; slaves with MII down can't negotiate and are left out
+func lacpComplete(status *BondStatus) bool {
+	negotiated := 0
+	for _, slave := range status.Slaves {
+		if slave.MIIStatus != "up" {
+			continue
+		}
+		if slave.AggregatorID != status.Aggregator || !slave.CollectingDistributing() {
+			return false
+		}
+		negotiated++
+	}
+	return negotiated > 0
+}
@@ -0,0 +1,220 @@
+package network
+
+import (
+	"net"
+	"testing"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// LACP port states as reported for a port carrying traffic (0x3d) and for
+// one whose partner never sent an LACPDU (0x45)
+const (
+	stateAggregated = lacpActivity | lacpAggregation | lacpSynchronization | lacpCollecting | lacpDistributing
+	stateDefaulted  = lacpActivity | lacpAggregation | lacpDefaulted
+)
+
+// fixtureBond returns a bond link as netlink reports it
+func fixtureBond(index int, mode netlink.BondMode, activeSlave int) *netlink.Bond {
+	bond := netlink.NewLinkBond(netlink.LinkAttrs{Name: "bond0", Index: index, OperState: netlink.OperUp})
+	bond.Mode = mode
+	bond.ActiveSlave = activeSlave
+	if mode == netlink.BOND_MODE_802_3AD {
+		bond.AdActorSystem, _ = net.ParseMAC("52:54:00:12:34:56")
+	}
+	return bond
+}
+
+// fixtureSlave returns a slave link of the bond with ifindex master
+func fixtureSlave(name string, index, master int, slave *netlink.BondSlave) netlink.Link {
+	operState := netlink.LinkOperState(netlink.OperDown)
+	if slave.MiiStatus == netlink.BondLinkUp {
+		operState = netlink.OperUp
+	}
+	return &netlink.Device{LinkAttrs: netlink.LinkAttrs{
+		Name:        name,
+		Index:       index,
+		MasterIndex: master,
+		OperState:   operState,
+		Slave:       slave,
+	}}
+}
+
+func TestBondStatusActiveBackup(t *testing.T) {
+	bond := fixtureBond(4, netlink.BOND_MODE_ACTIVE_BACKUP, 2)
+	links := []netlink.Link{
+		&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "lo", Index: 1}},
+		fixtureSlave("eno1", 2, 4, &netlink.BondSlave{State: netlink.BondStateActive, MiiStatus: netlink.BondLinkUp}),
+		fixtureSlave("eno2", 3, 4, &netlink.BondSlave{State: netlink.BondStateBackup, MiiStatus: netlink.BondLinkUp, LinkFailureCount: 1}),
+		bond,
+	}
+	
+	status := bondStatus(bond, links, nil)
+	if status.Mode != "active-backup" || status.MIIStatus != "up" {
+		t.Fatalf("mode %q, MII %q; want active-backup, up", status.Mode, status.MIIStatus)
+	}
+	if status.ActiveSlave != "eno1" {
+		t.Errorf("active slave %q, want eno1", status.ActiveSlave)
+	}
+	if status.TotalSlaves != 2 || status.SlaveCount != 2 {
+		t.Errorf("%d/%d slaves up, want 2/2", status.SlaveCount, status.TotalSlaves)
+	}
+	if status.Slaves[1].State != "backup" || status.Slaves[1].LinkFailures != 1 {
+		t.Errorf("eno2 state %q with %d link failures, want backup with 1", status.Slaves[1].State, status.Slaves[1].LinkFailures)
+	}
+	if ready, reason := status.ModeReady(); !ready || reason != "active slave eno1 up" {
+		t.Errorf("ModeReady() = %t, %q; want true, \"active slave eno1 up\"", ready, reason)
+	}
+	
+	// The active slave losing MII leaves the bond unusable until failover
+	status.Slaves[0].MIIStatus = "down"
+	if ready, reason := status.ModeReady(); ready || reason != "active slave eno1 not up" {
+		t.Errorf("ModeReady() with the active slave down = %t, %q; want false, \"active slave eno1 not up\"", ready, reason)
+	}
+}
+
+func TestBondStatusLACP(t *testing.T) {
+	partner, _ := net.ParseMAC("00:1c:73:aa:bb:cc")
+	tests := []struct {
+		name     string
+		slaves   []*netlink.BondSlave
+		adInfo   *netlink.BondAdInfo
+		complete bool
+		usable   int
+		reason   string
+	}{
+		{
+			name: "complete",
+			slaves: []*netlink.BondSlave{
+				{MiiStatus: netlink.BondLinkUp, AggregatorId: 1, AdActorOperPortState: stateAggregated, AdPartnerOperPortState: stateAggregated},
+				{MiiStatus: netlink.BondLinkUp, AggregatorId: 1, AdActorOperPortState: stateAggregated, AdPartnerOperPortState: stateAggregated},
+			},
+			adInfo:   &netlink.BondAdInfo{AggregatorId: 1, NumPorts: 2, ActorKey: 15, PartnerKey: 32769, PartnerMac: partner},
+			complete: true,
+			usable:   2,
+			reason:   "2 slaves aggregated",
+		},
+		{
+			// eno2 is cabled to a switch port without LACP configured, so it
+			// sits defaulted in an aggregator of its own
+			name: "partial",
+			slaves: []*netlink.BondSlave{
+				{MiiStatus: netlink.BondLinkUp, AggregatorId: 1, AdActorOperPortState: stateAggregated, AdPartnerOperPortState: stateAggregated},
+				{MiiStatus: netlink.BondLinkUp, AggregatorId: 2, AdActorOperPortState: stateDefaulted, AdPartnerOperPortState: lacpActivity},
+			},
+			adInfo:   &netlink.BondAdInfo{AggregatorId: 1, NumPorts: 1, ActorKey: 15, PartnerKey: 32769, PartnerMac: partner},
+			complete: false,
+			usable:   1,
+			reason:   "LACP negotiation incomplete (1/2 slaves aggregated)",
+		},
+		{
+			// A slave with MII down can't negotiate and doesn't hold the bond back
+			name: "slave down",
+			slaves: []*netlink.BondSlave{
+				{MiiStatus: netlink.BondLinkUp, AggregatorId: 1, AdActorOperPortState: stateAggregated, AdPartnerOperPortState: stateAggregated},
+				{MiiStatus: netlink.BondLinkDown, AggregatorId: 2, AdActorOperPortState: stateDefaulted},
+			},
+			adInfo:   &netlink.BondAdInfo{AggregatorId: 1, NumPorts: 1, ActorKey: 15, PartnerKey: 32769, PartnerMac: partner},
+			complete: true,
+			usable:   1,
+			reason:   "1 slaves aggregated",
+		},
+		{
+			name: "no aggregator",
+			slaves: []*netlink.BondSlave{
+				{MiiStatus: netlink.BondLinkUp, AdActorOperPortState: stateDefaulted},
+			},
+			complete: false,
+			usable:   0,
+			reason:   "LACP negotiation incomplete (0/1 slaves aggregated)",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bond := fixtureBond(5, netlink.BOND_MODE_802_3AD, 0)
+			links := []netlink.Link{bond}
+			for i, slave := range tt.slaves {
+				links = append(links, fixtureSlave([]string{"eno1", "eno2"}[i], 2+i, 5, slave))
+			}
+			
+			status := bondStatus(bond, links, tt.adInfo)
+			if status.LACPComplete != tt.complete {
+				t.Errorf("LACPComplete = %t, want %t", status.LACPComplete, tt.complete)
+			}
+			if got := lacpComplete(status); got != tt.complete {
+				t.Errorf("lacpComplete() = %t, want %t", got, tt.complete)
+			}
+			if got := status.UsableSlaves(); got != tt.usable {
+				t.Errorf("UsableSlaves() = %d, want %d", got, tt.usable)
+			}
+			if ready, reason := status.ModeReady(); ready != tt.complete || reason != tt.reason {
+				t.Errorf("ModeReady() = %t, %q; want %t, %q", ready, reason, tt.complete, tt.reason)
+			}
+			if tt.adInfo != nil && (status.PartnerSystem != partner.String() || status.Aggregator != tt.adInfo.AggregatorId) {
+				t.Errorf("partner %s on aggregator %d, want %s on %d", status.PartnerSystem, status.Aggregator, partner, tt.adInfo.AggregatorId)
+			}
+		})
+	}
+}
+
+func TestBondStatusIgnoresOtherLinks(t *testing.T) {
+	bond := fixtureBond(4, netlink.BOND_MODE_ACTIVE_BACKUP, 2)
+	links := []netlink.Link{
+		fixtureSlave("eno1", 2, 4, &netlink.BondSlave{State: netlink.BondStateActive, MiiStatus: netlink.BondLinkUp}),
+		// Enslaved to a different bond
+		fixtureSlave("eno3", 6, 7, &netlink.BondSlave{State: netlink.BondStateActive, MiiStatus: netlink.BondLinkUp}),
+		// A bridge port of the bond's master index would have no bond slave attributes
+		&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "tap0", Index: 8, MasterIndex: 4}},
+	}
+	
+	status := bondStatus(bond, links, nil)
+	if status.TotalSlaves != 1 || status.Slaves[0].Name != "eno1" {
+		t.Errorf("slaves %+v, want only eno1", status.Slaves)
+	}
+}
+
+func TestParseChurnStates(t *testing.T) {
+	// Trimmed /proc/net/bonding/bond0 of a bond with one port churned
+	const proc = `Ethernet Channel Bonding Driver: v5.15.0
+
+Bonding Mode: IEEE 802.3ad Dynamic link aggregation
+MII Status: up
+
+Slave Interface: eno1
+MII Status: up
+Aggregator ID: 1
+Actor Churn State: none
+Partner Churn State: none
+
+Slave Interface: eno2
+MII Status: up
+Aggregator ID: 2
+Actor Churn State: churned
+Partner Churn State: churned
+`
+	status := &BondStatus{Name: "bond0", Slaves: []SlaveStatus{{Name: "eno1"}, {Name: "eno2"}}}
+	parseChurnStates(status, proc)
+	
+	if status.Slaves[0].ActorChurn != "none" || status.Slaves[0].Churned() {
+		t.Errorf("eno1 churn %q/%q, want none", status.Slaves[0].ActorChurn, status.Slaves[0].PartnerChurn)
+	}
+	if !status.Slaves[1].Churned() {
+		t.Errorf("eno2 churn %q/%q, want churned", status.Slaves[1].ActorChurn, status.Slaves[1].PartnerChurn)
+	}
+}
+
+func TestLACPFlags(t *testing.T) {
+	if got := MissingLACPFlags(stateDefaulted); len(got) != 3 {
+		t.Errorf("MissingLACPFlags(0x%02x) = %v, want synchronization, collecting and distributing", stateDefaulted, got)
+	}
+	if got := MissingLACPFlags(stateAggregated); len(got) != 0 {
+		t.Errorf("MissingLACPFlags(0x%02x) = %v, want none", stateAggregated, got)
+	}
+	
+	slave := SlaveStatus{ActorState: stateDefaulted}
+	if !slave.PartnerDefaulted() || slave.CollectingDistributing() {
+		t.Errorf("state 0x%02x: defaulted %t, collecting/distributing %t; want true, false",
+			stateDefaulted, slave.PartnerDefaulted(), slave.CollectingDistributing())
+	}
+}
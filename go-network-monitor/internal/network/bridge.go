@@ -0,0 +1,98 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// bridgePortStates names the spanning tree states in brport/state
+var bridgePortStates = map[string]string{
+	"0": "disabled",
+	"1": "listening",
+	"2": "learning",
+	"3": "forwarding",
+	"4": "blocking",
+}
+
+// BridgePort is a port of a bridge and its spanning tree state
+type BridgePort struct {
+	Name  string
+	State string  // "disabled", "listening", "learning", "forwarding" or "blocking"
+}
+
+// BridgeStatus represents the status of a bridge interface
+type BridgeStatus struct {
+	Name            string
+	STP             string         // "off", "kernel" or "user" (mstpd/rstpd)
+	ForwardDelay    time.Duration  // Time a port spends listening and again learning under STP
+	Ports           []BridgePort
+	ForwardingPorts int
+}
+
+// IsBridgeInterface checks if an interface is a Linux bridge
+func (im *InterfaceMonitor) IsBridgeInterface(interfaceName string) bool {
+	link, err := netlink.LinkByName(interfaceName)
+	return err == nil && link.Type() == "bridge"
+}
+
+// CheckBridgeStatus checks the STP mode of a bridge and the state of each of
+// its ports; a bridge only passes traffic once a port is forwarding, which
+// with STP enabled takes twice the forward delay after the port comes up
+func (im *InterfaceMonitor) CheckBridgeStatus(interfaceName string) (*BridgeStatus, error) {
+	bridge, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		return nil, fmt.Errorf("bridge interface %s not found: %w", interfaceName, err)
+	}
+	if bridge.Type() != "bridge" {
+		return nil, fmt.Errorf("%s is not a bridge", interfaceName)
+	}
+	
+	status := &BridgeStatus{Name: interfaceName, STP: "off"}
+	bridgePath := fmt.Sprintf("/sys/class/net/%s/bridge", interfaceName)
+	switch readSysfs(bridgePath + "/stp_state") {
+	case "1":
+		status.STP = "kernel"
+	case "2":
+		status.STP = "user"
+	}
+	if delay, err := strconv.Atoi(readSysfs(bridgePath + "/forward_delay")); err == nil {
+		status.ForwardDelay = time.Duration(delay) * 10 * time.Millisecond // Centiseconds
+	}
+	
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+	for _, link := range links {
+		if link.Attrs().MasterIndex != bridge.Attrs().Index {
+			continue
+		}
+		
+		name := link.Attrs().Name
+		state, ok := bridgePortStates[readSysfs(fmt.Sprintf("/sys/class/net/%s/brport/state", name))]
+		if !ok {
+			state = "unknown"
+		}
+		status.Ports = append(status.Ports, BridgePort{Name: name, State: state})
+		if state == "forwarding" {
+			status.ForwardingPorts++
+		}
+	}
+	
+	return status, nil
+}
+
+// readSysfs returns the trimmed contents of a sysfs attribute, or "" when it
+// can't be read
+func readSysfs(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -1,9 +1,12 @@
 package network
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -11,82 +14,554 @@ import (
 	"github.com/vishvananda/netlink"
 )
 
+// resolvConfPath is where the system resolver's nameservers are configured
+const resolvConfPath = "/etc/resolv.conf"
+
+// resolvedUpstreamPath lists the upstream nameservers systemd-resolved forwards to
+const resolvedUpstreamPath = "/run/systemd/resolve/resolv.conf"
+
 // ConnectivityChecker handles network connectivity tests
 type ConnectivityChecker struct {
 	pingTimeout time.Duration
 	dnsTimeout  time.Duration
+	nmTimeout   time.Duration
+	routeTable  int
 }
 
 // NewConnectivityChecker creates a new connectivity checker
-func NewConnectivityChecker(pingTimeout, dnsTimeout time.Duration) *ConnectivityChecker {
+func NewConnectivityChecker(pingTimeout, dnsTimeout, nmTimeout time.Duration, routeTable int) *ConnectivityChecker {
 	return &ConnectivityChecker{
 		pingTimeout: pingTimeout,
 		dnsTimeout:  dnsTimeout,
+		nmTimeout:   nmTimeout,
+		routeTable:  routeTable,
 	}
 }
 
-// GetDefaultGateway returns the default gateway IP address
+// NextHop is one path of a default route
+type NextHop struct {
+	Gateway   net.IP
+	Interface string
+	Weight    int   // Relative share of flows hashed to this path
+	OnLink    bool  // Installed with the onlink flag, so the gateway needn't be in a connected subnet
+}
+
+// GetDefaultGateway returns the default gateway IP address; for an ECMP
+// default route this is the gateway of its first path
 func (cc *ConnectivityChecker) GetDefaultGateway() (net.IP, error) {
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	hops, err := cc.GetDefaultNextHops()
+	if err != nil {
+		return nil, err
+	}
+	return hops[0].Gateway, nil
+}
+
+// GetDefaultNextHops returns the next hops of the IPv4 default route: a
+// single one for an ordinary route, or one per path of an ECMP (multipath)
+// route, across which the kernel hashes flows
+func (cc *ConnectivityChecker) GetDefaultNextHops() ([]NextHop, error) {
+	routes, err := listRoutes(cc.routeTable, netlink.FAMILY_V4)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list routes: %w", err)
 	}
 	
 	for _, route := range routes {
 		// Look for default route (destination 0.0.0.0/0)
-		if route.Dst == nil && route.Gw != nil {
-			return route.Gw, nil
+		if route.Dst != nil {
+			continue
+		}
+		if route.Gw != nil {
+			return []NextHop{{
+				Gateway:   route.Gw,
+				Interface: linkName(route.LinkIndex),
+				Weight:    1,
+				OnLink:    route.Flags&int(netlink.FLAG_ONLINK) != 0,
+			}}, nil
+		}
+		
+		// ECMP default routes carry their gateways as nexthops
+		var hops []NextHop
+		for _, hop := range multipathHops(route) {
+			if hop.Gateway != nil {
+				hops = append(hops, hop)
+			}
+		}
+		if len(hops) > 0 {
+			return hops, nil
 		}
 	}
 	
 	return nil, fmt.Errorf("no default gateway found")
 }
 
-// CheckGatewayReachability tests if the default gateway is reachable via ping
-func (cc *ConnectivityChecker) CheckGatewayReachability(gateway net.IP) error {
-	if gateway == nil {
-		return fmt.Errorf("no gateway provided")
+// OnLinkReason explains how the gateway of a next hop is directly reachable
+// on its interface: the onlink flag, a connected subnet or point-to-point
+// peer of the interface, or a link-scope route through the interface such as
+// the host route DHCP clients add for a gateway outside the leased subnet.
+// It returns "" when the gateway isn't on-link, and can't be reached at all.
+func (cc *ConnectivityChecker) OnLinkReason(hop NextHop) (string, error) {
+	if hop.OnLink {
+		return "onlink flag", nil
+	}
+	
+	link, err := netlink.LinkByName(hop.Interface)
+	if err != nil {
+		return "", fmt.Errorf("interface %s not found: %w", hop.Interface, err)
+	}
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_V4)
+	if err != nil {
+		return "", fmt.Errorf("failed to list addresses of %s: %w", hop.Interface, err)
+	}
+	for _, addr := range addrs {
+		if addr.Peer != nil && addr.Peer.Contains(hop.Gateway) {
+			return fmt.Sprintf("peer %s", addr.Peer), nil
+		}
+		if addr.IPNet.Contains(hop.Gateway) {
+			subnet := &net.IPNet{IP: addr.IP.Mask(addr.Mask), Mask: addr.Mask}
+			return fmt.Sprintf("connected subnet %s", subnet), nil
+		}
+	}
+	
+	routes, err := listRoutes(cc.routeTable, netlink.FAMILY_V4)
+	if err != nil {
+		return "", fmt.Errorf("failed to list routes: %w", err)
+	}
+	for _, route := range routes {
+		if route.LinkIndex == link.Attrs().Index && route.Gw == nil && route.Scope == netlink.SCOPE_LINK &&
+			route.Dst != nil && route.Dst.Contains(hop.Gateway) {
+			return fmt.Sprintf("link route %s", route.Dst), nil
+		}
+	}
+	return "", nil
+}
+
+// linkName returns the name of the interface with the given index, or "" if
+// there is none
+func linkName(index int) string {
+	if index > 0 {
+		if link, err := netlink.LinkByIndex(index); err == nil {
+			return link.Attrs().Name
+		}
+	}
+	return ""
+}
+
+// RouteInterface returns the name and MTU of the interface the kernel routes
+// traffic for ip through
+func (cc *ConnectivityChecker) RouteInterface(ip net.IP) (string, int, error) {
+	routes, err := netlink.RouteGet(ip)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to look up route to %s: %w", ip, err)
+	}
+	if len(routes) == 0 {
+		return "", 0, fmt.Errorf("no route to %s", ip)
 	}
 	
-	ctx, cancel := context.WithTimeout(context.Background(), cc.pingTimeout)
+	link, err := netlink.LinkByIndex(routes[0].LinkIndex)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to look up interface of route to %s: %w", ip, err)
+	}
+	return link.Attrs().Name, link.Attrs().MTU, nil
+}
+
+// PrimaryAddresses returns the source addresses the kernel picks for traffic
+// leaving through the default routes, IPv4 first; these are the addresses
+// other hosts see this one by
+func (cc *ConnectivityChecker) PrimaryAddresses() []net.IP {
+	var addrs []net.IP
+	for _, dst := range []net.IP{net.IPv4(192, 0, 2, 1), net.ParseIP("2001:db8::1")} {
+		routes, err := netlink.RouteGet(dst)
+		if err != nil || len(routes) == 0 || routes[0].Src == nil {
+			continue // No default route for this family
+		}
+		if src := routes[0].Src; src.IsGlobalUnicast() {
+			addrs = append(addrs, src)
+		}
+	}
+	return addrs
+}
+
+// ReverseLookup returns the PTR names of ip within the DNS timeout
+func (cc *ConnectivityChecker) ReverseLookup(ctx context.Context, ip net.IP) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cc.dnsTimeout)
 	defer cancel()
 	
-	// Use ping command with specific timeout
-	cmd := exec.CommandContext(ctx, "ping", "-c", "1", "-W", "1", gateway.String())
-	output, err := cmd.CombinedOutput()
+	resolver := &net.Resolver{}
+	names, err := resolver.LookupAddr(ctx, ip.String())
+	if err != nil {
+		return nil, fmt.Errorf("reverse lookup of %s failed: %w", ip, err)
+	}
+	return names, nil
+}
+
+// ForwardConfirms reports whether name resolves back to ip, as Kerberos and
+// most clustered services expect of a host's PTR record
+func (cc *ConnectivityChecker) ForwardConfirms(ctx context.Context, name string, ip net.IP) bool {
+	ctx, cancel := context.WithTimeout(ctx, cc.dnsTimeout)
+	defer cancel()
 	
+	resolver := &net.Resolver{}
+	addrs, err := resolver.LookupIP(ctx, "ip", name)
 	if err != nil {
-		return fmt.Errorf("ping failed: %s", strings.TrimSpace(string(output)))
+		return false
+	}
+	for _, addr := range addrs {
+		if addr.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveHostname looks name up the way `hostname -f` does and returns its
+// addresses and canonical name within the DNS timeout. An /etc/hosts entry
+// answers first, as the files source does in nsswitch, with the first name
+// on its line as the canonical name; otherwise the canonical name of the
+// resolver's host lookup is used (the search domain or CNAME target it
+// settled on) and its addresses are those of that name.
+func (cc *ConnectivityChecker) ResolveHostname(ctx context.Context, name string) ([]net.IP, string, error) {
+	if addrs, canonical := lookupHostsFile(hostsPath, name); len(addrs) > 0 {
+		return addrs, canonical, nil
 	}
 	
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, cc.dnsTimeout)
+	defer cancel()
+	
+	resolver := &net.Resolver{}
+	canonical, err := resolver.LookupCNAME(ctx, name)
+	if err != nil {
+		return nil, "", fmt.Errorf("lookup of %s failed: %w", name, err)
+	}
+	canonical = strings.TrimSuffix(canonical, ".")
+	addrs, err := resolver.LookupIP(ctx, "ip", canonical)
+	if err != nil {
+		return nil, "", fmt.Errorf("lookup of %s (%s) failed: %w", name, canonical, err)
+	}
+	return addrs, canonical, nil
 }
 
-// CheckDNSResolution tests DNS resolution for a given hostname
-func (cc *ConnectivityChecker) CheckDNSResolution(hostname string) error {
+// hostsPath is the static host table consulted before DNS
+const hostsPath = "/etc/hosts"
+
+// lookupHostsFile returns the addresses of every line of a hosts file naming
+// host, and the canonical (first) name of the first such line
+func lookupHostsFile(path, host string) ([]net.IP, string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, ""
+	}
+	defer file.Close()
+	
+	var addrs []net.IP
+	var canonical string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			continue
+		}
+		for _, alias := range fields[1:] {
+			if strings.EqualFold(alias, host) {
+				addrs = append(addrs, ip)
+				if canonical == "" {
+					canonical = fields[1]
+				}
+				break
+			}
+		}
+	}
+	return addrs, canonical
+}
+
+// IsLocalAddress reports whether ip is assigned to one of this host's
+// interfaces; any loopback address counts, as lo answers for all of 127/8
+func IsLocalAddress(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return true
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDefaultGateway6 returns the IPv6 default gateway. Router advertisements
+// usually install a link-local gateway, so the address is scoped to the
+// route's interface.
+func (cc *ConnectivityChecker) GetDefaultGateway6() (*net.IPAddr, error) {
+	routes, err := listRoutes(cc.routeTable, netlink.FAMILY_V6)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IPv6 routes: %w", err)
+	}
+	
+	for _, route := range routes {
+		// Look for default route (destination ::/0)
+		if route.Dst != nil {
+			continue
+		}
+		
+		gateway, linkIndex := route.Gw, route.LinkIndex
+		if gateway == nil && len(route.MultiPath) > 0 {
+			// ECMP default routes carry their gateways as nexthops
+			gateway, linkIndex = route.MultiPath[0].Gw, route.MultiPath[0].LinkIndex
+		}
+		if gateway == nil {
+			continue
+		}
+		
+		addr := &net.IPAddr{IP: gateway}
+		if gateway.IsLinkLocalUnicast() && linkIndex > 0 {
+			if link, err := netlink.LinkByIndex(linkIndex); err == nil {
+				addr.Zone = link.Attrs().Name
+			}
+		}
+		return addr, nil
+	}
+	
+	return nil, fmt.Errorf("no IPv6 default gateway found")
+}
+
+// PingTarget sends count echo requests to addr, each waiting up to the ping
+// timeout for its reply
+func (cc *ConnectivityChecker) PingTarget(ctx context.Context, addr *net.IPAddr, count int) *PingStats {
+	return PingSeries(ctx, addr, count, cc.pingTimeout)
+}
+
+// TraceHop probes the host ttl hops toward addr, waiting up to the ping
+// timeout for its response
+func (cc *ConnectivityChecker) TraceHop(ctx context.Context, addr *net.IPAddr, ttl int) (*Hop, error) {
+	return TraceHop(ctx, addr, ttl, cc.pingTimeout)
+}
+
+// CheckTCP connects to a host:port endpoint, resolving the host within the
+// same timeout, and returns how long the connection took to establish
+func (cc *ConnectivityChecker) CheckTCP(ctx context.Context, endpoint string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	start := time.Now()
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	
+	return time.Since(start).Round(time.Microsecond), nil
+}
+
+// ResolveTarget returns the IP address for a ping target, resolving
+// hostnames within the DNS timeout
+func (cc *ConnectivityChecker) ResolveTarget(ctx context.Context, target string) (net.IP, error) {
+	if ip := net.ParseIP(target); ip != nil {
+		return ip, nil
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, cc.dnsTimeout)
+	defer cancel()
+	
+	resolver := &net.Resolver{}
+	addrs, err := resolver.LookupIP(ctx, "ip4", target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", target, err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no IPv4 address for %s", target)
+	}
+	
+	return addrs[0], nil
+}
+
+// CheckDNSResolution tests DNS resolution for a given hostname, requesting
+// recordType ("A", "AAAA", "SRV" or "MX") or any address when it's empty, and
+// returns how long the lookup took
+func (cc *ConnectivityChecker) CheckDNSResolution(ctx context.Context, hostname, recordType string) (time.Duration, error) {
 	if hostname == "" {
-		return fmt.Errorf("no hostname provided")
+		return 0, fmt.Errorf("no hostname provided")
 	}
 	
-	ctx, cancel := context.WithTimeout(context.Background(), cc.dnsTimeout)
+	ctx, cancel := context.WithTimeout(ctx, cc.dnsTimeout)
 	defer cancel()
 	
+	start := time.Now()
 	resolver := &net.Resolver{}
-	_, err := resolver.LookupHost(ctx, hostname)
+	err := lookup(ctx, resolver, hostname, recordType)
+	if err != nil {
+		return 0, fmt.Errorf("DNS resolution failed for %s: %w", queryName(hostname, recordType), err)
+	}
+	
+	return time.Since(start).Round(time.Microsecond), nil
+}
+
+// CheckDNSServer resolves a hostname through a specific DNS server ("10.0.0.53"
+// or "10.0.0.53:5353"), bypassing the system resolver configuration, and
+// returns how long the server took to answer
+func (cc *ConnectivityChecker) CheckDNSServer(ctx context.Context, server, hostname, recordType string) (time.Duration, error) {
+	if hostname == "" {
+		return 0, fmt.Errorf("no hostname provided")
+	}
+	
+	address := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		address = net.JoinHostPort(server, "53")
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, cc.dnsTimeout)
+	defer cancel()
+	
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+	start := time.Now()
+	err := lookup(ctx, resolver, hostname, recordType)
 	if err != nil {
-		return fmt.Errorf("DNS resolution failed for %s: %w", hostname, err)
+		// DNSError names the resolv.conf server rather than the one dialled
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return 0, fmt.Errorf("DNS resolution failed for %s via %s: %s", queryName(hostname, recordType), address, dnsErr.Err)
+		}
+		return 0, fmt.Errorf("DNS resolution failed for %s via %s: %w", queryName(hostname, recordType), address, err)
 	}
 	
-	return nil
+	return time.Since(start).Round(time.Microsecond), nil
+}
+
+// lookup resolves hostname through resolver, requesting recordType or any
+// address when recordType is empty. At least one record must come back.
+func lookup(ctx context.Context, resolver *net.Resolver, hostname, recordType string) error {
+	var err error
+	switch recordType {
+	case "":
+		_, err = resolver.LookupHost(ctx, hostname)
+	case "A":
+		_, err = resolver.LookupIP(ctx, "ip4", hostname)
+	case "AAAA":
+		_, err = resolver.LookupIP(ctx, "ip6", hostname)
+	case "SRV":
+		_, _, err = resolver.LookupSRV(ctx, "", "", hostname)
+	case "MX":
+		_, err = resolver.LookupMX(ctx, hostname)
+	default:
+		err = fmt.Errorf("unsupported record type %s", recordType)
+	}
+	return err
+}
+
+// queryName describes a lookup for error messages, e.g. "_ldap._tcp.corp SRV"
+func queryName(hostname, recordType string) string {
+	if recordType == "" {
+		return hostname
+	}
+	return hostname + " " + recordType
+}
+
+// SystemNameservers returns the nameservers the system resolver uses, as
+// listed in /etc/resolv.conf
+func (cc *ConnectivityChecker) SystemNameservers() ([]string, error) {
+	return readNameservers(resolvConfPath)
+}
+
+// UpstreamNameservers returns the nameservers that actually answer the system
+// resolver's queries: those in /etc/resolv.conf, with any systemd-resolved stub
+// listener replaced by the upstreams resolved forwards to
+func (cc *ConnectivityChecker) UpstreamNameservers() ([]string, error) {
+	return upstreamNameservers(resolvConfPath, resolvedUpstreamPath)
+}
+
+// upstreamNameservers reads the nameservers of resolvConf, dropping the stub
+// listener addresses and adding the resolved upstreams listed in upstreamPath
+// in their place
+func upstreamNameservers(resolvConf, upstreamPath string) ([]string, error) {
+	servers, err := readNameservers(resolvConf)
+	if err != nil {
+		return nil, err
+	}
+	
+	var upstream []string
+	stub := false
+	for _, server := range servers {
+		if isResolvedStub(server) {
+			stub = true
+		} else {
+			upstream = append(upstream, server)
+		}
+	}
+	if !stub {
+		return upstream, nil
+	}
+	
+	resolved, err := readNameservers(upstreamPath)
+	if err != nil {
+		if len(upstream) > 0 {
+			return upstream, nil // The other entries still answer queries
+		}
+		return nil, err
+	}
+	for _, server := range resolved {
+		if !isResolvedStub(server) && !containsString(upstream, server) {
+			upstream = append(upstream, server)
+		}
+	}
+	return upstream, nil
+}
+
+// isResolvedStub reports whether server is a systemd-resolved stub listener
+func isResolvedStub(server string) bool {
+	return server == "127.0.0.53" || server == "127.0.0.54"
+}
+
+// readNameservers returns the nameserver entries of a resolv.conf file
+func readNameservers(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+	
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+	
+	return servers, scanner.Err()
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
 
 // CheckNetworkManagerConnectivity checks NetworkManager connectivity status
-func (cc *ConnectivityChecker) CheckNetworkManagerConnectivity() (string, error) {
+func (cc *ConnectivityChecker) CheckNetworkManagerConnectivity(ctx context.Context) (string, error) {
 	// Check if NetworkManager is running
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	cmdCtx, cancel := context.WithTimeout(ctx, cc.nmTimeout)
 	defer cancel()
 	
-	cmd := exec.CommandContext(ctx, "systemctl", "is-active", "NetworkManager")
+	cmd := exec.CommandContext(cmdCtx, "systemctl", "is-active", "NetworkManager")
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("NetworkManager is not running")
 	}
@@ -97,10 +572,10 @@ func (cc *ConnectivityChecker) CheckNetworkManagerConnectivity() (string, error)
 	}
 	
 	// Get connectivity status
-	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	cmdCtx, cancel = context.WithTimeout(ctx, cc.nmTimeout)
 	defer cancel()
 	
-	cmd = exec.CommandContext(ctx, "nmcli", "networking", "connectivity")
+	cmd = exec.CommandContext(cmdCtx, "nmcli", "networking", "connectivity")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to query NetworkManager connectivity: %w", err)
@@ -111,8 +586,8 @@ func (cc *ConnectivityChecker) CheckNetworkManagerConnectivity() (string, error)
 }
 
 // IsNetworkManagerConnectivityFull checks if NetworkManager reports full connectivity
-func (cc *ConnectivityChecker) IsNetworkManagerConnectivityFull() bool {
-	connectivity, err := cc.CheckNetworkManagerConnectivity()
+func (cc *ConnectivityChecker) IsNetworkManagerConnectivityFull(ctx context.Context) bool {
+	connectivity, err := cc.CheckNetworkManagerConnectivity(ctx)
 	if err != nil {
 		return false // Consider as not blocking if service is unavailable
 	}
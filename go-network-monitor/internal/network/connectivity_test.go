@@ -0,0 +1,74 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLookupHostsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	hosts := `127.0.0.1	localhost
+127.0.1.1	web1.example.com web1   # added by the installer
+10.1.2.3	web1.example.com web1
+::1	localhost ip6-localhost
+`
+	if err := os.WriteFile(path, []byte(hosts), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	addrs, canonical := lookupHostsFile(path, "WEB1")
+	if canonical != "web1.example.com" {
+		t.Errorf("canonical name %q, want web1.example.com", canonical)
+	}
+	if len(addrs) != 2 || addrs[0].String() != "127.0.1.1" || addrs[1].String() != "10.1.2.3" {
+		t.Errorf("addresses %v, want [127.0.1.1 10.1.2.3]", addrs)
+	}
+	
+	if addrs, canonical := lookupHostsFile(path, "db1"); addrs != nil || canonical != "" {
+		t.Errorf("db1: %v, %q; want no entry", addrs, canonical)
+	}
+}
+
+func TestUpstreamNameservers(t *testing.T) {
+	const upstream = "nameserver 192.0.2.1\nnameserver 192.0.2.2\n"
+	tests := []struct {
+		name       string
+		resolvConf string
+		upstream   string
+		want       []string
+		wantErr    bool
+	}{
+		{name: "stub only", resolvConf: "nameserver 127.0.0.53\noptions edns0 trust-ad\n", upstream: upstream, want: []string{"192.0.2.1", "192.0.2.2"}},
+		{name: "no stub", resolvConf: "nameserver 10.0.0.2\n", upstream: upstream, want: []string{"10.0.0.2"}},
+		{name: "stub and a server", resolvConf: "nameserver 127.0.0.53\nnameserver 192.0.2.2\nnameserver 10.0.0.2\n", upstream: upstream, want: []string{"192.0.2.2", "10.0.0.2", "192.0.2.1"}},
+		{name: "stub and a server, resolved not running", resolvConf: "nameserver 10.0.0.2\nnameserver 127.0.0.54\n", want: []string{"10.0.0.2"}},
+		{name: "stub only, resolved not running", resolvConf: "nameserver 127.0.0.53\n", wantErr: true},
+		{name: "none", resolvConf: "search corp\n", upstream: upstream},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			resolvConf := filepath.Join(dir, "resolv.conf")
+			upstreamPath := filepath.Join(dir, "upstream.conf")
+			if err := os.WriteFile(resolvConf, []byte(tt.resolvConf), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.upstream != "" {
+				if err := os.WriteFile(upstreamPath, []byte(tt.upstream), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			
+			got, err := upstreamNameservers(resolvConf, upstreamPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upstreamNameservers() error = %v, want error %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("upstreamNameservers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
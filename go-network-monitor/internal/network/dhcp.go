@@ -0,0 +1,248 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// DHCPLease represents an active DHCP lease found on disk
+type DHCPLease struct {
+	Interface string
+	Source    string // Client that wrote the lease (systemd-networkd, dhclient, dhcpcd)
+	Address   net.IP
+	Expiry    time.Time // Zero for an infinite or static lease
+}
+
+// infiniteLease is the lease time option value of a lease that never expires
+const infiniteLease = 0xffffffff
+
+// Permanent reports whether the lease never expires
+func (dl *DHCPLease) Permanent() bool {
+	return dl.Expiry.IsZero()
+}
+
+// Remaining returns the time left before the lease expires; it is only
+// meaningful for a lease that isn't permanent
+func (dl *DHCPLease) Remaining() time.Duration {
+	return time.Until(dl.Expiry)
+}
+
+// Expired reports whether the lease has expired
+func (dl *DHCPLease) Expired() bool {
+	return !dl.Permanent() && !dl.Expiry.After(time.Now())
+}
+
+// DHCPMonitor handles DHCP lease validation
+type DHCPMonitor struct{}
+
+// NewDHCPMonitor creates a new DHCP monitor
+func NewDHCPMonitor() *DHCPMonitor {
+	return &DHCPMonitor{}
+}
+
+// CheckLease looks up the DHCP lease for an interface. It returns nil without
+// an error when no lease file exists, i.e. the interface doesn't use DHCP.
+func (dm *DHCPMonitor) CheckLease(interfaceName string) (*DHCPLease, error) {
+	// systemd-networkd stores leases by interface index
+	if link, err := netlink.LinkByName(interfaceName); err == nil {
+		path := fmt.Sprintf("/run/systemd/netif/leases/%d", link.Attrs().Index)
+		if _, err := os.Stat(path); err == nil {
+			return parseNetworkdLease(interfaceName, path)
+		}
+	}
+	
+	dhclientPaths := []string{
+		fmt.Sprintf("/var/lib/dhcp/dhclient.%s.leases", interfaceName),
+		fmt.Sprintf("/var/lib/dhclient/dhclient-%s.leases", interfaceName),
+		fmt.Sprintf("/var/lib/NetworkManager/dhclient-%s.lease", interfaceName),
+	}
+	for _, path := range dhclientPaths {
+		if _, err := os.Stat(path); err == nil {
+			return parseDhclientLease(interfaceName, path)
+		}
+	}
+	
+	dhcpcdPaths := []string{
+		fmt.Sprintf("/var/lib/dhcpcd/%s.lease", interfaceName),
+		fmt.Sprintf("/var/lib/dhcpcd/dhcpcd-%s.lease", interfaceName),
+		fmt.Sprintf("/var/lib/dhcpcd5/dhcpcd-%s.lease", interfaceName),
+	}
+	for _, path := range dhcpcdPaths {
+		if _, err := os.Stat(path); err == nil {
+			return parseDhcpcdLease(interfaceName, path)
+		}
+	}
+	
+	return nil, nil
+}
+
+// parseNetworkdLease parses a systemd-networkd key=value lease file. The file
+// is rewritten on every renewal, so its mtime marks the start of the lease.
+func parseNetworkdLease(interfaceName, path string) (*DHCPLease, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat lease file %s: %w", path, err)
+	}
+	
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lease file %s: %w", path, err)
+	}
+	defer file.Close()
+	
+	lease := &DHCPLease{
+		Interface: interfaceName,
+		Source:    "systemd-networkd",
+	}
+	
+	var lifetime time.Duration
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		
+		switch key {
+		case "ADDRESS":
+			lease.Address = net.ParseIP(value)
+		case "LIFETIME":
+			if seconds, err := strconv.ParseUint(value, 10, 32); err == nil && seconds != infiniteLease {
+				lifetime = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	
+	if lease.Address == nil {
+		return nil, fmt.Errorf("no address in lease file %s", path)
+	}
+	
+	// networkd leaves LIFETIME out of infinite leases
+	if lifetime > 0 {
+		lease.Expiry = info.ModTime().Add(lifetime)
+	}
+	return lease, nil
+}
+
+// parseDhclientLease parses an ISC dhclient lease file, using the last lease block
+func parseDhclientLease(interfaceName, path string) (*DHCPLease, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lease file %s: %w", path, err)
+	}
+	defer file.Close()
+	
+	var lease *DHCPLease
+	var current *DHCPLease
+	
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ";")
+		
+		switch {
+		case strings.HasPrefix(line, "lease {"):
+			current = &DHCPLease{
+				Interface: interfaceName,
+				Source:    "dhclient",
+			}
+		case line == "}" && current != nil:
+			if current.Address != nil {
+				lease = current
+			}
+			current = nil
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "fixed-address "):
+			current.Address = net.ParseIP(strings.TrimPrefix(line, "fixed-address "))
+		case strings.HasPrefix(line, "expire "):
+			current.Expiry = parseDhclientTime(strings.TrimPrefix(line, "expire "))
+		}
+	}
+	
+	if lease == nil {
+		return nil, fmt.Errorf("no lease found in %s", path)
+	}
+	
+	return lease, nil
+}
+
+// parseDhclientTime parses "4 2025/08/14 10:00:00" (UTC) or "epoch 1755165600 # ...";
+// "never", written for infinite leases, gives the zero time
+func parseDhclientTime(value string) time.Time {
+	fields := strings.Fields(value)
+	if len(fields) >= 2 && fields[0] == "epoch" {
+		if seconds, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			return time.Unix(seconds, 0)
+		}
+		return time.Time{}
+	}
+	
+	if len(fields) >= 3 {
+		if t, err := time.Parse("2006/01/02 15:04:05", fields[1]+" "+fields[2]); err == nil {
+			return t
+		}
+	}
+	
+	return time.Time{}
+}
+
+// parseDhcpcdLease parses a dhcpcd lease file, which holds the raw DHCP ACK.
+// The file is written when the lease is obtained, so its mtime marks the start.
+func parseDhcpcdLease(interfaceName, path string) (*DHCPLease, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat lease file %s: %w", path, err)
+	}
+	
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lease file %s: %w", path, err)
+	}
+	
+	// BOOTP header is 236 bytes followed by the DHCP magic cookie
+	const optionsOffset = 240
+	if len(data) < optionsOffset || binary.BigEndian.Uint32(data[236:240]) != 0x63825363 {
+		return nil, fmt.Errorf("invalid DHCP message in %s", path)
+	}
+	
+	lease := &DHCPLease{
+		Interface: interfaceName,
+		Source:    "dhcpcd",
+		Address:   net.IP(append([]byte{}, data[16:20]...)),
+	}
+	
+	// Walk the options looking for the lease time (option 51)
+	for i := optionsOffset; i < len(data); {
+		code := data[i]
+		if code == 255 {
+			break
+		}
+		if code == 0 {
+			i++
+			continue
+		}
+		if i+1 >= len(data) {
+			break
+		}
+		length := int(data[i+1])
+		if i+2+length > len(data) {
+			break
+		}
+		if code == 51 && length == 4 {
+			if seconds := binary.BigEndian.Uint32(data[i+2 : i+6]); seconds != infiniteLease {
+				lease.Expiry = info.ModTime().Add(time.Duration(seconds) * time.Second)
+			}
+		}
+		i += 2 + length
+	}
+	
+	return lease, nil
+}
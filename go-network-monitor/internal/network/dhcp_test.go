@@ -0,0 +1,86 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDHCPLeaseExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiry    time.Time
+		permanent bool
+		expired   bool
+	}{
+		{name: "infinite", expiry: time.Time{}, permanent: true},
+		{name: "running", expiry: time.Now().Add(time.Hour)},
+		{name: "expired", expiry: time.Now().Add(-time.Minute), expired: true},
+	}
+	
+	for _, tt := range tests {
+		lease := &DHCPLease{Expiry: tt.expiry}
+		if lease.Permanent() != tt.permanent || lease.Expired() != tt.expired {
+			t.Errorf("%s: Permanent() = %t, Expired() = %t; want %t, %t",
+				tt.name, lease.Permanent(), lease.Expired(), tt.permanent, tt.expired)
+		}
+	}
+}
+
+func TestParseNetworkdLease(t *testing.T) {
+	tests := []struct {
+		name      string
+		lease     string
+		permanent bool
+	}{
+		{name: "lifetime", lease: "ADDRESS=192.0.2.10\nLIFETIME=3600\n"},
+		{name: "infinite lifetime", lease: "ADDRESS=192.0.2.10\nLIFETIME=4294967295\n", permanent: true},
+		{name: "no lifetime", lease: "ADDRESS=192.0.2.10\n", permanent: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "2")
+			if err := os.WriteFile(path, []byte(tt.lease), 0644); err != nil {
+				t.Fatal(err)
+			}
+			
+			lease, err := parseNetworkdLease("eth0", path)
+			if err != nil {
+				t.Fatalf("parseNetworkdLease: %v", err)
+			}
+			if lease.Permanent() != tt.permanent || lease.Expired() {
+				t.Errorf("Permanent() = %t, Expired() = %t; want %t, false", lease.Permanent(), lease.Expired(), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestParseDhclientLease(t *testing.T) {
+	const leases = `lease {
+  interface "eth0";
+  fixed-address 192.0.2.10;
+  expire 4 2020/01/02 10:00:00;
+}
+lease {
+  interface "eth0";
+  fixed-address 192.0.2.11;
+  expire never;
+}
+`
+	path := filepath.Join(t.TempDir(), "dhclient.eth0.leases")
+	if err := os.WriteFile(path, []byte(leases), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	// The last lease block is the current one
+	lease, err := parseDhclientLease("eth0", path)
+	if err != nil {
+		t.Fatalf("parseDhclientLease: %v", err)
+	}
+	if lease.Address.String() != "192.0.2.11" || !lease.Permanent() || lease.Expired() {
+		t.Errorf("lease %s permanent %t expired %t, want 192.0.2.11 that never expires",
+			lease.Address, lease.Permanent(), lease.Expired())
+	}
+}
@@ -0,0 +1,158 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// DNS header flags and the response codes the checks look at
+const (
+	dnsFlagResponse      = 0x8000
+	dnsFlagRecursion     = 0x0100
+	dnsFlagAuthenticated = 0x0020
+	dnsRcodeServFail     = 2
+)
+
+// dnsRcodeNames names the common response codes for logging
+var dnsRcodeNames = map[int]string{
+	0: "NOERROR",
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+// DNSSECAnswer is a nameserver's response to a query asking for DNSSEC
+// validation
+type DNSSECAnswer struct {
+	Rcode         int
+	Authenticated bool           // AD flag: the server validated the answer
+	RTT           time.Duration
+}
+
+// RcodeName returns the answer's response code as dig shows it
+func (a *DNSSECAnswer) RcodeName() string {
+	if name, ok := dnsRcodeNames[a.Rcode]; ok {
+		return name
+	}
+	return fmt.Sprintf("RCODE%d", a.Rcode)
+}
+
+// Bogus reports whether the server refused to answer as validation failed,
+// which validating resolvers signal with SERVFAIL
+func (a *DNSSECAnswer) Bogus() bool {
+	return a.Rcode == dnsRcodeServFail
+}
+
+// QueryDNSSEC asks server for the A records of name with the DO and AD bits
+// set, the way a validating stub does, and returns the response code and
+// whether the server vouched for the answer
+func QueryDNSSEC(ctx context.Context, server, name string, timeout time.Duration) (*DNSSECAnswer, error) {
+	query, err := dnsQuery(name, 1, dnsFlagRecursion|dnsFlagAuthenticated, true)
+	if err != nil {
+		return nil, err
+	}
+	response, rtt, err := dnsExchange(ctx, server, query, timeout)
+	if err != nil {
+		return nil, err
+	}
+	
+	flags := binary.BigEndian.Uint16(response[2:])
+	return &DNSSECAnswer{
+		Rcode:         int(flags & 0x000f),
+		Authenticated: flags&dnsFlagAuthenticated != 0,
+		RTT:           rtt,
+	}, nil
+}
+
+// dnsQuery encodes a query for name and qtype (class IN) with the given
+// header flags and a random ID, optionally with an EDNS0 OPT record asking
+// for DNSSEC records (DO bit)
+func dnsQuery(name string, qtype uint16, flags uint16, dnssecOK bool) ([]byte, error) {
+	query := make([]byte, 12, 512)
+	// An unpredictable ID keeps off-path spoofed answers from matching
+	if _, err := rand.Read(query[0:2]); err != nil {
+		return nil, fmt.Errorf("failed to generate query ID: %w", err)
+	}
+	binary.BigEndian.PutUint16(query[2:], flags)
+	binary.BigEndian.PutUint16(query[4:], 1)
+	
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			continue // Root
+		}
+		if len(label) > 63 {
+			return nil, fmt.Errorf("invalid name %q: label longer than 63 characters", name)
+		}
+		query = append(query, byte(len(label)))
+		query = append(query, label...)
+	}
+	query = append(query, 0)
+	query = binary.BigEndian.AppendUint16(query, qtype)
+	query = binary.BigEndian.AppendUint16(query, 1)
+	
+	if dnssecOK {
+		binary.BigEndian.PutUint16(query[10:], 1)
+		// OPT: root name, type 41, 1232 byte payload, DO bit in the TTL
+		query = append(query, 0)
+		query = binary.BigEndian.AppendUint16(query, 41)
+		query = binary.BigEndian.AppendUint16(query, 1232)
+		query = binary.BigEndian.AppendUint32(query, 0x8000)
+		query = binary.BigEndian.AppendUint16(query, 0)
+	}
+	return query, nil
+}
+
+// dnsExchange sends query to server ("10.0.0.53" or "10.0.0.53:5353") over
+// UDP and returns the matching response and how long it took
+func dnsExchange(ctx context.Context, server string, query []byte, timeout time.Duration) ([]byte, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	address := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		address = net.JoinHostPort(server, "53")
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+	
+	start := time.Now()
+	if _, err := conn.Write(query); err != nil {
+		return nil, 0, fmt.Errorf("failed to send query: %w", err)
+	}
+	
+	id := binary.BigEndian.Uint16(query)
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil, 0, ctx.Err()
+			}
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil, 0, fmt.Errorf("no answer within %s", timeout)
+			}
+			return nil, 0, err
+		}
+		if n >= 12 && binary.BigEndian.Uint16(buf) == id && binary.BigEndian.Uint16(buf[2:])&dnsFlagResponse != 0 {
+			return buf[:n], time.Since(start).Round(time.Microsecond), nil
+		}
+	}
+}
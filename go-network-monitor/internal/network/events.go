@@ -0,0 +1,127 @@
+package network
+
+import (
+	"fmt"
+	"syscall"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// LinkWatcher delivers kernel link, address, route and neighbor change notifications
+type LinkWatcher struct {
+	events chan string
+	done   chan struct{}
+}
+
+// NewLinkWatcher subscribes to netlink link, address, route and neighbor updates
+func NewLinkWatcher() (*LinkWatcher, error) {
+	lw := &LinkWatcher{
+		events: make(chan string, 64),
+		done:   make(chan struct{}),
+	}
+	
+	linkUpdates := make(chan netlink.LinkUpdate)
+	if err := netlink.LinkSubscribe(linkUpdates, lw.done); err != nil {
+		close(lw.done)
+		return nil, fmt.Errorf("failed to subscribe to link updates: %w", err)
+	}
+	
+	addrUpdates := make(chan netlink.AddrUpdate)
+	if err := netlink.AddrSubscribe(addrUpdates, lw.done); err != nil {
+		close(lw.done)
+		return nil, fmt.Errorf("failed to subscribe to address updates: %w", err)
+	}
+	
+	routeUpdates := make(chan netlink.RouteUpdate)
+	if err := netlink.RouteSubscribe(routeUpdates, lw.done); err != nil {
+		close(lw.done)
+		return nil, fmt.Errorf("failed to subscribe to route updates: %w", err)
+	}
+	
+	neighUpdates := make(chan netlink.NeighUpdate)
+	if err := netlink.NeighSubscribe(neighUpdates, lw.done); err != nil {
+		close(lw.done)
+		return nil, fmt.Errorf("failed to subscribe to neighbor updates: %w", err)
+	}
+	
+	go func() {
+		for update := range linkUpdates {
+			attrs := update.Link.Attrs()
+			if update.Header.Type == syscall.RTM_DELLINK {
+				lw.notify(fmt.Sprintf("link %s removed", attrs.Name))
+			} else {
+				lw.notify(fmt.Sprintf("link %s %s", attrs.Name, attrs.OperState))
+			}
+		}
+	}()
+	
+	go func() {
+		for update := range addrUpdates {
+			action := "removed"
+			if update.NewAddr {
+				action = "added"
+			}
+			lw.notify(fmt.Sprintf("address %s %s", action, update.LinkAddress.String()))
+		}
+	}()
+	
+	go func() {
+		for update := range routeUpdates {
+			lw.notify(fmt.Sprintf("route %s %s", updateAction(update.Type, syscall.RTM_DELROUTE), routeDestination(update.Route)))
+		}
+	}()
+	
+	go func() {
+		for update := range neighUpdates {
+			lw.notify(fmt.Sprintf("neighbor %s %s", updateAction(update.Type, syscall.RTM_DELNEIGH), update.IP))
+		}
+	}()
+	
+	return lw, nil
+}
+
+// Events returns the channel on which change descriptions are delivered
+func (lw *LinkWatcher) Events() <-chan string {
+	return lw.events
+}
+
+// Drain discards any pending events, typically those caused by the checks themselves
+func (lw *LinkWatcher) Drain() {
+	for {
+		select {
+		case <-lw.events:
+		default:
+			return
+		}
+	}
+}
+
+// Close stops all subscriptions
+func (lw *LinkWatcher) Close() {
+	close(lw.done)
+}
+
+// notify queues an event without blocking the subscription goroutines;
+// events are coalesced by the consumer so dropping on a full queue is harmless
+func (lw *LinkWatcher) notify(event string) {
+	select {
+	case lw.events <- event:
+	default:
+	}
+}
+
+// updateAction describes a netlink message type as added or removed
+func updateAction(msgType, delType uint16) string {
+	if msgType == delType {
+		return "removed"
+	}
+	return "added"
+}
+
+// routeDestination returns a printable destination for a route
+func routeDestination(route netlink.Route) string {
+	if route.Dst == nil {
+		return "default"
+	}
+	return route.Dst.String()
+}
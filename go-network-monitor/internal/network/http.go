@@ -0,0 +1,91 @@
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// maxHTTPBody caps how much of a response body is read for matching
+const maxHTTPBody = 1 << 20
+
+// HTTPResponse is the outcome of fetching a URL
+type HTTPResponse struct {
+	Status   int
+	Location string         // Redirect target, when redirects aren't followed
+	Body     []byte         // Up to maxHTTPBody bytes of the body
+	Elapsed  time.Duration
+}
+
+// FetchOptions controls how a URL is fetched
+type FetchOptions struct {
+	Timeout    time.Duration
+	Insecure   bool      // Skip TLS certificate verification
+	Proxy      *url.URL  // Proxy to send the request through (nil = http_proxy/https_proxy environment)
+	NoRedirect bool      // Return redirects rather than following them
+}
+
+// FetchURL issues a GET for rawURL, including DNS resolution, the proxy
+// CONNECT for https URLs and the TLS handshake, within opts.Timeout. Each
+// fetch uses a fresh connection so a cached connection can't hide a broken
+// path.
+func FetchURL(ctx context.Context, rawURL string, opts FetchOptions) (*HTTPResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != nil {
+		proxy = http.ProxyURL(opts.Proxy)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             proxy,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: opts.Insecure},
+			DisableKeepAlives: true,
+		},
+	}
+	if opts.NoRedirect {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "network-monitor")
+	
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	
+	return &HTTPResponse{
+		Status:   resp.StatusCode,
+		Location: resp.Header.Get("Location"),
+		Body:     body,
+		Elapsed:  time.Since(start).Round(time.Microsecond),
+	}, nil
+}
+
+// EnvironmentProxy returns the proxy the http_proxy, https_proxy and no_proxy
+// environment variables select for rawURL, or nil when there is none
+func EnvironmentProxy(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyFromEnvironment(&http.Request{URL: u})
+}
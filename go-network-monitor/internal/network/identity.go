@@ -0,0 +1,71 @@
+package network
+
+import (
+	"net"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// ethtool request for the permanent hardware address (include/uapi/linux/ethtool.h)
+const (
+	siocEthtool      = 0x8946
+	ethtoolGPermAddr = 0x20
+	maxAddrLen       = 32
+)
+
+// ethtoolPermAddr is struct ethtool_perm_addr with room for the address
+type ethtoolPermAddr struct {
+	cmd  uint32
+	size uint32
+	data [maxAddrLen]byte
+}
+
+// ifreqData is struct ifreq with the ifr_data member of the union
+type ifreqData struct {
+	name [syscall.IFNAMSIZ]byte
+	data uintptr
+	_    [16]byte
+}
+
+// permanentAddress returns the burned-in hardware address of an interface,
+// which unlike the current address survives bonding and MAC changes, or nil
+// when the driver doesn't report one
+func permanentAddress(interfaceName string) net.HardwareAddr {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil
+	}
+	defer syscall.Close(fd)
+	
+	perm := ethtoolPermAddr{cmd: ethtoolGPermAddr, size: maxAddrLen}
+	var ifr ifreqData
+	copy(ifr.name[:syscall.IFNAMSIZ-1], interfaceName)
+	ifr.data = uintptr(unsafe.Pointer(&perm))
+	
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), siocEthtool, uintptr(unsafe.Pointer(&ifr)))
+	runtime.KeepAlive(&perm)
+	if errno != 0 || perm.size == 0 || perm.size > maxAddrLen {
+		return nil
+	}
+	
+	addr := net.HardwareAddr(append([]byte(nil), perm.data[:perm.size]...))
+	for _, b := range addr {
+		if b != 0 {
+			return addr
+		}
+	}
+	return nil // Virtual devices report all zeroes
+}
+
+// MatchInterfaceStatus reports whether a pattern names an interface: a MAC
+// address matches the interface's permanent or current address, anything
+// else is matched against the name as a glob. Matching by MAC keeps a
+// requirement valid when udev renames eth0 to enp3s0f0 mid-boot.
+func MatchInterfaceStatus(pattern string, status *InterfaceStatus) bool {
+	if mac, err := net.ParseMAC(pattern); err == nil {
+		return strings.EqualFold(mac.String(), status.PermAddr) || strings.EqualFold(mac.String(), status.MAC)
+	}
+	return MatchInterface(pattern, status.Name)
+}
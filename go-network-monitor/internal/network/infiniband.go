@@ -0,0 +1,72 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// arphrdInfiniband is the ARPHRD_INFINIBAND hardware type IPoIB interfaces
+// report in /sys/class/net/<iface>/type
+const arphrdInfiniband = "32"
+
+// InfiniBandStatus represents the state of the HCA port behind an IPoIB interface
+type InfiniBandStatus struct {
+	Name      string
+	Device    string  // HCA, e.g. "mlx5_0"
+	Port      int
+	State     string  // Logical port state: "DOWN", "INIT", "ARMED" or "ACTIVE"
+	PhysState string  // Physical port state, e.g. "Polling" or "LinkUp"
+	LinkLayer string  // "InfiniBand", or "Ethernet" for RoCE ports
+	Rate      string  // e.g. "100 Gb/sec (4X EDR)"
+}
+
+// Active reports whether the port can pass traffic; an IB port only goes
+// ACTIVE once the subnet manager has configured it, well after carrier
+func (ibs *InfiniBandStatus) Active() bool {
+	return ibs.State == "ACTIVE"
+}
+
+// IsInfiniBandInterface checks if an interface is an IPoIB interface
+func (im *InterfaceMonitor) IsInfiniBandInterface(interfaceName string) bool {
+	return readSysfs(fmt.Sprintf("/sys/class/net/%s/type", interfaceName)) == arphrdInfiniband
+}
+
+// CheckInfiniBandStatus reads the port state and link layer of the HCA port
+// an IPoIB interface runs on
+func (im *InterfaceMonitor) CheckInfiniBandStatus(interfaceName string) (*InfiniBandStatus, error) {
+	devices, err := os.ReadDir(fmt.Sprintf("/sys/class/net/%s/device/infiniband", interfaceName))
+	if err != nil || len(devices) == 0 {
+		return nil, fmt.Errorf("no InfiniBand device found for %s", interfaceName)
+	}
+	
+	status := &InfiniBandStatus{Name: interfaceName, Device: devices[0].Name(), Port: 1}
+	
+	// dev_port is the zero-based port of the HCA; IB ports are numbered from 1
+	if devPort, err := strconv.Atoi(readSysfs(fmt.Sprintf("/sys/class/net/%s/dev_port", interfaceName))); err == nil {
+		status.Port = devPort + 1
+	}
+	
+	portPath := fmt.Sprintf("/sys/class/infiniband/%s/ports/%d", status.Device, status.Port)
+	state := readSysfs(portPath + "/state")
+	if state == "" {
+		return nil, fmt.Errorf("failed to read state of %s port %d", status.Device, status.Port)
+	}
+	
+	// state and phys_state read as "4: ACTIVE" and "5: LinkUp"
+	status.State = portStateName(state)
+	status.PhysState = portStateName(readSysfs(portPath + "/phys_state"))
+	status.LinkLayer = readSysfs(portPath + "/link_layer")
+	status.Rate = readSysfs(portPath + "/rate")
+	
+	return status, nil
+}
+
+// portStateName strips the numeric prefix from an IB port state
+func portStateName(state string) string {
+	if _, name, ok := strings.Cut(state, ":"); ok {
+		return strings.TrimSpace(name)
+	}
+	return state
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path"
+	"strconv"
 	"strings"
+	"syscall"
 	
 	"github.com/vishvananda/netlink"
 )
@@ -14,41 +17,87 @@ import (
 type InterfaceType string
 
 const (
-	Ethernet InterfaceType = "ethernet"
-	Bond     InterfaceType = "bond"
-	Wireless InterfaceType = "wireless"
-	Tunnel   InterfaceType = "tunnel"
-	Other    InterfaceType = "other"
+	Ethernet   InterfaceType = "ethernet"
+	Bond       InterfaceType = "bond"
+	Bridge     InterfaceType = "bridge"
+	Wireless   InterfaceType = "wireless"
+	Tunnel     InterfaceType = "tunnel"
+	InfiniBand InterfaceType = "infiniband"
+	VLAN       InterfaceType = "vlan"
+	MACVLAN    InterfaceType = "macvlan"
+	IPVLAN     InterfaceType = "ipvlan"
+	Veth       InterfaceType = "veth"
+	Dummy      InterfaceType = "dummy"
+	Other      InterfaceType = "other"
 )
 
+// linkKinds maps netlink link kinds (IFLA_INFO_KIND) to interface types;
+// physical devices, wireless and IPoIB interfaces have no kind
+var linkKinds = map[string]InterfaceType{
+	"bond":      Bond,
+	"bridge":    Bridge,
+	"vlan":      VLAN,
+	"macvlan":   MACVLAN,
+	"macvtap":   MACVLAN,
+	"ipvlan":    IPVLAN,
+	"veth":      Veth,
+	"dummy":     Dummy,
+	"wireguard": Tunnel,
+	"tuntap":    Tunnel,
+	"ipip":      Tunnel,
+	"sit":       Tunnel,
+	"gre":       Tunnel,
+	"gretap":    Tunnel,
+	"ip6gre":    Tunnel,
+	"ip6gretap": Tunnel,
+	"ip6tnl":    Tunnel,
+	"vti":       Tunnel,
+	"vti6":      Tunnel,
+	"vxlan":     Tunnel,
+	"geneve":    Tunnel,
+}
+
 // InterfaceStatus represents the status of a network interface
 type InterfaceStatus struct {
 	Name        string
 	Type        InterfaceType
+	Index       int     // ifindex, stable across renames
+	MAC         string  // Current hardware address
+	PermAddr    string  // Permanent hardware address (empty if the driver doesn't report one)
 	Carrier     bool
 	OperState   string
 	AdminState  string
 	HasCarrier  bool
+	MTU         int
+	Speed       int     // Negotiated speed in Mbps (-1 = unknown, e.g. no carrier)
+	Duplex      string  // "full", "half" or "unknown"
+	Tentative   []string  // Addresses still undergoing Duplicate Address Detection
+	DADFailed   []string  // Addresses that failed Duplicate Address Detection
 }
 
-// BondStatus represents the status of a bond interface
-type BondStatus struct {
+// DiscoveredInterface describes an interface found on the host and whether it
+// would be monitored with the current configuration
+type DiscoveredInterface struct {
 	Name           string
-	Mode           string
-	MIIStatus      string
-	ActiveSlave    string
-	SlaveCount     int
-	TotalSlaves    int
-	LACPComplete   bool
+	Type           InterfaceType
+	Excluded       bool    // Matches an excluded pattern, or is an ignored SR-IOV VF
+	Required       bool    // Matches a required interface pattern
+	TypeMonitored  bool    // Of a monitored type
+	Monitored      bool    // Not excluded, and of a monitored type or required
+	PF             string  // Parent physical function of an SR-IOV VF, empty otherwise
 }
 
 // InterfaceMonitor handles network interface monitoring
 type InterfaceMonitor struct {
 	interfaceTypes []InterfaceType
+	excluded       []string  // Glob patterns of interfaces never monitored
+	ignoreVFs      bool      // SR-IOV virtual functions are never monitored
 }
 
-// NewInterfaceMonitor creates a new interface monitor
-func NewInterfaceMonitor(interfaceTypes []string) *InterfaceMonitor {
+// NewInterfaceMonitor creates a new interface monitor; interfaces matching any
+// of the excluded glob patterns (e.g. "docker*", "veth*") are ignored, as are
+// SR-IOV virtual functions when ignoreVFs is set
+func NewInterfaceMonitor(interfaceTypes []string, excluded []string, ignoreVFs bool) *InterfaceMonitor {
 	var types []InterfaceType
 	for _, t := range interfaceTypes {
 		switch strings.ToLower(t) {
@@ -56,21 +105,43 @@ func NewInterfaceMonitor(interfaceTypes []string) *InterfaceMonitor {
 			types = append(types, Ethernet)
 		case "bond":
 			types = append(types, Bond)
+		case "bridge":
+			types = append(types, Bridge)
 		case "wireless":
 			types = append(types, Wireless)
 		case "tunnel":
 			types = append(types, Tunnel)
+		case "infiniband":
+			types = append(types, InfiniBand)
+		case "vlan":
+			types = append(types, VLAN)
+		case "macvlan":
+			types = append(types, MACVLAN)
+		case "ipvlan":
+			types = append(types, IPVLAN)
+		case "veth":
+			types = append(types, Veth)
+		case "dummy":
+			types = append(types, Dummy)
 		case "other":
 			types = append(types, Other)
 		}
 	}
-	return &InterfaceMonitor{interfaceTypes: types}
+	return &InterfaceMonitor{interfaceTypes: types, excluded: excluded, ignoreVFs: ignoreVFs}
+}
+
+// MatchInterface reports whether an interface name matches a glob pattern
+// such as "en*" or "bond[0-9]"; a plain name only matches itself
+func MatchInterface(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
 }
 
 // GetActiveInterfaces returns all active network interfaces (excluding loopback)
+// of a monitored type, plus any matching a required pattern whatever its type
 // IMPORTANT: Never cache this function's result - interface discovery
 // during boot is one of the key things we need to troubleshoot.
-func (im *InterfaceMonitor) GetActiveInterfaces() ([]string, error) {
+func (im *InterfaceMonitor) GetActiveInterfaces(required []string) ([]string, error) {
 	links, err := netlink.LinkList()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
@@ -83,7 +154,11 @@ func (im *InterfaceMonitor) GetActiveInterfaces() ([]string, error) {
 			continue // Skip loopback
 		}
 		
-		if im.isInterfaceTypeMonitored(name) {
+		if im.isExcluded(name) {
+			continue
+		}
+		
+		if im.isInterfaceTypeMonitored(name) || matchesRequired(link, required) {
 			interfaces = append(interfaces, name)
 		}
 	}
@@ -91,6 +166,39 @@ func (im *InterfaceMonitor) GetActiveInterfaces() ([]string, error) {
 	return interfaces, nil
 }
 
+// DiscoverInterfaces lists every interface (excluding loopback) with its
+// detected type, including the ones that are not monitored
+func (im *InterfaceMonitor) DiscoverInterfaces(required []string) ([]DiscoveredInterface, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+	
+	var interfaces []DiscoveredInterface
+	for _, link := range links {
+		name := link.Attrs().Name
+		if name == "lo" {
+			continue
+		}
+		
+		excluded := im.isExcluded(name)
+		isRequired := matchesRequired(link, required)
+		typeMonitored := im.isInterfaceTypeMonitored(name)
+		pf, _, _ := im.VFParent(name)
+		interfaces = append(interfaces, DiscoveredInterface{
+			Name:          name,
+			Type:          im.getInterfaceType(name),
+			Excluded:      excluded,
+			Required:      isRequired,
+			TypeMonitored: typeMonitored,
+			Monitored:     !excluded && (typeMonitored || isRequired),
+			PF:            pf,
+		})
+	}
+	
+	return interfaces, nil
+}
+
 // CheckInterfaceStatus checks the status of a network interface
 func (im *InterfaceMonitor) CheckInterfaceStatus(interfaceName string) (*InterfaceStatus, error) {
 	link, err := netlink.LinkByName(interfaceName)
@@ -100,8 +208,14 @@ func (im *InterfaceMonitor) CheckInterfaceStatus(interfaceName string) (*Interfa
 	
 	attrs := link.Attrs()
 	status := &InterfaceStatus{
-		Name: interfaceName,
-		Type: im.getInterfaceType(interfaceName),
+		Name:  interfaceName,
+		Type:  im.getInterfaceType(interfaceName),
+		Index: attrs.Index,
+		MAC:   attrs.HardwareAddr.String(),
+		MTU:   attrs.MTU,
+	}
+	if perm := permanentAddress(interfaceName); perm != nil {
+		status.PermAddr = perm.String()
 	}
 	
 	// Check carrier status
@@ -113,6 +227,8 @@ func (im *InterfaceMonitor) CheckInterfaceStatus(interfaceName string) (*Interfa
 		status.HasCarrier = status.Carrier
 	}
 	
+	status.Speed, status.Duplex = linkSpeed(interfaceName)
+	
 	// Check operational state
 	operstatePath := fmt.Sprintf("/sys/class/net/%s/operstate", interfaceName)
 	operstateData, err := os.ReadFile(operstatePath)
@@ -129,112 +245,167 @@ func (im *InterfaceMonitor) CheckInterfaceStatus(interfaceName string) (*Interfa
 		status.AdminState = "down"
 	}
 	
+	if err := checkDAD(link, status); err != nil {
+		return nil, err
+	}
+	
 	return status, nil
 }
 
-// CheckBondStatus checks the status of a bond interface
-func (im *InterfaceMonitor) CheckBondStatus(interfaceName string) (*BondStatus, error) {
-	bondPath := fmt.Sprintf("/proc/net/bonding/%s", interfaceName)
-	
-	file, err := os.Open(bondPath)
-	if err != nil {
-		return nil, fmt.Errorf("bond interface %s not found: %w", interfaceName, err)
+// linkSpeed returns the negotiated speed in Mbps and duplex of an interface;
+// virtual interfaces and interfaces without carrier report -1/unknown or
+// fail the read
+func linkSpeed(interfaceName string) (int, string) {
+	speed := -1
+	speedPath := fmt.Sprintf("/sys/class/net/%s/speed", interfaceName)
+	speedData, err := os.ReadFile(speedPath)
+	if err == nil {
+		if value, err := strconv.Atoi(strings.TrimSpace(string(speedData))); err == nil && value > 0 {
+			speed = value
+		}
 	}
-	defer file.Close()
 	
-	status := &BondStatus{
-		Name: interfaceName,
+	duplex := "unknown"
+	duplexPath := fmt.Sprintf("/sys/class/net/%s/duplex", interfaceName)
+	if duplexData, err := os.ReadFile(duplexPath); err == nil {
+		duplex = strings.TrimSpace(string(duplexData))
 	}
 	
-	scanner := bufio.NewScanner(file)
-	var currentSlave string
-	slaveStates := make(map[string]bool)
+	return speed, duplex
+}
+
+// checkDAD records the addresses of a link that are still tentative or failed
+// Duplicate Address Detection. Only IPv6 addresses carry DAD state; optimistic
+// addresses are usable while tentative and aren't reported.
+func checkDAD(link netlink.Link, status *InterfaceStatus) error {
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("failed to list addresses of %s: %w", status.Name, err)
+	}
 	
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		
-		if strings.HasPrefix(line, "Bonding Mode: ") {
-			status.Mode = strings.TrimPrefix(line, "Bonding Mode: ")
-		} else if strings.HasPrefix(line, "MII Status: ") {
-			status.MIIStatus = strings.TrimPrefix(line, "MII Status: ")
-		} else if strings.HasPrefix(line, "Currently Active Slave: ") {
-			status.ActiveSlave = strings.TrimPrefix(line, "Currently Active Slave: ")
-		} else if strings.HasPrefix(line, "Slave Interface: ") {
-			currentSlave = strings.TrimPrefix(line, "Slave Interface: ")
-			status.TotalSlaves++
-		} else if strings.HasPrefix(line, "MII Status: ") && currentSlave != "" {
-			miiStatus := strings.TrimPrefix(line, "MII Status: ")
-			if miiStatus == "up" {
-				status.SlaveCount++
-				slaveStates[currentSlave] = true
-			}
-		} else if strings.Contains(line, "Actor LACP PDU: ") && currentSlave != "" {
-			// Parse LACP state for 802.3ad bonds
-			if strings.Contains(line, "Collecting distributing") {
-				slaveStates[currentSlave] = true
-			}
+	for _, addr := range addrs {
+		switch {
+		case addr.Flags&syscall.IFA_F_DADFAILED != 0:
+			status.DADFailed = append(status.DADFailed, addr.IPNet.String())
+		case addr.Flags&syscall.IFA_F_TENTATIVE != 0 && addr.Flags&syscall.IFA_F_OPTIMISTIC == 0:
+			status.Tentative = append(status.Tentative, addr.IPNet.String())
 		}
 	}
-	
-	// Check if LACP is complete for 802.3ad bonds
-	if strings.Contains(status.Mode, "IEEE 802.3ad") {
-		status.LACPComplete = true
-		for _, lacpOk := range slaveStates {
-			if !lacpOk {
-				status.LACPComplete = false
-				break
-			}
+	return nil
+}
+
+// isExcluded checks if an interface matches an exclusion pattern or is an
+// SR-IOV virtual function that should be ignored
+func (im *InterfaceMonitor) isExcluded(interfaceName string) bool {
+	for _, pattern := range im.excluded {
+		if MatchInterface(pattern, interfaceName) {
+			return true
 		}
-	} else {
-		// For non-LACP bonds, consider complete if we have an active slave
-		status.LACPComplete = (status.ActiveSlave != "" && status.SlaveCount > 0)
 	}
-	
-	return status, nil
+	if im.ignoreVFs {
+		if _, _, isVF := im.VFParent(interfaceName); isVF {
+			return true
+		}
+	}
+	return false
 }
 
-// IsBondInterface checks if an interface is a bond interface
-func (im *InterfaceMonitor) IsBondInterface(interfaceName string) bool {
-	bondPath := fmt.Sprintf("/proc/net/bonding/%s", interfaceName)
-	_, err := os.Stat(bondPath)
-	return err == nil
+// matchesRequired reports whether a link matches any of the required
+// interface patterns, by name or by permanent or current MAC address
+func matchesRequired(link netlink.Link, required []string) bool {
+	if len(required) == 0 {
+		return false
+	}
+	status := &InterfaceStatus{Name: link.Attrs().Name, MAC: link.Attrs().HardwareAddr.String()}
+	if perm := permanentAddress(status.Name); perm != nil {
+		status.PermAddr = perm.String()
+	}
+	for _, pattern := range required {
+		if MatchInterfaceStatus(pattern, status) {
+			return true
+		}
+	}
+	return false
 }
 
 // isInterfaceTypeMonitored checks if an interface type should be monitored
 func (im *InterfaceMonitor) isInterfaceTypeMonitored(interfaceName string) bool {
 	interfaceType := im.getInterfaceType(interfaceName)
+	if im.monitorsType(interfaceType) {
+		return true
+	}
 	
+	// Types used to be guessed from the name, so virtual interfaces named
+	// like NICs (eth0.100, a container's veth or macvlan eth0) were ethernet;
+	// keep monitoring them where ethernet is
+	switch interfaceType {
+	case VLAN, MACVLAN, IPVLAN, Veth:
+		if im.monitorsType(Ethernet) && (strings.HasPrefix(interfaceName, "eth") || strings.HasPrefix(interfaceName, "en")) {
+			return true
+		}
+	}
+	
+	return false
+}
+
+// monitorsType reports whether an interface type is one of the monitored ones
+func (im *InterfaceMonitor) monitorsType(interfaceType InterfaceType) bool {
 	for _, monitoredType := range im.interfaceTypes {
 		if interfaceType == monitoredType {
 			return true
 		}
 	}
-	
 	return false
 }
 
-// getInterfaceType determines the type of network interface
+// getInterfaceType determines the type of network interface from its netlink
+// kind, falling back to sysfs for devices without one. Names aren't used:
+// udev and administrators can rename any device.
 func (im *InterfaceMonitor) getInterfaceType(interfaceName string) InterfaceType {
-	// Check if it's a bond interface
-	if im.IsBondInterface(interfaceName) {
-		return Bond
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		return Other
 	}
 	
-	// Check wireless
-	wirelessPath := fmt.Sprintf("/sys/class/net/%s/wireless", interfaceName)
-	if _, err := os.Stat(wirelessPath); err == nil {
+	if interfaceType, ok := linkKinds[link.Type()]; ok {
+		return interfaceType
+	}
+	
+	// Wireless interfaces are plain devices with a wlan devtype
+	sysfsPath := fmt.Sprintf("/sys/class/net/%s", interfaceName)
+	if ueventDevType(interfaceName) == "wlan" {
+		return Wireless
+	}
+	if _, err := os.Stat(sysfsPath + "/wireless"); err == nil {
 		return Wireless
 	}
 	
-	// Check if it's a tunnel interface
-	if strings.HasPrefix(interfaceName, "tun") || strings.HasPrefix(interfaceName, "tap") {
-		return Tunnel
+	if im.IsInfiniBandInterface(interfaceName) {
+		return InfiniBand
 	}
 	
-	// Default to ethernet for physical interfaces
-	if strings.HasPrefix(interfaceName, "eth") || strings.HasPrefix(interfaceName, "en") {
+	// Remaining devices backed by hardware are physical NICs
+	if _, err := os.Stat(sysfsPath + "/device"); err == nil {
 		return Ethernet
 	}
 	
 	return Other
-}
\ No newline at end of file
+}
+
+// ueventDevType returns the DEVTYPE the kernel reports for an interface in
+// its uevent file, e.g. "wlan", "vlan" or "bond"; empty for plain devices
+func ueventDevType(interfaceName string) string {
+	file, err := os.Open(fmt.Sprintf("/sys/class/net/%s/uevent", interfaceName))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "DEVTYPE="); ok {
+			return value
+		}
+	}
+	return ""
+}
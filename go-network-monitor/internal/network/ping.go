@@ -0,0 +1,295 @@
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ICMP message types used for echo
+const (
+	icmpEchoReply     = 0
+	icmpEchoRequest   = 8
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+)
+
+// pingSeq numbers echo requests so concurrent pings can't take each other's replies
+var pingSeq uint32
+
+// IP and ICMP header sizes, for sizing path MTU probes
+const (
+	ipv4HeaderLen = 20
+	ipv6HeaderLen = 40
+	icmpHeaderLen = 8
+	pingTokenLen  = 8
+)
+
+// PingInterval spaces consecutive echo requests to the same target
+const PingInterval = 200 * time.Millisecond
+
+// PingStats summarizes a series of echo requests to one target
+type PingStats struct {
+	Sent     int
+	Received int
+	MinRTT   time.Duration
+	AvgRTT   time.Duration
+	MaxRTT   time.Duration
+	Err      error  // Error behind the last unanswered echo request
+}
+
+// LossPercent returns the percentage of echo requests that went unanswered
+func (s *PingStats) LossPercent() int {
+	if s.Sent == 0 {
+		return 100
+	}
+	return (s.Sent - s.Received) * 100 / s.Sent
+}
+
+// PingSeries sends count echo requests to addr, PingInterval apart, each
+// waiting up to timeout for its reply. It stops early when ctx is done.
+func PingSeries(ctx context.Context, addr *net.IPAddr, count int, timeout time.Duration) *PingStats {
+	stats := &PingStats{}
+	var total time.Duration
+	
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return stats
+			case <-time.After(PingInterval):
+			}
+		}
+		
+		stats.Sent++
+		rtt, err := Ping(ctx, addr, timeout)
+		if err != nil {
+			stats.Err = err
+			if ctx.Err() != nil {
+				return stats
+			}
+			continue
+		}
+		
+		stats.Received++
+		total += rtt
+		if stats.MinRTT == 0 || rtt < stats.MinRTT {
+			stats.MinRTT = rtt
+		}
+		if rtt > stats.MaxRTT {
+			stats.MaxRTT = rtt
+		}
+		stats.AvgRTT = (total / time.Duration(stats.Received)).Round(time.Microsecond)
+	}
+	
+	return stats
+}
+
+// Ping sends a single ICMP echo request to addr and returns the round-trip
+// time of the reply. It uses a raw ICMP socket when permitted (root or
+// CAP_NET_RAW) and an unprivileged ICMP datagram socket
+// (net.ipv4.ping_group_range) otherwise, so the ping binary isn't needed.
+func Ping(ctx context.Context, addr *net.IPAddr, timeout time.Duration) (time.Duration, error) {
+	return echo(ctx, addr, timeout, 0)
+}
+
+// ProbeMTU sends a single echo request that fills an IP packet of mtu bytes
+// with fragmentation disabled, so the reply only arrives when every hop to
+// addr carries packets of that size
+func ProbeMTU(ctx context.Context, addr *net.IPAddr, mtu int, timeout time.Duration) (time.Duration, error) {
+	size := mtu - ipHeaderLen(addr)
+	if size < icmpHeaderLen+pingTokenLen {
+		return 0, fmt.Errorf("MTU %d is too small to probe", mtu)
+	}
+	return echo(ctx, addr, timeout, size)
+}
+
+// ipHeaderLen returns the IP header size for the address family of addr
+func ipHeaderLen(addr *net.IPAddr) int {
+	if addr.IP.To4() == nil {
+		return ipv6HeaderLen
+	}
+	return ipv4HeaderLen
+}
+
+// echo sends an echo request and waits for its reply. A non-zero size sets
+// the ICMP message size and forbids fragmentation, for path MTU probes.
+func echo(ctx context.Context, addr *net.IPAddr, timeout time.Duration, size int) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	ipv6 := addr.IP.To4() == nil
+	conn, dst, err := listenICMP(addr, ipv6)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	
+	if size > 0 {
+		if err := disableFragmentation(conn, ipv6); err != nil {
+			return 0, err
+		}
+	}
+	
+	// Unblock the read as soon as the timeout expires or ctx is cancelled
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+	
+	seq := uint16(atomic.AddUint32(&pingSeq, 1))
+	token := make([]byte, pingTokenLen)
+	binary.BigEndian.PutUint64(token, uint64(time.Now().UnixNano()))
+	
+	start := time.Now()
+	msg := echoRequest(ipv6, seq, token, size)
+	if _, err := conn.WriteTo(msg, dst); err != nil {
+		if errors.Is(err, syscall.EMSGSIZE) {
+			return 0, fmt.Errorf("%d-byte packet exceeds the local interface MTU", len(msg)+ipHeaderLen(addr))
+		}
+		return 0, fmt.Errorf("failed to send echo request: %w", err)
+	}
+	
+	buf := make([]byte, len(msg)+1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return 0, ctx.Err()
+			}
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return 0, fmt.Errorf("no echo reply within %s", timeout)
+			}
+			return 0, fmt.Errorf("failed to read echo reply: %w", err)
+		}
+		
+		// Raw sockets see every ICMP message for the host; keep reading
+		// until our own reply arrives
+		if isEchoReply(buf[:n], ipv6, seq, token) {
+			return time.Since(start).Round(time.Microsecond), nil
+		}
+	}
+}
+
+// listenICMP opens an ICMP socket for the address family of addr and returns
+// it with the destination address in the form the socket expects
+func listenICMP(addr *net.IPAddr, ipv6 bool) (net.PacketConn, net.Addr, error) {
+	network := "ip4:icmp"
+	if ipv6 {
+		network = "ip6:ipv6-icmp"
+	}
+	if conn, err := net.ListenPacket(network, ""); err == nil {
+		return conn, addr, nil
+	}
+	
+	// Unprivileged ICMP datagram socket; the kernel fills in the identifier
+	family, proto := syscall.AF_INET, syscall.IPPROTO_ICMP
+	if ipv6 {
+		family, proto = syscall.AF_INET6, syscall.IPPROTO_ICMPV6
+	}
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open ICMP socket (needs root, CAP_NET_RAW or the group in net.ipv4.ping_group_range): %w", err)
+	}
+	
+	file := os.NewFile(uintptr(fd), "icmp")
+	defer file.Close()
+	conn, err := net.FilePacketConn(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open ICMP socket: %w", err)
+	}
+	return conn, &net.UDPAddr{IP: addr.IP, Zone: addr.Zone}, nil
+}
+
+// disableFragmentation sets the Don't Fragment bit on outgoing packets (IPv6
+// never fragments in transit) and stops the kernel from fragmenting locally,
+// ignoring any cached path MTU
+func disableFragmentation(conn net.PacketConn, ipv6 bool) error {
+	level, option, value := syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_PROBE
+	if ipv6 {
+		level, option, value = syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, syscall.IPV6_PMTUDISC_PROBE
+	}
+	if err := setSockoptInt(conn, level, option, value); err != nil {
+		return fmt.Errorf("failed to disable fragmentation: %w", err)
+	}
+	return nil
+}
+
+// setSockoptInt sets an integer socket option on an ICMP socket
+func setSockoptInt(conn net.PacketConn, level, option, value int) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return errors.New("ICMP socket does not support socket options")
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), level, option, value)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// echoRequest builds an ICMP or ICMPv6 echo request carrying token, padded
+// to size bytes when size is larger. The kernel computes the ICMPv6 checksum,
+// which covers a pseudo-header.
+func echoRequest(ipv6 bool, seq uint16, token []byte, size int) []byte {
+	if size < icmpHeaderLen+len(token) {
+		size = icmpHeaderLen + len(token)
+	}
+	msg := make([]byte, size)
+	msg[0] = icmpEchoRequest
+	if ipv6 {
+		msg[0] = icmpv6EchoRequest
+	}
+	binary.BigEndian.PutUint16(msg[4:], uint16(os.Getpid()))
+	binary.BigEndian.PutUint16(msg[6:], seq)
+	copy(msg[8:], token)
+	
+	if !ipv6 {
+		binary.BigEndian.PutUint16(msg[2:], checksum(msg))
+	}
+	return msg
+}
+
+// isEchoReply reports whether msg is the reply to our echo request. The
+// identifier isn't compared, as datagram sockets replace it with their own.
+func isEchoReply(msg []byte, ipv6 bool, seq uint16, token []byte) bool {
+	if len(msg) < 8+len(token) {
+		return false
+	}
+	
+	replyType := byte(icmpEchoReply)
+	if ipv6 {
+		replyType = icmpv6EchoReply
+	}
+	return msg[0] == replyType &&
+		binary.BigEndian.Uint16(msg[6:]) == seq &&
+		string(msg[8:8+len(token)]) == string(token)
+}
+
+// checksum computes the Internet checksum (RFC 1071) of b
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return ^uint16(sum)
+}
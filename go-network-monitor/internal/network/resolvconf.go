@@ -0,0 +1,89 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// resolvedManagedFiles are the files /etc/resolv.conf links to when
+// systemd-resolved manages it
+var resolvedManagedFiles = []string{
+	"/run/systemd/resolve/stub-resolv.conf",
+	"/run/systemd/resolve/resolv.conf",
+	"/usr/lib/systemd/resolv.conf",
+	"/lib/systemd/resolv.conf",
+}
+
+// ResolvConf describes /etc/resolv.conf
+type ResolvConf struct {
+	Target            string    // Symlink target, empty for a regular file
+	Nameservers       []string
+	ManagedByResolved bool      // Links to one of the files resolved maintains
+}
+
+// UsesResolvedStub reports whether the only nameservers are the
+// systemd-resolved stub listeners
+func (rc *ResolvConf) UsesResolvedStub() bool {
+	for _, server := range rc.Nameservers {
+		if !isResolvedStub(server) {
+			return false
+		}
+	}
+	return len(rc.Nameservers) > 0
+}
+
+// InspectResolvConf reads /etc/resolv.conf, failing when it's missing or a
+// symlink to a file that doesn't exist (yet), the classic boot failure when
+// the manager that should write it hasn't started
+func InspectResolvConf() (*ResolvConf, error) {
+	rc := &ResolvConf{}
+	info, err := os.Lstat(resolvConfPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s does not exist", resolvConfPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", resolvConfPath, err)
+	}
+	
+	if info.Mode()&os.ModeSymlink != 0 {
+		rc.Target, err = os.Readlink(resolvConfPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read symlink %s: %w", resolvConfPath, err)
+		}
+		target := rc.Target
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(resolvConfPath), target)
+		}
+		if _, err := os.Stat(target); err != nil {
+			return nil, fmt.Errorf("%s is a dangling symlink to %s", resolvConfPath, rc.Target)
+		}
+		for _, managed := range resolvedManagedFiles {
+			if filepath.Clean(target) == managed {
+				rc.ManagedByResolved = true
+			}
+		}
+	}
+	
+	rc.Nameservers, err = readNameservers(resolvConfPath)
+	if err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// ProbeNameserver sends a query for the root name servers to server and
+// returns how long any answer took; the answer's content doesn't matter, only
+// that the server responds
+func ProbeNameserver(ctx context.Context, server string, timeout time.Duration) (time.Duration, error) {
+	query, err := dnsQuery(".", 2, dnsFlagRecursion, false)
+	if err != nil {
+		return 0, err
+	}
+	// Any response to our query, whatever its rcode, shows the server is up
+	_, rtt, err := dnsExchange(ctx, server, query, timeout)
+	return rtt, err
+}
@@ -1,12 +1,23 @@
 package network
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 	
 	"github.com/vishvananda/netlink"
 )
 
+// Routing table selectors
+const (
+	AllRouteTables = syscall.RT_TABLE_UNSPEC // Every table except the local table
+	MainRouteTable = syscall.RT_TABLE_MAIN
+)
+
 // RouteType represents different types of routes
 type RouteType string
 
@@ -23,7 +34,12 @@ type RouteEntry struct {
 	Gateway       net.IP
 	Interface     string
 	Metric        int
+	Table         int
 	Type          RouteType
+	IPv6          bool
+	Protocol      int            // Route origin, e.g. syscall.RTPROT_RA for router advertisements
+	Expires       time.Duration  // Remaining lifetime of an expiring IPv6 route (0 = permanent)
+	NextHops      []NextHop      // Paths of a multipath (ECMP) route, which has no Gateway or Interface of its own
 }
 
 // RoutingTableStatus represents the status of the routing table
@@ -35,19 +51,71 @@ type RoutingTableStatus struct {
 	HasDefaultRoute bool
 	DefaultGateway  net.IP
 	DefaultInterface string
+	DefaultTable    int
+	
+	// IPv6 routes, counted separately
+	TotalRoutes6      int
+	DefaultRoutes6    int
+	NetworkRoutes6    int
+	HostRoutes6       int
+	HasDefaultRoute6  bool
+	DefaultGateway6   net.IP
+	DefaultInterface6 string
+	DefaultExpires6   time.Duration  // Remaining lifetime of an RA-learned default route (0 = permanent)
+	IPv6Err           error          // Set when the IPv6 routes couldn't be listed, e.g. IPv6 disabled
 }
 
+// PolicyRule is an ip rule selecting a routing table
+type PolicyRule struct {
+	Priority int
+	IPv6     bool
+	Selector string  // What the rule matches, e.g. "from 10.1.0.0/16 iif mgmt0" or "from all"
+	Table    int     // 0 for rules that don't look up a table (goto, unreachable)
+}
+
+// userHZ is the tick rate of the clock_t values in rta_cacheinfo
+const userHZ = 100
+
 // RoutingMonitor handles routing table monitoring
-type RoutingMonitor struct{}
+type RoutingMonitor struct {
+	table int
+}
 
-// NewRoutingMonitor creates a new routing monitor
-func NewRoutingMonitor() *RoutingMonitor {
-	return &RoutingMonitor{}
+// NewRoutingMonitor creates a new routing monitor for the given table
+// (MainRouteTable, AllRouteTables or a specific table ID)
+func NewRoutingMonitor(table int) *RoutingMonitor {
+	return &RoutingMonitor{table: table}
+}
+
+// listRoutes returns the routes of the given address family (netlink.FAMILY_V4
+// or netlink.FAMILY_V6) in the selected routing table(s)
+func listRoutes(table, family int) ([]netlink.Route, error) {
+	if table == MainRouteTable {
+		return netlink.RouteList(nil, family)
+	}
+	
+	routes, err := netlink.RouteListFiltered(family, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return nil, err
+	}
+	
+	if table != AllRouteTables {
+		return routes, nil
+	}
+	
+	// The local table only holds local and broadcast addresses
+	var filtered []netlink.Route
+	for _, route := range routes {
+		if route.Table != syscall.RT_TABLE_LOCAL {
+			filtered = append(filtered, route)
+		}
+	}
+	return filtered, nil
 }
 
 // CheckRoutingTable analyzes the routing table
 func (rm *RoutingMonitor) CheckRoutingTable() (*RoutingTableStatus, error) {
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	routes, err := listRoutes(rm.table, netlink.FAMILY_V4)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routing table: %w", err)
 	}
@@ -63,6 +131,7 @@ func (rm *RoutingMonitor) CheckRoutingTable() (*RoutingTableStatus, error) {
 			status.DefaultRoutes++
 			status.HasDefaultRoute = true
 			status.DefaultGateway = route.Gw
+			status.DefaultTable = route.Table
 			
 			if route.LinkIndex > 0 {
 				if link, err := netlink.LinkByIndex(route.LinkIndex); err == nil {
@@ -80,23 +149,82 @@ func (rm *RoutingMonitor) CheckRoutingTable() (*RoutingTableStatus, error) {
 		}
 	}
 	
+	status.IPv6Err = rm.checkRoutingTable6(status)
+	
 	return status, nil
 }
 
-// GetDefaultRoutes returns all default routes
+// checkRoutingTable6 fills in the IPv6 counters of status
+func (rm *RoutingMonitor) checkRoutingTable6(status *RoutingTableStatus) error {
+	routes, err := listRoutes(rm.table, netlink.FAMILY_V6)
+	if err != nil {
+		return fmt.Errorf("failed to get IPv6 routing table: %w", err)
+	}
+	
+	for _, route := range routes {
+		status.TotalRoutes6++
+		
+		if route.Dst != nil {
+			// Check if it's a host route (/128)
+			if ones, bits := route.Dst.Mask.Size(); ones == bits {
+				status.HostRoutes6++
+			} else {
+				status.NetworkRoutes6++
+			}
+			continue
+		}
+		
+		// Default route (::/0); unreachable and prohibit defaults don't count
+		if route.Type != syscall.RTN_UNICAST {
+			continue
+		}
+		status.DefaultRoutes6++
+		if status.HasDefaultRoute6 {
+			continue
+		}
+		status.HasDefaultRoute6 = true
+		status.DefaultGateway6 = route.Gw
+		
+		if route.LinkIndex > 0 {
+			if link, err := netlink.LinkByIndex(route.LinkIndex); err == nil {
+				status.DefaultInterface6 = link.Attrs().Name
+			}
+		}
+		if route.Protocol == syscall.RTPROT_RA {
+			if expiries, err := defaultRouteExpiries6(); err == nil {
+				status.DefaultExpires6 = expiries[expiryKey(route.Gw, route.LinkIndex)]
+			}
+		}
+	}
+	
+	return nil
+}
+
+// GetDefaultRoutes returns all default routes, IPv4 first. IPv6 routes
+// learned from router advertisements carry their remaining lifetime.
 func (rm *RoutingMonitor) GetDefaultRoutes() ([]RouteEntry, error) {
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	return rm.TableDefaultRoutes(rm.table)
+}
+
+// TableDefaultRoutes returns the default routes of a routing table other
+// than the monitored one, e.g. a policy routing table
+func (rm *RoutingMonitor) TableDefaultRoutes(table int) ([]RouteEntry, error) {
+	routes, count4, err := listAllFamilies(table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routes: %w", err)
 	}
 	
 	var defaultRoutes []RouteEntry
-	for _, route := range routes {
-		if route.Dst == nil { // Default route
+	for i, route := range routes {
+		if route.Dst == nil && route.Type == syscall.RTN_UNICAST { // Default route
 			entry := RouteEntry{
-				Gateway: route.Gw,
-				Metric:  route.Priority,
-				Type:    DefaultRoute,
+				Gateway:  route.Gw,
+				Metric:   route.Priority,
+				Table:    route.Table,
+				Type:     DefaultRoute,
+				IPv6:     i >= count4,
+				Protocol: route.Protocol,
+				NextHops: multipathHops(route),
 			}
 			
 			if route.LinkIndex > 0 {
@@ -109,30 +237,36 @@ func (rm *RoutingMonitor) GetDefaultRoutes() ([]RouteEntry, error) {
 		}
 	}
 	
+	setExpiries(defaultRoutes)
+	
 	return defaultRoutes, nil
 }
 
-// GetAllRoutes returns all routes in the routing table
+// GetAllRoutes returns all IPv4 and IPv6 routes in the routing table
 func (rm *RoutingMonitor) GetAllRoutes() ([]RouteEntry, error) {
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	routes, count4, err := listAllFamilies(rm.table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routes: %w", err)
 	}
 	
 	var routeEntries []RouteEntry
-	for _, route := range routes {
+	for i, route := range routes {
 		entry := RouteEntry{
 			Destination: route.Dst,
 			Gateway:     route.Gw,
 			Metric:      route.Priority,
+			Table:       route.Table,
+			IPv6:        i >= count4,
+			Protocol:    route.Protocol,
+			NextHops:    multipathHops(route),
 		}
 		
 		// Determine route type
 		if route.Dst == nil {
 			entry.Type = DefaultRoute
 		} else {
-			ones, _ := route.Dst.Mask.Size()
-			if ones == 32 {
+			ones, bits := route.Dst.Mask.Size()
+			if ones == bits {
 				entry.Type = HostRoute
 			} else {
 				entry.Type = NetworkRoute
@@ -149,9 +283,248 @@ func (rm *RoutingMonitor) GetAllRoutes() ([]RouteEntry, error) {
 		routeEntries = append(routeEntries, entry)
 	}
 	
+	setExpiries(routeEntries)
+	
 	return routeEntries, nil
 }
 
+// FindRoute returns the first route to a destination (nil for a default
+// route) that also matches the gateway and interface when they are given,
+// or nil if there is none. A multipath route matches when one of its paths
+// does.
+func FindRoute(routes []RouteEntry, destination *net.IPNet, gateway net.IP, device string) *RouteEntry {
+	for i := range routes {
+		route := &routes[i]
+		if !sameDestination(route.Destination, destination) {
+			continue
+		}
+		if len(route.NextHops) == 0 && hopMatches(route.Gateway, route.Interface, gateway, device) {
+			return route
+		}
+		for _, hop := range route.NextHops {
+			if hopMatches(hop.Gateway, hop.Interface, gateway, device) {
+				return route
+			}
+		}
+	}
+	return nil
+}
+
+// hopMatches reports whether a path goes via gateway and out of device, each
+// only compared when given
+func hopMatches(hopGateway net.IP, hopDevice string, gateway net.IP, device string) bool {
+	if gateway != nil && !gateway.Equal(hopGateway) {
+		return false
+	}
+	return device == "" || device == hopDevice
+}
+
+// multipathHops returns the paths of a multipath route, or nil for an
+// ordinary route; the kernel reports weight - 1 as the hop count
+func multipathHops(route netlink.Route) []NextHop {
+	var hops []NextHop
+	for _, path := range route.MultiPath {
+		hops = append(hops, NextHop{
+			Gateway:   path.Gw,
+			Interface: linkName(path.LinkIndex),
+			Weight:    path.Hops + 1,
+			OnLink:    path.Flags&int(netlink.FLAG_ONLINK) != 0,
+		})
+	}
+	return hops
+}
+
+// sameDestination compares route destinations, treating nil and a /0 prefix
+// as the default route
+func sameDestination(a, b *net.IPNet) bool {
+	if a == nil || b == nil {
+		return (a == nil || isDefault(a)) && (b == nil || isDefault(b))
+	}
+	aOnes, aBits := a.Mask.Size()
+	bOnes, bBits := b.Mask.Size()
+	return a.IP.Equal(b.IP) && aOnes == bOnes && aBits == bBits
+}
+
+// isDefault reports whether a prefix is 0.0.0.0/0 or ::/0
+func isDefault(prefix *net.IPNet) bool {
+	ones, _ := prefix.Mask.Size()
+	return ones == 0
+}
+
+// listAllFamilies returns the IPv4 routes followed by the IPv6 routes of the
+// selected table(s), and how many of them are IPv4. IPv6 being unavailable
+// isn't an error.
+func listAllFamilies(table int) ([]netlink.Route, int, error) {
+	routes, err := listRoutes(table, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, 0, err
+	}
+	count4 := len(routes)
+	if routes6, err := listRoutes(table, netlink.FAMILY_V6); err == nil {
+		routes = append(routes, routes6...)
+	}
+	return routes, count4, nil
+}
+
+// ListPolicyRules returns the IPv4 ip rules followed by the IPv6 ones, each
+// in priority order. IPv6 being unavailable isn't an error.
+func ListPolicyRules() ([]PolicyRule, error) {
+	var rules []PolicyRule
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		list, err := netlink.RuleList(family)
+		if err != nil {
+			if family == netlink.FAMILY_V6 {
+				break
+			}
+			return nil, fmt.Errorf("failed to list ip rules: %w", err)
+		}
+		for _, rule := range list {
+			// The priority 0 rule (lookup local) comes without a priority
+			priority := rule.Priority
+			if priority < 0 {
+				priority = 0
+			}
+			rules = append(rules, PolicyRule{
+				Priority: priority,
+				IPv6:     family == netlink.FAMILY_V6,
+				Selector: ruleSelector(rule),
+				Table:    rule.Table,
+			})
+		}
+	}
+	return rules, nil
+}
+
+// ruleSelector describes what an ip rule matches the way "ip rule" prints it
+func ruleSelector(rule netlink.Rule) string {
+	var parts []string
+	if rule.Invert {
+		parts = append(parts, "not")
+	}
+	if rule.Src != nil {
+		parts = append(parts, "from "+rule.Src.String())
+	} else {
+		parts = append(parts, "from all")
+	}
+	if rule.Dst != nil {
+		parts = append(parts, "to "+rule.Dst.String())
+	}
+	if rule.Mark > 0 {
+		mark := fmt.Sprintf("fwmark %#x", rule.Mark)
+		if rule.Mask > 0 && rule.Mask != 0xffffffff {
+			mark += fmt.Sprintf("/%#x", rule.Mask)
+		}
+		parts = append(parts, mark)
+	}
+	if rule.IifName != "" {
+		parts = append(parts, "iif "+rule.IifName)
+	}
+	if rule.OifName != "" {
+		parts = append(parts, "oif "+rule.OifName)
+	}
+	return strings.Join(parts, " ")
+}
+
+// String returns a rule the way "ip rule" prints it
+func (pr *PolicyRule) String() string {
+	if pr.Table == 0 {
+		return fmt.Sprintf("%d: %s", pr.Priority, pr.Selector)
+	}
+	return fmt.Sprintf("%d: %s lookup %s", pr.Priority, pr.Selector, tableName(pr.Table))
+}
+
+// tableName names the reserved routing tables the way iproute2 does
+func tableName(table int) string {
+	switch table {
+	case syscall.RT_TABLE_MAIN:
+		return "main"
+	case syscall.RT_TABLE_LOCAL:
+		return "local"
+	case syscall.RT_TABLE_DEFAULT:
+		return "default"
+	}
+	return strconv.Itoa(table)
+}
+
+// setExpiries fills in the remaining lifetime of RA-learned IPv6 default routes
+func setExpiries(entries []RouteEntry) {
+	var expiries map[string]time.Duration
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Type != DefaultRoute || entry.Protocol != syscall.RTPROT_RA {
+			continue
+		}
+		if expiries == nil {
+			var err error
+			if expiries, err = defaultRouteExpiries6(); err != nil {
+				return
+			}
+		}
+		
+		linkIndex := 0
+		if link, err := netlink.LinkByName(entry.Interface); err == nil {
+			linkIndex = link.Attrs().Index
+		}
+		entry.Expires = expiries[expiryKey(entry.Gateway, linkIndex)]
+	}
+}
+
+// expiryKey identifies a default route by gateway and outgoing interface
+func expiryKey(gateway net.IP, linkIndex int) string {
+	return fmt.Sprintf("%s/%d", gateway, linkIndex)
+}
+
+// defaultRouteExpiries6 returns the remaining lifetime of the expiring IPv6
+// default routes, keyed by expiryKey. netlink.Route doesn't expose
+// RTA_CACHEINFO, so the routes are dumped directly.
+func defaultRouteExpiries6() (map[string]time.Duration, error) {
+	rib, err := syscall.NetlinkRIB(syscall.RTM_GETROUTE, syscall.AF_INET6)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := syscall.ParseNetlinkMessage(rib)
+	if err != nil {
+		return nil, err
+	}
+	
+	expiries := make(map[string]time.Duration)
+	for _, msg := range msgs {
+		// rtmsg: family, dst_len, ...; only default routes (dst_len 0) are wanted
+		if msg.Header.Type != syscall.RTM_NEWROUTE || len(msg.Data) < syscall.SizeofRtMsg || msg.Data[1] != 0 {
+			continue
+		}
+		attrs, err := syscall.ParseNetlinkRouteAttr(&msg)
+		if err != nil {
+			continue
+		}
+		
+		var gateway net.IP
+		var linkIndex int
+		var expires time.Duration
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case syscall.RTA_GATEWAY:
+				gateway = net.IP(attr.Value)
+			case syscall.RTA_OIF:
+				if len(attr.Value) >= 4 {
+					linkIndex = int(binary.NativeEndian.Uint32(attr.Value))
+				}
+			case syscall.RTA_CACHEINFO:
+				// struct rta_cacheinfo: rta_clntref, rta_lastuse, rta_expires, ...
+				if len(attr.Value) >= 12 {
+					ticks := int32(binary.NativeEndian.Uint32(attr.Value[8:12]))
+					expires = time.Duration(ticks) * time.Second / userHZ
+				}
+			}
+		}
+		if expires > 0 {
+			expiries[expiryKey(gateway, linkIndex)] = expires
+		}
+	}
+	
+	return expiries, nil
+}
+
 // String returns a string representation of a route entry
 func (re *RouteEntry) String() string {
 	var dest string
@@ -161,13 +534,39 @@ func (re *RouteEntry) String() string {
 		dest = re.Destination.String()
 	}
 	
-	if re.Gateway != nil {
+	var route string
+	if len(re.NextHops) > 0 {
+		route = dest
+		if re.Metric > 0 {
+			route += fmt.Sprintf(" metric %d", re.Metric)
+		}
+		for _, hop := range re.NextHops {
+			if hop.Gateway != nil {
+				route += fmt.Sprintf(" nexthop via %s dev %s weight %d", hop.Gateway, hop.Interface, hop.Weight)
+			} else {
+				route += fmt.Sprintf(" nexthop dev %s weight %d", hop.Interface, hop.Weight)
+			}
+		}
+	} else if re.Gateway != nil {
 		if re.Metric > 0 {
-			return fmt.Sprintf("%s via %s dev %s metric %d", dest, re.Gateway, re.Interface, re.Metric)
+			route = fmt.Sprintf("%s via %s dev %s metric %d", dest, re.Gateway, re.Interface, re.Metric)
 		} else {
-			return fmt.Sprintf("%s via %s dev %s", dest, re.Gateway, re.Interface)
+			route = fmt.Sprintf("%s via %s dev %s", dest, re.Gateway, re.Interface)
 		}
 	} else {
-		return fmt.Sprintf("%s dev %s", dest, re.Interface)
+		route = fmt.Sprintf("%s dev %s", dest, re.Interface)
+	}
+	
+	if re.Table != 0 && re.Table != MainRouteTable {
+		route += fmt.Sprintf(" table %d", re.Table)
 	}
+	
+	if re.Protocol == syscall.RTPROT_RA {
+		route += " proto ra"
+	}
+	if re.Expires > 0 {
+		route += fmt.Sprintf(" expires %s", re.Expires.Round(time.Second))
+	}
+	
+	return route
 }
\ No newline at end of file
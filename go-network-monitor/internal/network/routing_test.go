@@ -0,0 +1,48 @@
+package network
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFindRouteMultipath(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.20.0.0/16")
+	routes := []RouteEntry{
+		{Destination: subnet, Gateway: net.ParseIP("10.0.0.254"), Interface: "eno1", Type: NetworkRoute},
+		// default nexthop via 10.0.0.1 dev bond0 weight 1 nexthop via 10.0.1.1 dev bond1 weight 1
+		{Type: DefaultRoute, NextHops: []NextHop{
+			{Gateway: net.ParseIP("10.0.0.1"), Interface: "bond0", Weight: 1},
+			{Gateway: net.ParseIP("10.0.1.1"), Interface: "bond1", Weight: 1},
+		}},
+	}
+	
+	tests := []struct {
+		name        string
+		destination *net.IPNet
+		gateway     net.IP
+		device      string
+		found       bool
+	}{
+		{name: "default", found: true},
+		{name: "default via first path", gateway: net.ParseIP("10.0.0.1"), found: true},
+		{name: "default via second path", gateway: net.ParseIP("10.0.1.1"), found: true},
+		{name: "default dev", device: "bond1", found: true},
+		{name: "default via and dev of one path", gateway: net.ParseIP("10.0.1.1"), device: "bond1", found: true},
+		{name: "default via and dev of different paths", gateway: net.ParseIP("10.0.0.1"), device: "bond1", found: false},
+		{name: "default via unknown gateway", gateway: net.ParseIP("10.0.2.1"), found: false},
+		{name: "subnet", destination: subnet, gateway: net.ParseIP("10.0.0.254"), device: "eno1", found: true},
+		{name: "subnet wrong device", destination: subnet, device: "bond0", found: false},
+	}
+	
+	for _, tt := range tests {
+		route := FindRoute(routes, tt.destination, tt.gateway, tt.device)
+		if (route != nil) != tt.found {
+			t.Errorf("%s: FindRoute() = %v, want found %t", tt.name, route, tt.found)
+		}
+	}
+	
+	want := "default nexthop via 10.0.0.1 dev bond0 weight 1 nexthop via 10.0.1.1 dev bond1 weight 1"
+	if got := routes[1].String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
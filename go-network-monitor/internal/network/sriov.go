@@ -0,0 +1,114 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// vfLinkStates names the administrative VF link states set with
+// "ip link set <pf> vf <n> state"
+var vfLinkStates = map[uint32]string{
+	netlink.VF_LINK_STATE_AUTO:    "auto",
+	netlink.VF_LINK_STATE_ENABLE:  "enable",
+	netlink.VF_LINK_STATE_DISABLE: "disable",
+}
+
+// VirtualFunction is an SR-IOV virtual function of a physical function
+type VirtualFunction struct {
+	Index     int
+	Netdev    string  // Host interface of the VF, empty when bound to vfio-pci or moved to another namespace
+	Driver    string  // e.g. "iavf", "mlx5_core" or "vfio-pci" for a VF passed through to a guest
+	MAC       string
+	LinkState string  // "auto" (follows the PF), "enable" or "disable"
+}
+
+// SRIOVStatus represents the virtual functions of an SR-IOV physical function
+type SRIOVStatus struct {
+	PF       string
+	TotalVFs int  // VFs the device supports
+	VFs      []VirtualFunction
+}
+
+// VFParent returns the physical function an SR-IOV virtual function belongs
+// to and its VF index; ok is false for interfaces that aren't VFs
+func (im *InterfaceMonitor) VFParent(interfaceName string) (pf string, index int, ok bool) {
+	device := fmt.Sprintf("/sys/class/net/%s/device", interfaceName)
+	physfn := device + "/physfn"
+	if _, err := os.Stat(physfn); err != nil {
+		return "", 0, false
+	}
+	
+	pf = "unknown"
+	if netdevs, err := os.ReadDir(physfn + "/net"); err == nil && len(netdevs) > 0 {
+		pf = netdevs[0].Name()
+	}
+	
+	// The VF index is the virtfn<N> link of the PF that resolves to this device
+	index = -1
+	self, err := filepath.EvalSymlinks(device)
+	if err != nil {
+		return pf, index, true
+	}
+	virtfns, _ := filepath.Glob(physfn + "/virtfn*")
+	for _, virtfn := range virtfns {
+		if target, err := filepath.EvalSymlinks(virtfn); err == nil && target == self {
+			index, _ = strconv.Atoi(strings.TrimPrefix(filepath.Base(virtfn), "virtfn"))
+			break
+		}
+	}
+	return pf, index, true
+}
+
+// IsSRIOVPF checks if an interface is an SR-IOV physical function with
+// virtual functions enabled
+func (im *InterfaceMonitor) IsSRIOVPF(interfaceName string) bool {
+	numVFs, err := strconv.Atoi(readSysfs(fmt.Sprintf("/sys/class/net/%s/device/sriov_numvfs", interfaceName)))
+	return err == nil && numVFs > 0
+}
+
+// CheckSRIOVStatus lists the enabled virtual functions of a physical function
+// with their host interface, driver and administrative link state. VFs
+// passed through to guests are bound to vfio-pci and have no host interface.
+func (im *InterfaceMonitor) CheckSRIOVStatus(interfaceName string) (*SRIOVStatus, error) {
+	device := fmt.Sprintf("/sys/class/net/%s/device", interfaceName)
+	numVFs, err := strconv.Atoi(readSysfs(device + "/sriov_numvfs"))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an SR-IOV physical function", interfaceName)
+	}
+	
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		return nil, fmt.Errorf("interface %s not found: %w", interfaceName, err)
+	}
+	vfInfo := make(map[int]netlink.VfInfo)
+	for _, vf := range link.Attrs().Vfs {
+		vfInfo[vf.ID] = vf
+	}
+	
+	status := &SRIOVStatus{PF: interfaceName}
+	status.TotalVFs, _ = strconv.Atoi(readSysfs(device + "/sriov_totalvfs"))
+	for i := 0; i < numVFs; i++ {
+		virtfn := fmt.Sprintf("%s/virtfn%d", device, i)
+		vf := VirtualFunction{Index: i, LinkState: "unknown"}
+		if netdevs, err := os.ReadDir(virtfn + "/net"); err == nil && len(netdevs) > 0 {
+			vf.Netdev = netdevs[0].Name()
+		}
+		if driver, err := os.Readlink(virtfn + "/driver"); err == nil {
+			vf.Driver = filepath.Base(driver)
+		}
+		if info, ok := vfInfo[i]; ok {
+			vf.MAC = info.Mac.String()
+			if state, ok := vfLinkStates[info.LinkState]; ok {
+				vf.LinkState = state
+			}
+		}
+		status.VFs = append(status.VFs, vf)
+	}
+	
+	return status, nil
+}
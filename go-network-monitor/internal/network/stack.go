@@ -0,0 +1,84 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+	
+	"github.com/vishvananda/netlink"
+)
+
+// maxStackDepth bounds lower-device resolution against loops
+const maxStackDepth = 8
+
+// DeviceStack is an interface and the devices beneath it, e.g.
+// vlan100 -> bond0 -> [eno1 eno2]
+type DeviceStack struct {
+	Name    string
+	Parent  *DeviceStack    // Device a VLAN, macvlan or ipvlan sits on
+	Members []*DeviceStack  // Slaves or ports enslaved to a bond, bridge or team
+}
+
+// String renders the stack top-down
+func (ds *DeviceStack) String() string {
+	s := ds.Name
+	if len(ds.Members) > 0 {
+		var members []string
+		for _, member := range ds.Members {
+			members = append(members, member.String())
+		}
+		s += " -> [" + strings.Join(members, " ") + "]"
+	}
+	if ds.Parent != nil {
+		s += " -> " + ds.Parent.String()
+	}
+	return s
+}
+
+// ResolveStack follows an interface's parent link (IFLA_LINK) and the
+// devices enslaved to it (IFLA_MASTER) down to the physical NICs
+func (im *InterfaceMonitor) ResolveStack(interfaceName string) (*DeviceStack, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+	
+	byIndex := make(map[int]netlink.Link)
+	var top netlink.Link
+	for _, link := range links {
+		byIndex[link.Attrs().Index] = link
+		if link.Attrs().Name == interfaceName {
+			top = link
+		}
+	}
+	if top == nil {
+		return nil, fmt.Errorf("interface %s not found", interfaceName)
+	}
+	
+	return resolveStack(top, links, byIndex, 0), nil
+}
+
+// resolveStack builds the stack beneath link. Only VLAN-like devices follow
+// their parent: the IFLA_LINK of a veth or tunnel is a peer or underlay
+// rather than a device it is stacked on.
+func resolveStack(link netlink.Link, links []netlink.Link, byIndex map[int]netlink.Link, depth int) *DeviceStack {
+	attrs := link.Attrs()
+	stack := &DeviceStack{Name: attrs.Name}
+	if depth >= maxStackDepth {
+		return stack
+	}
+	
+	switch linkKinds[link.Type()] {
+	case VLAN, MACVLAN, IPVLAN:
+		if parent, ok := byIndex[attrs.ParentIndex]; ok && attrs.ParentIndex != attrs.Index {
+			stack.Parent = resolveStack(parent, links, byIndex, depth+1)
+		}
+	}
+	
+	for _, member := range links {
+		if member.Attrs().MasterIndex == attrs.Index {
+			stack.Members = append(stack.Members, resolveStack(member, links, byIndex, depth+1))
+		}
+	}
+	
+	return stack
+}
@@ -0,0 +1,45 @@
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// TLSCertificate describes the leaf certificate of a verified TLS endpoint
+type TLSCertificate struct {
+	Subject  string
+	Issuer   string
+	NotAfter time.Time
+	Elapsed  time.Duration  // Connect and handshake time
+}
+
+// CheckTLS connects to a host:port endpoint and performs a TLS handshake,
+// verifying the certificate chain against the system trust store and the
+// host name, within timeout
+func CheckTLS(ctx context.Context, endpoint string, timeout time.Duration) (*TLSCertificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	
+	start := time.Now()
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: host}}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	
+	leaf := conn.(*tls.Conn).ConnectionState().PeerCertificates[0]
+	return &TLSCertificate{
+		Subject:  leaf.Subject.CommonName,
+		Issuer:   leaf.Issuer.CommonName,
+		NotAfter: leaf.NotAfter,
+		Elapsed:  time.Since(start).Round(time.Microsecond),
+	}, nil
+}
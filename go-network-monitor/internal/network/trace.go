@@ -0,0 +1,127 @@
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ICMP error messages that quote the probe which caused them
+const (
+	icmpDestUnreachable   = 3
+	icmpTimeExceeded      = 11
+	icmpv6DestUnreachable = 1
+	icmpv6TimeExceeded    = 3
+)
+
+// Hop is the host that answered a probe sent with a limited hop count
+type Hop struct {
+	TTL     int
+	Addr    net.IP
+	RTT     time.Duration
+	Reached bool           // The destination itself answered
+}
+
+// TraceHop sends an echo request toward addr that expires after ttl hops and
+// returns the host that answered it: the router at that hop with a Time
+// Exceeded message, or addr itself with an echo reply. ICMP errors are only
+// delivered to raw sockets, so this needs root or CAP_NET_RAW.
+func TraceHop(ctx context.Context, addr *net.IPAddr, ttl int, timeout time.Duration) (*Hop, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	ipv6 := addr.IP.To4() == nil
+	network, level, option := "ip4:icmp", syscall.IPPROTO_IP, syscall.IP_TTL
+	if ipv6 {
+		network, level, option = "ip6:ipv6-icmp", syscall.IPPROTO_IPV6, syscall.IPV6_UNICAST_HOPS
+	}
+	conn, err := net.ListenPacket(network, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw ICMP socket (needs root or CAP_NET_RAW): %w", err)
+	}
+	defer conn.Close()
+	
+	if err := setSockoptInt(conn, level, option, ttl); err != nil {
+		return nil, fmt.Errorf("failed to set hop limit: %w", err)
+	}
+	
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+	
+	seq := uint16(atomic.AddUint32(&pingSeq, 1))
+	token := make([]byte, pingTokenLen)
+	binary.BigEndian.PutUint64(token, uint64(time.Now().UnixNano()))
+	
+	start := time.Now()
+	if _, err := conn.WriteTo(echoRequest(ipv6, seq, token, 0), addr); err != nil {
+		return nil, fmt.Errorf("failed to send probe: %w", err)
+	}
+	
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil, ctx.Err()
+			}
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil, fmt.Errorf("no response within %s", timeout)
+			}
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		
+		msg := buf[:n]
+		hop := &Hop{TTL: ttl, RTT: time.Since(start).Round(time.Microsecond)}
+		if ipAddr, ok := from.(*net.IPAddr); ok {
+			hop.Addr = ipAddr.IP
+		}
+		
+		if isEchoReply(msg, ipv6, seq, token) {
+			hop.Reached = true
+			return hop, nil
+		}
+		
+		// Raw sockets see every ICMP error for the host; only those quoting
+		// our probe are answers to it
+		if !quotesProbe(msg, ipv6, seq) {
+			continue
+		}
+		switch {
+		case !ipv6 && msg[0] == icmpTimeExceeded, ipv6 && msg[0] == icmpv6TimeExceeded:
+			return hop, nil
+		case !ipv6 && msg[0] == icmpDestUnreachable, ipv6 && msg[0] == icmpv6DestUnreachable:
+			return hop, fmt.Errorf("%s reports destination unreachable (code %d)", hop.Addr, msg[1])
+		}
+	}
+}
+
+// quotesProbe reports whether the ICMP error msg quotes our echo request:
+// after the 8-byte ICMP header comes the original IP header and the first 8
+// bytes of the original ICMP message, which hold the identifier and sequence
+func quotesProbe(msg []byte, ipv6 bool, seq uint16) bool {
+	if len(msg) < icmpHeaderLen+ipv4HeaderLen {
+		return false
+	}
+	
+	inner := msg[icmpHeaderLen:]
+	headerLen, requestType := int(inner[0]&0x0f)*4, byte(icmpEchoRequest)
+	if ipv6 {
+		headerLen, requestType = ipv6HeaderLen, icmpv6EchoRequest
+	}
+	if len(inner) < headerLen+icmpHeaderLen {
+		return false
+	}
+	
+	quoted := inner[headerLen:]
+	return quoted[0] == requestType &&
+		binary.BigEndian.Uint16(quoted[4:]) == uint16(os.Getpid()) &&
+		binary.BigEndian.Uint16(quoted[6:]) == seq
+}
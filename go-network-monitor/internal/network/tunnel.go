@@ -0,0 +1,184 @@
+package network
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+	
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+)
+
+// WireGuard generic netlink interface (include/uapi/linux/wireguard.h)
+const (
+	wgGenlName           = "wireguard"
+	wgGenlVersion        = 1
+	wgCmdGetDevice       = 0
+	wgDeviceAttrIfname   = 2
+	wgDeviceAttrPeers    = 8
+	wgPeerAttrPublicKey  = 1
+	wgPeerAttrEndpoint   = 4
+	wgPeerAttrHandshake  = 6
+	nlaTypeMask          = 0x3fff  // Strips NLA_F_NESTED and NLA_F_NET_BYTEORDER
+)
+
+// WireGuardPeer is a peer of a WireGuard interface
+type WireGuardPeer struct {
+	PublicKey     string     // Base64, as wg shows it
+	Endpoint      string     // Empty until the peer's address is known
+	LastHandshake time.Time  // Zero when no handshake has completed
+}
+
+// TunnelStatus represents the status of a tunnel interface
+type TunnelStatus struct {
+	Name     string
+	Kind     string           // "wireguard", "tun", "tap" or the link kind of other tunnels, e.g. "gre"
+	Peers    []WireGuardPeer  // WireGuard only
+	Attached bool             // tun/tap only: a process such as the VPN daemon has the device open
+}
+
+// IsTunnelInterface checks if an interface is a tunnel: WireGuard, tun/tap
+// or an IP tunnel or overlay such as GRE or VXLAN
+func (im *InterfaceMonitor) IsTunnelInterface(interfaceName string) bool {
+	return tunnelKind(interfaceName) != ""
+}
+
+// CheckTunnelStatus reports the peers of a WireGuard interface, or whether
+// a process is attached to a tun/tap device; a tunnel whose daemon hasn't
+// started yet, or whose peer hasn't answered, has an interface but passes no
+// traffic
+func (im *InterfaceMonitor) CheckTunnelStatus(interfaceName string) (*TunnelStatus, error) {
+	status := &TunnelStatus{Name: interfaceName, Kind: tunnelKind(interfaceName)}
+	
+	var err error
+	switch status.Kind {
+	case "wireguard":
+		status.Peers, err = wireGuardPeers(interfaceName)
+	case "tun", "tap":
+		status.Attached = tunAttached(interfaceName)
+	case "":
+		err = fmt.Errorf("%s is not a tunnel", interfaceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// tunnelKind returns the kind of tunnel an interface is, or "" for none
+func tunnelKind(interfaceName string) string {
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil || linkKinds[link.Type()] != Tunnel {
+		return ""
+	}
+	if link.Type() != "tuntap" {
+		return link.Type()
+	}
+	
+	// IFF_TAP is 0x0002
+	flags := readSysfs(fmt.Sprintf("/sys/class/net/%s/tun_flags", interfaceName))
+	var value uint
+	if _, err := fmt.Sscanf(flags, "0x%x", &value); err == nil && value&0x0002 != 0 {
+		return "tap"
+	}
+	return "tun"
+}
+
+// wireGuardPeers queries the WireGuard module over generic netlink for the
+// peers of an interface and their last handshake
+func wireGuardPeers(interfaceName string) ([]WireGuardPeer, error) {
+	family, err := netlink.GenlFamilyGet(wgGenlName)
+	if err != nil {
+		return nil, fmt.Errorf("wireguard netlink family not available: %w", err)
+	}
+	
+	req := nl.NewNetlinkRequest(int(family.ID), syscall.NLM_F_DUMP)
+	req.AddData(&nl.Genlmsg{Command: wgCmdGetDevice, Version: wgGenlVersion})
+	req.AddData(nl.NewRtAttr(wgDeviceAttrIfname, nl.ZeroTerminated(interfaceName)))
+	msgs, err := req.Execute(syscall.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wireguard device %s: %w", interfaceName, err)
+	}
+	
+	// Devices with many peers are split across several messages
+	var peers []WireGuardPeer
+	for _, msg := range msgs {
+		attrs, err := nl.ParseRouteAttr(msg[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse wireguard device %s: %w", interfaceName, err)
+		}
+		for _, attr := range attrs {
+			if attr.Attr.Type&nlaTypeMask != wgDeviceAttrPeers {
+				continue
+			}
+			peerList, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse wireguard peers of %s: %w", interfaceName, err)
+			}
+			for _, peerAttr := range peerList {
+				peer, err := parseWireGuardPeer(peerAttr.Value)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse wireguard peer of %s: %w", interfaceName, err)
+				}
+				peers = append(peers, peer)
+			}
+		}
+	}
+	return peers, nil
+}
+
+// parseWireGuardPeer decodes the attributes of one peer
+func parseWireGuardPeer(b []byte) (WireGuardPeer, error) {
+	var peer WireGuardPeer
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return peer, err
+	}
+	
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nlaTypeMask {
+		case wgPeerAttrPublicKey:
+			peer.PublicKey = base64.StdEncoding.EncodeToString(attr.Value)
+		case wgPeerAttrEndpoint:
+			peer.Endpoint = parseSockaddr(attr.Value)
+		case wgPeerAttrHandshake:
+			// struct __kernel_timespec, zero when there hasn't been one
+			if len(attr.Value) >= 16 {
+				sec := int64(nl.NativeEndian().Uint64(attr.Value[0:]))
+				nsec := int64(nl.NativeEndian().Uint64(attr.Value[8:]))
+				if sec != 0 || nsec != 0 {
+					peer.LastHandshake = time.Unix(sec, nsec)
+				}
+			}
+		}
+	}
+	return peer, nil
+}
+
+// parseSockaddr formats a struct sockaddr_in or sockaddr_in6 as host:port
+func parseSockaddr(b []byte) string {
+	if len(b) < 4 {
+		return ""
+	}
+	port := int(binary.BigEndian.Uint16(b[2:]))
+	switch nl.NativeEndian().Uint16(b) {
+	case syscall.AF_INET:
+		if len(b) >= 8 {
+			return net.JoinHostPort(net.IP(b[4:8]).String(), fmt.Sprint(port))
+		}
+	case syscall.AF_INET6:
+		if len(b) >= 24 {
+			return net.JoinHostPort(net.IP(b[8:24]).String(), fmt.Sprint(port))
+		}
+	}
+	return ""
+}
+
+// tunAttached reports whether a process has a tun/tap device open: the
+// kernel only turns the carrier on while a descriptor is attached to it
+func tunAttached(interfaceName string) bool {
+	return readSysfs(fmt.Sprintf("/sys/class/net/%s/carrier", interfaceName)) == "1"
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/logger"
+)
+
+// Hooks runs local commands on readiness transitions, e.g. to start
+// application warmup as soon as the network is ready
+type Hooks struct {
+	commands map[string][]string  // Command arguments keyed by event type
+	timeout  time.Duration
+	logger   *logger.Logger
+	wg       sync.WaitGroup
+}
+
+// NewHooks creates hooks for the ready, degraded and timeout transitions;
+// empty commands are skipped. Commands are split on spaces and run without a shell.
+func NewHooks(onReady, onDegraded, onTimeout string, timeout time.Duration, log *logger.Logger) *Hooks {
+	commands := make(map[string][]string)
+	if args := strings.Fields(onReady); len(args) > 0 {
+		commands[EventNetworkReady] = args
+		commands[EventUnblocked] = args
+	}
+	if args := strings.Fields(onDegraded); len(args) > 0 {
+		commands[EventNetworkLost] = args
+	}
+	if args := strings.Fields(onTimeout); len(args) > 0 {
+		commands[EventTimeout] = args
+	}
+	
+	return &Hooks{
+		commands: commands,
+		timeout:  timeout,
+		logger:   log,
+	}
+}
+
+// Run starts the hook for the event in the background, if one is configured.
+// The event is passed as JSON on stdin and summarized in NETWORK_MONITOR_* variables;
+// cancelling ctx kills the command.
+func (h *Hooks) Run(ctx context.Context, eventType string, states map[string]bool) {
+	args, ok := h.commands[eventType]
+	if !ok {
+		return
+	}
+	
+	hostname, _ := os.Hostname()
+	event := Event{
+		Hostname:  hostname,
+		Event:     eventType,
+		Timestamp: time.Now(),
+		States:    states,
+	}
+	
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		
+		if err := h.run(ctx, args, event); err != nil {
+			h.logger.Logf("Hook: %s command %s FAILED - %v", eventType, args[0], err)
+			return
+		}
+		h.logger.Logf("Hook: %s command %s completed", eventType, args[0])
+	}()
+}
+
+// Close waits for running hooks, bounded by the hook timeout
+func (h *Hooks) Close() {
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	
+	select {
+	case <-done:
+	case <-time.After(h.timeout + time.Second):
+		h.logger.Log("Hook: giving up on running commands")
+	}
+}
+
+// run executes a single hook command and logs its output
+func (h *Hooks) run(ctx context.Context, args []string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	
+	var failed []string
+	for name, state := range event.States {
+		if !state {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"NETWORK_MONITOR_EVENT="+event.Event,
+		"NETWORK_MONITOR_FAILED_CHECKS="+strings.Join(failed, " "),
+	)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.WaitDelay = time.Second  // Don't wait on children of a killed hook that still hold its output open
+	
+	output, err := cmd.CombinedOutput()
+	if text := strings.TrimSpace(string(output)); text != "" {
+		h.logger.Logf("Hook: %s output: %s", event.Event, text)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", h.timeout)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/logger"
+)
+
+// Event types sent to the webhook and hooks
+const (
+	EventNetworkReady = "network-ready"
+	EventNetworkLost  = "network-lost"
+	EventUnblocked    = "boot-unblocked"
+	EventTimeout      = "timeout"
+)
+
+// Event is the JSON payload posted to the webhook and passed to hooks
+type Event struct {
+	Hostname  string          `json:"hostname"`
+	Event     string          `json:"event"`
+	Timestamp time.Time       `json:"timestamp"`
+	States    map[string]bool `json:"states"`
+}
+
+// Webhook posts readiness transitions to an external HTTP endpoint
+type Webhook struct {
+	url      string
+	timeout  time.Duration
+	attempts int
+	client   *http.Client
+	logger   *logger.Logger
+	wg       sync.WaitGroup
+}
+
+// NewWebhook creates a new webhook notifier
+func NewWebhook(url string, timeout time.Duration, log *logger.Logger) *Webhook {
+	return &Webhook{
+		url:      url,
+		timeout:  timeout,
+		attempts: 3,
+		client:   &http.Client{},
+		logger:   log,
+	}
+}
+
+// Send posts the event in the background so it never stalls the monitor loop;
+// cancelling ctx abandons the delivery, including any retries
+func (w *Webhook) Send(ctx context.Context, eventType string, states map[string]bool) {
+	hostname, _ := os.Hostname()
+	event := Event{
+		Hostname:  hostname,
+		Event:     eventType,
+		Timestamp: time.Now(),
+		States:    states,
+	}
+	
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		
+		var err error
+		for attempt := 1; attempt <= w.attempts; attempt++ {
+			if err = w.post(ctx, event); err == nil {
+				w.logger.Logf("Webhook: %s event delivered", eventType)
+				return
+			}
+			if ctx.Err() != nil {
+				w.logger.Logf("Webhook: %s event abandoned - shutting down", eventType)
+				return
+			}
+			if attempt < w.attempts {
+				select {
+				case <-ctx.Done():
+					w.logger.Logf("Webhook: %s event abandoned - shutting down", eventType)
+					return
+				case <-time.After(time.Duration(attempt) * time.Second):
+				}
+			}
+		}
+		w.logger.Logf("Webhook: %s event FAILED after %d attempts - %v", eventType, w.attempts, err)
+	}()
+}
+
+// Close waits for in-flight deliveries, bounded by the worst-case retry time
+func (w *Webhook) Close() {
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	
+	select {
+	case <-done:
+	case <-time.After(time.Duration(w.attempts) * (w.timeout + time.Second)):
+		w.logger.Log("Webhook: giving up on pending deliveries")
+	}
+}
+
+// post performs a single delivery attempt
+func (w *Webhook) post(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	
+	return nil
+}
@@ -0,0 +1,202 @@
+package readiness
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed boolean readiness condition over check names, e.g.
+// "interfaces && gateway && (dns || nm_connectivity)"
+type Expression struct {
+	source string
+	root   node
+}
+
+// node is a single operator or check name in the expression tree
+type node interface {
+	eval(states map[string]bool) bool
+	names(out []string) []string
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ operand node }
+type nameNode struct{ name string }
+
+func (n andNode) eval(states map[string]bool) bool { return n.left.eval(states) && n.right.eval(states) }
+func (n orNode) eval(states map[string]bool) bool  { return n.left.eval(states) || n.right.eval(states) }
+func (n notNode) eval(states map[string]bool) bool { return !n.operand.eval(states) }
+func (n nameNode) eval(states map[string]bool) bool {
+	return states[n.name] // Checks that aren't enabled count as failing
+}
+
+func (n andNode) names(out []string) []string { return n.right.names(n.left.names(out)) }
+func (n orNode) names(out []string) []string  { return n.right.names(n.left.names(out)) }
+func (n notNode) names(out []string) []string { return n.operand.names(out) }
+func (n nameNode) names(out []string) []string { return append(out, n.name) }
+
+// Parse parses an expression of check names combined with &&, ||, ! and parentheses
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q after complete expression", p.tokens[p.pos])
+	}
+	
+	return &Expression{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression against the current check states
+func (e *Expression) Eval(states map[string]bool) bool {
+	return e.root.eval(states)
+}
+
+// Names returns the check names referenced by the expression
+func (e *Expression) Names() []string {
+	return e.root.names(nil)
+}
+
+// String returns the expression as written
+func (e *Expression) String() string {
+	return e.source
+}
+
+// tokenize splits an expression into operators, parentheses and check names
+func tokenize(source string) ([]string, error) {
+	var tokens []string
+	runes := []rune(source)
+	
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '!':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("expected %c%c at position %d", r, r, i+1)
+			}
+			tokens = append(tokens, string([]rune{r, r}))
+			i += 2
+		case isNameRune(r):
+			start := i
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+		}
+	}
+	
+	return tokens, nil
+}
+
+// isNameRune reports whether r can appear in a check name ("nm_connectivity", "exec:check-mount#2")
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-:.#", r)
+}
+
+// parser is a recursive descent parser; ! binds tighter than &&, which binds tighter than ||
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "!":
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case ")", "&&", "||":
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+	
+	p.pos++
+	name := CanonicalName(token)
+	if name == "exec:" {
+		return nil, fmt.Errorf("exec: needs a command name, e.g. exec:check-mount")
+	}
+	return nameNode{name}, nil
+}
+
+// CanonicalName returns the form check names are looked up by: built-in
+// check names are case-insensitive and lower-cased, while the command part
+// of an "exec:" name is kept as written
+func CanonicalName(name string) string {
+	if prefix, command, ok := strings.Cut(name, ":"); ok && strings.EqualFold(prefix, "exec") {
+		return "exec:" + command
+	}
+	return strings.ToLower(name)
+}
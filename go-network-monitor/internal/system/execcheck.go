@@ -0,0 +1,74 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExecCheck runs a site-specific readiness command; a zero exit status means passing
+type ExecCheck struct {
+	Name    string
+	Command []string
+	timeout time.Duration
+}
+
+// ExecResult holds the outcome of a single command run
+type ExecResult struct {
+	Passed   bool
+	ExitCode int
+	Output   string
+}
+
+// NewExecCheck creates a check from a command line of space-separated arguments
+func NewExecCheck(command string, timeout time.Duration) (*ExecCheck, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	
+	return &ExecCheck{
+		Name:    filepath.Base(args[0]),
+		Command: args,
+		timeout: timeout,
+	}, nil
+}
+
+// Run executes the command with the given extra environment variables; an
+// error is returned only when the command couldn't be run or timed out
+func (ec *ExecCheck) Run(ctx context.Context, env map[string]string) (*ExecResult, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, ec.timeout)
+	defer cancel()
+	
+	cmd := exec.CommandContext(cmdCtx, ec.Command[0], ec.Command[1:]...)
+	cmd.Env = os.Environ()
+	for key, val := range env {
+		cmd.Env = append(cmd.Env, key+"="+val)
+	}
+	
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.WaitDelay = time.Second  // Don't wait on children of a killed command that still hold its output open
+	
+	err := cmd.Run()
+	result := &ExecResult{Output: strings.TrimSpace(stdout.String())}
+	
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("timed out after %s", ec.timeout)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	
+	result.Passed = true
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+	
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// ErrResolvedUnavailable is returned when systemd-resolved isn't running
+var ErrResolvedUnavailable = errors.New("systemd-resolved not available")
+
+// resolvedObject is the D-Bus object of the systemd-resolved manager
+const resolvedObject = "/org/freedesktop/resolve1"
+
+// ResolvedServer is a DNS server systemd-resolved has been configured with
+type ResolvedServer struct {
+	Interface    string  // Empty for global servers
+	Address      net.IP
+	DefaultRoute bool    // Used for names outside any link's routing domains
+}
+
+// ResolvedStatus is the DNS configuration systemd-resolved is working with
+type ResolvedStatus struct {
+	Servers         []ResolvedServer  // From DHCP, NetworkManager, networkd or resolved.conf
+	CurrentServer   net.IP            // Global server in use, if any
+	DNSSEC          string            // "yes", "no" or "allow-downgrade"
+	DNSSECSupported bool              // Whether the current servers support DNSSEC
+}
+
+// resolvedServerEntry is the D-Bus form of a DNS server: interface index
+// (0 = global), address family and address
+type resolvedServerEntry struct {
+	Ifindex int32
+	Family  int32
+	Address []byte
+}
+
+// ResolvedMonitor reads the DNS configuration of systemd-resolved over D-Bus
+type ResolvedMonitor struct {
+	timeout time.Duration
+}
+
+// NewResolvedMonitor creates a new systemd-resolved monitor
+func NewResolvedMonitor(timeout time.Duration) *ResolvedMonitor {
+	return &ResolvedMonitor{timeout: timeout}
+}
+
+// Status returns the DNS servers systemd-resolved knows about, global and
+// per link, along with its DNSSEC mode
+func (rm *ResolvedMonitor) Status(ctx context.Context) (*ResolvedStatus, error) {
+	conn, err := godbus.SystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to connect to system bus: %v", ErrResolvedUnavailable, err)
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, rm.timeout)
+	defer cancel()
+	
+	manager := conn.Object("org.freedesktop.resolve1", resolvedObject)
+	
+	var entries []resolvedServerEntry
+	if err := getProperty(ctx, manager, "org.freedesktop.resolve1.Manager", "DNS", &entries); err != nil {
+		var dbusErr godbus.Error
+		if errors.As(err, &dbusErr) && (dbusErr.Name == "org.freedesktop.DBus.Error.ServiceUnknown" ||
+			dbusErr.Name == "org.freedesktop.DBus.Error.NameHasNoOwner") {
+			return nil, fmt.Errorf("%w: %v", ErrResolvedUnavailable, dbusErr)
+		}
+		return nil, err
+	}
+	
+	status := &ResolvedStatus{}
+	if err := getProperty(ctx, manager, "org.freedesktop.resolve1.Manager", "DNSSEC", &status.DNSSEC); err != nil {
+		return nil, err
+	}
+	if err := getProperty(ctx, manager, "org.freedesktop.resolve1.Manager", "DNSSECSupported", &status.DNSSECSupported); err != nil {
+		return nil, err
+	}
+	
+	var current resolvedServerEntry
+	if err := getProperty(ctx, manager, "org.freedesktop.resolve1.Manager", "CurrentDNSServer", &current); err == nil && len(current.Address) > 0 {
+		status.CurrentServer = net.IP(current.Address)
+	}
+	
+	// Global servers apply to every name; link servers only when the link
+	// is a default route for DNS
+	defaultRoute := make(map[int32]bool)
+	for _, entry := range entries {
+		server := ResolvedServer{Address: net.IP(entry.Address), DefaultRoute: true}
+		if entry.Ifindex != 0 {
+			isDefault, ok := defaultRoute[entry.Ifindex]
+			if !ok {
+				isDefault = rm.linkDefaultRoute(ctx, conn, manager, entry.Ifindex)
+				defaultRoute[entry.Ifindex] = isDefault
+			}
+			server.Interface = linkName(entry.Ifindex)
+			server.DefaultRoute = isDefault
+		}
+		status.Servers = append(status.Servers, server)
+	}
+	
+	return status, nil
+}
+
+// linkDefaultRoute reports whether resolved sends queries for names outside
+// any routing domain to the link's servers. Versions without the
+// DefaultRoute property use every link with servers.
+func (rm *ResolvedMonitor) linkDefaultRoute(ctx context.Context, conn *godbus.Conn, manager godbus.BusObject, ifindex int32) bool {
+	var path godbus.ObjectPath
+	if err := manager.CallWithContext(ctx, "org.freedesktop.resolve1.Manager.GetLink", 0, ifindex).Store(&path); err != nil {
+		return true
+	}
+	
+	var isDefault bool
+	if err := getProperty(ctx, conn.Object("org.freedesktop.resolve1", path), "org.freedesktop.resolve1.Link", "DefaultRoute", &isDefault); err != nil {
+		return true
+	}
+	return isDefault
+}
+
+// getProperty reads a single D-Bus property into value
+func getProperty(ctx context.Context, obj godbus.BusObject, iface, name string, value interface{}) error {
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, iface, name).Store(value); err != nil {
+		return fmt.Errorf("failed to query %s: %w", name, err)
+	}
+	return nil
+}
+
+// linkName returns the interface name for an interface index, or the index
+// itself when the interface is gone
+func linkName(ifindex int32) string {
+	if iface, err := net.InterfaceByIndex(int(ifindex)); err == nil {
+		return iface.Name
+	}
+	return fmt.Sprintf("ifindex %d", ifindex)
+}
@@ -0,0 +1,85 @@
+package system
+
+import (
+	"net"
+	"os"
+	"time"
+	
+	"github.com/coreos/go-systemd/v22/daemon"
+)
+
+// Notifier reports service state to systemd via sd_notify for units with
+// Type=notify; all calls are no-ops when NOTIFY_SOCKET isn't set
+type Notifier struct {
+	socket   string
+	watchdog time.Duration  // WatchdogSec of the unit (0 = watchdog disabled)
+	ready    bool
+}
+
+// NewNotifier creates a notifier from NOTIFY_SOCKET and WATCHDOG_USEC. The
+// variables are removed from the environment so that commands we run
+// (systemctl, ping, exec checks) don't send notifications on our behalf.
+func NewNotifier() *Notifier {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	watchdog, err := daemon.SdWatchdogEnabled(true)
+	if err != nil {
+		watchdog = 0
+	}
+	os.Unsetenv("NOTIFY_SOCKET")
+	return &Notifier{socket: socket, watchdog: watchdog}
+}
+
+// Enabled reports whether systemd is listening for notifications
+func (n *Notifier) Enabled() bool {
+	return n.socket != ""
+}
+
+// Ready tells systemd the service has finished starting up (READY=1);
+// only the first call is sent
+func (n *Notifier) Ready() error {
+	if !n.Enabled() || n.ready {
+		return nil
+	}
+	n.ready = true
+	return n.send(daemon.SdNotifyReady)
+}
+
+// Status sets the free-form status line shown by systemctl status
+func (n *Notifier) Status(status string) error {
+	return n.send("STATUS=" + status)
+}
+
+// WatchdogInterval returns the unit's watchdog timeout; keep-alives must be
+// sent more often than this. Zero means the watchdog is disabled.
+func (n *Notifier) WatchdogInterval() time.Duration {
+	if !n.Enabled() {
+		return 0
+	}
+	return n.watchdog
+}
+
+// Watchdog sends a watchdog keep-alive (WATCHDOG=1)
+func (n *Notifier) Watchdog() error {
+	return n.send(daemon.SdNotifyWatchdog)
+}
+
+// Stopping tells systemd the service is shutting down
+func (n *Notifier) Stopping() error {
+	return n.send(daemon.SdNotifyStopping)
+}
+
+// send writes a single state datagram to the notification socket
+func (n *Notifier) send(state string) error {
+	if !n.Enabled() {
+		return nil
+	}
+	
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	
+	_, err = conn.Write([]byte(state))
+	return err
+}
@@ -0,0 +1,133 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SystemctlMonitor handles systemd service monitoring by shelling out to
+// systemctl, for use when the D-Bus connection is unavailable
+type SystemctlMonitor struct {
+	path    string
+	timeout time.Duration
+}
+
+// NewSystemctlMonitor creates a new systemctl-based service monitor
+func NewSystemctlMonitor(timeout time.Duration) (*SystemctlMonitor, error) {
+	path, err := exec.LookPath("systemctl")
+	if err != nil {
+		return nil, fmt.Errorf("systemctl not available: %w", err)
+	}
+	
+	return &SystemctlMonitor{path: path, timeout: timeout}, nil
+}
+
+// Backend returns the name of this backend
+func (sm *SystemctlMonitor) Backend() string {
+	return "systemctl"
+}
+
+// Close is a no-op; there is no connection to release
+func (sm *SystemctlMonitor) Close() {}
+
+// GetEnabledServices returns the list of enabled services from the given service list
+func (sm *SystemctlMonitor) GetEnabledServices(ctx context.Context, serviceNames []string) ([]string, error) {
+	var enabledServices []string
+	
+	for _, serviceName := range serviceNames {
+		properties, err := sm.showUnit(ctx, serviceName)
+		if err != nil {
+			continue // Service not found, skip
+		}
+		
+		// Check if service is loaded and enabled
+		switch properties["LoadState"] {
+		case "loaded", "enabled", "enabled-runtime", "static", "generated", "indirect":
+			enabledServices = append(enabledServices, serviceName)
+		}
+	}
+	
+	return enabledServices, nil
+}
+
+// CheckServicesStatus checks the status of multiple services in batch
+func (sm *SystemctlMonitor) CheckServicesStatus(ctx context.Context, serviceNames []string) (map[string]*ServiceStatus, error) {
+	results := make(map[string]*ServiceStatus)
+	
+	type result struct {
+		name   string
+		status *ServiceStatus
+	}
+	
+	resultChan := make(chan result, len(serviceNames))
+	
+	for _, serviceName := range serviceNames {
+		go func(name string) {
+			status, _ := sm.CheckServiceStatus(ctx, name)
+			resultChan <- result{name: name, status: status}
+		}(serviceName)
+	}
+	
+	// Collect results
+	for i := 0; i < len(serviceNames); i++ {
+		res := <-resultChan
+		results[res.name] = res.status
+	}
+	
+	return results, nil
+}
+
+// CheckServiceStatus checks the status of a single service
+func (sm *SystemctlMonitor) CheckServiceStatus(ctx context.Context, serviceName string) (*ServiceStatus, error) {
+	properties, err := sm.showUnit(ctx, serviceName)
+	if err != nil {
+		return &ServiceStatus{
+			Name:        serviceName,
+			ActiveState: ServiceUnknown,
+			Available:   false,
+		}, nil
+	}
+	
+	status := &ServiceStatus{
+		Name:      serviceName,
+		Available: true,
+		LoadState: properties["LoadState"],
+		SubState:  properties["SubState"],
+	}
+	
+	if activeState := properties["ActiveState"]; activeState != "" {
+		status.ActiveState = ServiceState(activeState)
+	} else {
+		status.ActiveState = ServiceUnknown
+	}
+	
+	return status, nil
+}
+
+// showUnit runs systemctl show and returns the requested unit properties
+func (sm *SystemctlMonitor) showUnit(ctx context.Context, serviceName string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, sm.timeout)
+	defer cancel()
+	
+	cmd := exec.CommandContext(ctx, sm.path, "show", serviceName,
+		"--property=ActiveState,LoadState,SubState")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl show %s failed: %w", serviceName, err)
+	}
+	
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			properties[key] = value
+		}
+	}
+	
+	return properties, nil
+}
@@ -29,19 +29,52 @@ type ServiceStatus struct {
 	Available   bool
 }
 
+// ServiceMonitor is implemented by each service status backend so callers
+// don't need to know whether D-Bus or systemctl is in use
+type ServiceMonitor interface {
+	// Backend returns a short name for the backend in use
+	Backend() string
+	GetEnabledServices(ctx context.Context, serviceNames []string) ([]string, error)
+	CheckServicesStatus(ctx context.Context, serviceNames []string) (map[string]*ServiceStatus, error)
+	CheckServiceStatus(ctx context.Context, serviceName string) (*ServiceStatus, error)
+	Close()
+}
+
+// NewServiceMonitor connects to systemd over D-Bus, falling back to
+// shelling out to systemctl when the D-Bus connection is not available
+func NewServiceMonitor(timeout time.Duration) (ServiceMonitor, error) {
+	systemdMonitor, err := NewSystemdMonitor(timeout)
+	if err == nil {
+		return systemdMonitor, nil
+	}
+	
+	systemctlMonitor, fallbackErr := NewSystemctlMonitor(timeout)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%v; %v", err, fallbackErr)
+	}
+	
+	return systemctlMonitor, nil
+}
+
 // SystemdMonitor handles systemd service monitoring
 type SystemdMonitor struct {
-	conn *dbus.Conn
+	conn    *dbus.Conn
+	timeout time.Duration
 }
 
 // NewSystemdMonitor creates a new systemd monitor
-func NewSystemdMonitor() (*SystemdMonitor, error) {
+func NewSystemdMonitor(timeout time.Duration) (*SystemdMonitor, error) {
 	conn, err := dbus.NewSystemdConnectionContext(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to systemd: %w", err)
 	}
 	
-	return &SystemdMonitor{conn: conn}, nil
+	return &SystemdMonitor{conn: conn, timeout: timeout}, nil
+}
+
+// Backend returns the name of this backend
+func (sm *SystemdMonitor) Backend() string {
+	return "D-Bus"
 }
 
 // Close closes the systemd connection
@@ -52,14 +85,16 @@ func (sm *SystemdMonitor) Close() {
 }
 
 // GetEnabledServices returns the list of enabled services from the given service list
-func (sm *SystemdMonitor) GetEnabledServices(serviceNames []string) ([]string, error) {
+func (sm *SystemdMonitor) GetEnabledServices(ctx context.Context, serviceNames []string) ([]string, error) {
 	var enabledServices []string
 	
 	for _, serviceName := range serviceNames {
+		queryCtx, cancel := context.WithTimeout(ctx, sm.timeout)
 		unitStatus, err := sm.conn.GetUnitPropertiesContext(
-			context.Background(),
+			queryCtx,
 			serviceName,
 		)
+		cancel()
 		if err != nil {
 			continue // Service not found, skip
 		}
@@ -80,7 +115,7 @@ func (sm *SystemdMonitor) GetEnabledServices(serviceNames []string) ([]string, e
 }
 
 // CheckServicesStatus checks the status of multiple services in batch
-func (sm *SystemdMonitor) CheckServicesStatus(serviceNames []string) (map[string]*ServiceStatus, error) {
+func (sm *SystemdMonitor) CheckServicesStatus(ctx context.Context, serviceNames []string) (map[string]*ServiceStatus, error) {
 	results := make(map[string]*ServiceStatus)
 	
 	// Get all service statuses in parallel using goroutines
@@ -94,7 +129,7 @@ func (sm *SystemdMonitor) CheckServicesStatus(serviceNames []string) (map[string
 	
 	for _, serviceName := range serviceNames {
 		go func(name string) {
-			status, err := sm.checkSingleServiceStatus(name)
+			status, err := sm.checkSingleServiceStatus(ctx, name)
 			resultChan <- result{name: name, status: status, err: err}
 		}(serviceName)
 	}
@@ -111,13 +146,13 @@ func (sm *SystemdMonitor) CheckServicesStatus(serviceNames []string) (map[string
 }
 
 // CheckServiceStatus checks the status of a single service
-func (sm *SystemdMonitor) CheckServiceStatus(serviceName string) (*ServiceStatus, error) {
-	return sm.checkSingleServiceStatus(serviceName)
+func (sm *SystemdMonitor) CheckServiceStatus(ctx context.Context, serviceName string) (*ServiceStatus, error) {
+	return sm.checkSingleServiceStatus(ctx, serviceName)
 }
 
 // checkSingleServiceStatus performs the actual status check for a single service
-func (sm *SystemdMonitor) checkSingleServiceStatus(serviceName string) (*ServiceStatus, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (sm *SystemdMonitor) checkSingleServiceStatus(ctx context.Context, serviceName string) (*ServiceStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, sm.timeout)
 	defer cancel()
 	
 	unitStatus, err := sm.conn.GetUnitPropertiesContext(
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+	
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// ErrTimeSyncUnavailable is returned when no NTP service is available to synchronize the clock
+var ErrTimeSyncUnavailable = errors.New("no time synchronization service available")
+
+// TimeSyncMonitor checks whether the system clock is synchronized via
+// systemd-timedated, which reflects timesyncd, chrony and ntpd alike
+type TimeSyncMonitor struct {
+	timeout time.Duration
+}
+
+// NewTimeSyncMonitor creates a new time synchronization monitor
+func NewTimeSyncMonitor(timeout time.Duration) *TimeSyncMonitor {
+	return &TimeSyncMonitor{timeout: timeout}
+}
+
+// CheckSynchronized reports whether the clock is NTP synchronized. It
+// queries timedated over D-Bus, falling back to timedatectl.
+func (tm *TimeSyncMonitor) CheckSynchronized(ctx context.Context) (bool, error) {
+	canNTP, synchronized, err := tm.queryDBus(ctx)
+	if err != nil {
+		canNTP, synchronized, err = tm.queryTimedatectl(ctx)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrTimeSyncUnavailable, err)
+		}
+	}
+	
+	if !canNTP {
+		return false, ErrTimeSyncUnavailable
+	}
+	
+	return synchronized, nil
+}
+
+// queryDBus reads the CanNTP and NTPSynchronized properties of timedated
+func (tm *TimeSyncMonitor) queryDBus(ctx context.Context) (bool, bool, error) {
+	conn, err := godbus.SystemBus()
+	if err != nil {
+		return false, false, fmt.Errorf("failed to connect to system bus: %w", err)
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, tm.timeout)
+	defer cancel()
+	
+	obj := conn.Object("org.freedesktop.timedate1", "/org/freedesktop/timedate1")
+	
+	var canNTP, synchronized bool
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0,
+		"org.freedesktop.timedate1", "CanNTP").Store(&canNTP); err != nil {
+		return false, false, fmt.Errorf("failed to query CanNTP: %w", err)
+	}
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0,
+		"org.freedesktop.timedate1", "NTPSynchronized").Store(&synchronized); err != nil {
+		return false, false, fmt.Errorf("failed to query NTPSynchronized: %w", err)
+	}
+	
+	return canNTP, synchronized, nil
+}
+
+// queryTimedatectl reads the same properties via timedatectl show
+func (tm *TimeSyncMonitor) queryTimedatectl(ctx context.Context) (bool, bool, error) {
+	if _, err := exec.LookPath("timedatectl"); err != nil {
+		return false, false, fmt.Errorf("timedatectl not available")
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, tm.timeout)
+	defer cancel()
+	
+	cmd := exec.CommandContext(ctx, "timedatectl", "show", "-p", "CanNTP", "-p", "NTPSynchronized")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, false, fmt.Errorf("timedatectl show failed: %w", err)
+	}
+	
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			properties[key] = value
+		}
+	}
+	
+	return properties["CanNTP"] == "yes", properties["NTPSynchronized"] == "yes", nil
+}
@@ -0,0 +1,112 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+	
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// ErrWPASupplicantUnavailable is returned when wpa_supplicant isn't running
+// or doesn't manage the interface
+var ErrWPASupplicantUnavailable = errors.New("wpa_supplicant not available")
+
+const (
+	wpaService   = "fi.w1.wpa_supplicant1"
+	wpaObject    = "/fi/w1/wpa_supplicant1"
+	wpaInterface = "fi.w1.wpa_supplicant1.Interface"
+	wpaBSS       = "fi.w1.wpa_supplicant1.BSS"
+)
+
+// WirelessStatus is the association state of a wireless interface as
+// wpa_supplicant sees it
+type WirelessStatus struct {
+	Interface string
+	State     string  // wpa_supplicant state, "completed" once associated and authenticated
+	SSID      string  // Empty when not associated
+	BSSID     string
+	Signal    int     // dBm, 0 when unknown
+}
+
+// Associated reports whether the interface has completed association and
+// key negotiation
+func (ws *WirelessStatus) Associated() bool {
+	return ws.State == "completed"
+}
+
+// WirelessMonitor reads wireless association state from wpa_supplicant over D-Bus
+type WirelessMonitor struct {
+	timeout time.Duration
+}
+
+// NewWirelessMonitor creates a new wpa_supplicant monitor
+func NewWirelessMonitor(timeout time.Duration) *WirelessMonitor {
+	return &WirelessMonitor{timeout: timeout}
+}
+
+// Status returns the wpa_supplicant state of an interface and, once
+// associated, the SSID and signal strength of the access point
+func (wm *WirelessMonitor) Status(ctx context.Context, interfaceName string) (*WirelessStatus, error) {
+	conn, err := godbus.SystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to connect to system bus: %v", ErrWPASupplicantUnavailable, err)
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, wm.timeout)
+	defer cancel()
+	
+	var path godbus.ObjectPath
+	if err := conn.Object(wpaService, wpaObject).CallWithContext(ctx, wpaService+".GetInterface", 0, interfaceName).Store(&path); err != nil {
+		var dbusErr godbus.Error
+		if errors.As(err, &dbusErr) && (dbusErr.Name == "org.freedesktop.DBus.Error.ServiceUnknown" ||
+			dbusErr.Name == "org.freedesktop.DBus.Error.NameHasNoOwner" ||
+			dbusErr.Name == wpaService+".InterfaceUnknown") {
+			return nil, fmt.Errorf("%w: %v", ErrWPASupplicantUnavailable, dbusErr)
+		}
+		return nil, fmt.Errorf("failed to look up %s in wpa_supplicant: %w", interfaceName, err)
+	}
+	
+	iface := conn.Object(wpaService, path)
+	status := &WirelessStatus{Interface: interfaceName}
+	if err := getProperty(ctx, iface, wpaInterface, "State", &status.State); err != nil {
+		return nil, err
+	}
+	
+	var bssPath godbus.ObjectPath
+	if err := getProperty(ctx, iface, wpaInterface, "CurrentBSS", &bssPath); err != nil {
+		return nil, err
+	}
+	if bssPath == "" || bssPath == "/" {
+		return status, nil // Not associated
+	}
+	
+	bss := conn.Object(wpaService, bssPath)
+	var ssid, bssid []byte
+	if err := getProperty(ctx, bss, wpaBSS, "SSID", &ssid); err != nil {
+		return nil, err
+	}
+	status.SSID = string(ssid)
+	if err := getProperty(ctx, bss, wpaBSS, "BSSID", &bssid); err == nil && len(bssid) == 6 {
+		status.BSSID = net.HardwareAddr(bssid).String()
+	}
+	
+	// SignalPoll asks the driver for the current RSSI; the BSS signal is
+	// from the last scan and can be minutes old
+	var poll map[string]godbus.Variant
+	if err := iface.CallWithContext(ctx, wpaInterface+".SignalPoll", 0).Store(&poll); err == nil {
+		if rssi, ok := poll["rssi"].Value().(int32); ok {
+			status.Signal = int(rssi)
+		}
+	}
+	if status.Signal == 0 {
+		var signal int16
+		if err := getProperty(ctx, bss, wpaBSS, "Signal", &signal); err == nil {
+			status.Signal = int(signal)
+		}
+	}
+	
+	return status, nil
+}
@@ -0,0 +1,75 @@
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build metadata, injected at build time with -ldflags "-X <module>/internal/version.Version=v1.2.0"
+// (see the Makefile); anything left empty is filled in from the Go build info where available
+var (
+	Version   = ""
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info holds the resolved version, git commit and build date
+type Info struct {
+	Version   string
+	Commit    string
+	BuildDate string
+	Modified  bool  // Built from a working tree with uncommitted changes
+}
+
+// Get returns the build metadata, preferring ldflags values over the
+// module version and VCS stamping recorded by the Go toolchain
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+	}
+	
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	} else if len(info.Commit) > 12 {
+		info.Commit = info.Commit[:12]
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	
+	return info
+}
+
+// String formats the build metadata for --version and the startup banner
+func (i Info) String() string {
+	commit := i.Commit
+	if i.Modified {
+		commit += "-dirty"
+	}
+	return fmt.Sprintf("%s (commit %s, built %s)", i.Version, commit, i.BuildDate)
+}
@@ -0,0 +1,171 @@
+// Package netready lets other Go programs wait for network readiness using the
+// same checks as the network-monitor binary, instead of shelling out to it.
+//
+//	mon, err := netready.New(netready.Options{ConfigFile: "/etc/network-monitor/config.yaml"})
+//	if err != nil {
+//		return err
+//	}
+//	defer mon.Close()
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+//	defer cancel()
+//	status, err := mon.WaitReady(ctx)
+package netready
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+	
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/config"
+	"github.com/samsyeung/network_startup_monitor_service/go-network-monitor/internal/monitor"
+)
+
+// Status is the outcome of a check cycle
+type Status struct {
+	Ready     bool
+	Checks    map[string]bool  // Per-check state keyed by check name, e.g. "gateway"
+	Timestamp time.Time
+}
+
+// Failing returns the sorted names of the checks that are currently failing
+func (s Status) Failing() []string {
+	var failing []string
+	for name, passed := range s.Checks {
+		if !passed {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
+// Options configures an embedded monitor
+type Options struct {
+	ConfigFile string         // network-monitor YAML config file (empty = built-in defaults)
+	ConfigDir  string         // Drop-in fragment directory (empty = conf.d next to ConfigFile, if present)
+	Profile    string         // Named profile from the config files to apply (empty = none)
+	Interval   time.Duration  // Time between check cycles (0 = sleep_interval from the config)
+	Log        io.Writer      // Destination for the check log lines (nil = discarded)
+}
+
+// Monitor runs the readiness checks on behalf of the embedding program. It
+// never takes the lock file, installs signal handlers or exits the process.
+// Only one of Watch, Changes or WaitReady may run at a time.
+type Monitor struct {
+	mon *monitor.Monitor
+}
+
+// New creates a monitor from the options. The config file, drop-in fragments
+// and profile are loaded as the binary loads them, minus the environment and
+// flags, and the configuration is validated the same way.
+func New(opts Options) (*Monitor, error) {
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		if err := cfg.LoadFromFile(opts.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+	dir := opts.ConfigDir
+	if dir == "" {
+		dir = config.FindConfigDir(nil, opts.ConfigFile)
+	}
+	if dir != "" {
+		if err := cfg.LoadFromDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Profile != "" {
+		if err := cfg.ApplyProfile(opts.Profile); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Interval > 0 {
+		cfg.SleepInterval = opts.Interval
+	}
+	
+	cfg.NoLogFile = true
+	cfg.Console = opts.Log
+	if cfg.Console == nil {
+		cfg.Console = io.Discard
+	}
+	
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	
+	mon, err := monitor.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Monitor{mon: mon}, nil
+}
+
+// Watch runs check cycles until ctx is done, calling fn after the first cycle
+// and after every cycle in which readiness or any check changed
+func (m *Monitor) Watch(ctx context.Context, fn func(Status)) error {
+	var last *Status
+	return m.mon.Poll(ctx, func(ready bool, states map[string]bool) {
+		status := Status{Ready: ready, Checks: states, Timestamp: time.Now()}
+		if last != nil && !changed(*last, status) {
+			return
+		}
+		last = &status
+		fn(status)
+	})
+}
+
+// Changes runs Watch in the background and delivers each changed status on
+// the returned channel, which is closed once ctx is done
+func (m *Monitor) Changes(ctx context.Context) <-chan Status {
+	ch := make(chan Status)
+	go func() {
+		defer close(ch)
+		m.Watch(ctx, func(status Status) {
+			select {
+			case ch <- status:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return ch
+}
+
+// WaitReady blocks until the network is ready, returning the ready status, or
+// until ctx is done, returning the last status seen and the context's error
+func (m *Monitor) WaitReady(ctx context.Context) (Status, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	
+	var last Status
+	err := m.Watch(ctx, func(status Status) {
+		last = status
+		if status.Ready {
+			cancel()
+		}
+	})
+	if last.Ready {
+		return last, nil
+	}
+	return last, err
+}
+
+// Close releases the monitor's D-Bus connection and other resources
+func (m *Monitor) Close() error {
+	return m.mon.Close()
+}
+
+// changed reports whether readiness or any check state differs between two statuses
+func changed(a, b Status) bool {
+	if a.Ready != b.Ready || len(a.Checks) != len(b.Checks) {
+		return true
+	}
+	for name, passed := range a.Checks {
+		if other, ok := b.Checks[name]; !ok || other != passed {
+			return true
+		}
+	}
+	return false
+}